@@ -2,283 +2,167 @@ package main
 
 import (
 	"context"
-	"encoding/json"
+	"flag"
 	"fmt"
+	"net/http"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 
-	"github.com/fatih/color"
-	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/anuramat/gothink/thinking"
+	"github.com/anuramat/gothink/view"
 	"github.com/mark3labs/mcp-go/server"
 )
 
-type ThoughtData struct {
-	Thought           string  `json:"thought"`
-	ThoughtNumber     int     `json:"thoughtNumber"`
-	TotalThoughts     int     `json:"totalThoughts"`
-	NextThoughtNeeded bool    `json:"nextThoughtNeeded"`
-	IsRevision        *bool   `json:"isRevision,omitempty"`
-	RevisesThought    *int    `json:"revisesThought,omitempty"`
-	BranchFromThought *int    `json:"branchFromThought,omitempty"`
-	BranchId          *string `json:"branchId,omitempty"`
-	NeedsMoreThoughts *bool   `json:"needsMoreThoughts,omitempty"`
-}
-
-type SequentialThinkingServer struct {
-	thoughtHistory        []ThoughtData
-	branches              map[string][]ThoughtData
-	disableThoughtLogging bool
-}
-
-func NewSequentialThinkingServer() *SequentialThinkingServer {
-	return &SequentialThinkingServer{
-		thoughtHistory:        make([]ThoughtData, 0),
-		branches:              make(map[string][]ThoughtData),
-		disableThoughtLogging: strings.ToLower(os.Getenv("DISABLE_THOUGHT_LOGGING")) == "true",
-	}
-}
-
-func (s *SequentialThinkingServer) validateThoughtData(args map[string]any) (*ThoughtData, error) {
-	data := &ThoughtData{}
-
-	thought, ok := args["thought"].(string)
-	if !ok || thought == "" {
-		return nil, fmt.Errorf("invalid thought: must be a string")
-	}
-	data.Thought = thought
-
-	if val, ok := args["thoughtNumber"]; !ok {
-		return nil, fmt.Errorf("invalid thoughtNumber: must be a number")
-	} else if num, ok := val.(float64); ok {
-		data.ThoughtNumber = int(num)
-	} else {
-		return nil, fmt.Errorf("invalid thoughtNumber: must be a number")
-	}
-
-	if val, ok := args["totalThoughts"]; !ok {
-		return nil, fmt.Errorf("invalid totalThoughts: must be a number")
-	} else if num, ok := val.(float64); ok {
-		data.TotalThoughts = int(num)
-	} else {
-		return nil, fmt.Errorf("invalid totalThoughts: must be a number")
-	}
-
-	if val, ok := args["nextThoughtNeeded"]; !ok {
-		return nil, fmt.Errorf("invalid nextThoughtNeeded: must be a boolean")
-	} else if b, ok := val.(bool); ok {
-		data.NextThoughtNeeded = b
-	} else {
-		return nil, fmt.Errorf("invalid nextThoughtNeeded: must be a boolean")
-	}
+type stringList []string
 
-	if val, ok := args["isRevision"]; ok {
-		if b, ok := val.(bool); ok {
-			data.IsRevision = &b
-		}
-	}
+func (l *stringList) String() string     { return strings.Join(*l, ",") }
+func (l *stringList) Set(v string) error { *l = append(*l, v); return nil }
 
-	if val, ok := args["revisesThought"]; ok {
-		if num, ok := val.(float64); ok {
-			thought := int(num)
-			data.RevisesThought = &thought
+func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "view":
+			viewSession(os.Args[2:])
+			return
+		case "tail":
+			tailSession(os.Args[2:])
+			return
 		}
 	}
 
-	if val, ok := args["branchFromThought"]; ok {
-		if num, ok := val.(float64); ok {
-			thought := int(num)
-			data.BranchFromThought = &thought
+	var bundles stringList
+	flag.Var(&bundles, "bundle", "serve an exported session bundle directory as read-only resources (repeatable)")
+	toolConfig := flag.String("tools", "", "JSON file enabling or disabling tools and tool groups, reread on SIGHUP")
+	session := flag.String("load", "", "start from a session document exported with render_session in the json format")
+	dashboard := flag.String("dashboard", "", "serve a live web dashboard of the session on this address, e.g. localhost:7777")
+	strict := flag.Bool("strict", false, "reject thoughts with unknown arguments, besides those with arguments of the wrong type or inconsistent references")
+	lenient := flag.Bool("lenient", false, "repair thoughts with arguments of the wrong type or inconsistent references where possible, with a warning, instead of rejecting them")
+	flag.Parse()
+
+	var opts []thinking.Option
+	switch {
+	case *strict && *lenient:
+		fmt.Fprintln(os.Stderr, "-strict and -lenient are mutually exclusive")
+		os.Exit(2)
+	case *strict:
+		opts = append(opts, thinking.WithValidation(thinking.ValidationStrict))
+	case *lenient:
+		opts = append(opts, thinking.WithValidation(thinking.ValidationLenient))
+	}
+
+	thinker := thinking.NewSequentialThinkingServer(opts...)
+	if *toolConfig != "" {
+		if err := configureTools(thinker, *toolConfig); err != nil {
+			fmt.Fprintf(os.Stderr, "Tool config error: %v\n", err)
+			os.Exit(1)
 		}
 	}
-
-	if val, ok := args["branchId"]; ok {
-		if s, ok := val.(string); ok {
-			data.BranchId = &s
+	if *session != "" {
+		doc, err := thinking.ReadSessionFile(*session)
+		if err == nil {
+			err = thinker.ImportSession(doc)
 		}
-	}
-
-	if val, ok := args["needsMoreThoughts"]; ok {
-		if b, ok := val.(bool); ok {
-			data.NeedsMoreThoughts = &b
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Session error: %v\n", err)
+			os.Exit(1)
 		}
 	}
 
-	return data, nil
-}
+	s := server.NewMCPServer(
+		"sequential-thinking-server",
+		"0.2.0",
+		server.WithResourceCapabilities(true, false),
+		server.WithLogging(),
+		server.WithElicitation(),
+		server.WithCompletions(),
+		server.WithPromptCompletionProvider(thinker),
+		server.WithResourceCompletionProvider(thinker),
+	)
 
-func (s *SequentialThinkingServer) formatThought(data *ThoughtData) string {
-	var prefix, context string
+	thinker.Register(s)
 
-	if data.IsRevision != nil && *data.IsRevision {
-		prefix = color.YellowString("🔄 Revision")
-		if data.RevisesThought != nil {
-			context = fmt.Sprintf(" (revising thought %d)", *data.RevisesThought)
+	for _, dir := range bundles {
+		if err := thinking.ServeBundle(s, dir); err != nil {
+			fmt.Fprintf(os.Stderr, "Bundle error: %v\n", err)
+			os.Exit(1)
 		}
-	} else if data.BranchFromThought != nil && data.BranchId != nil {
-		prefix = color.GreenString("🌿 Branch")
-		context = fmt.Sprintf(" (from thought %d, ID: %s)", *data.BranchFromThought, *data.BranchId)
-	} else {
-		prefix = color.BlueString("💭 Thought")
 	}
 
-	header := fmt.Sprintf("%s %d/%d%s", prefix, data.ThoughtNumber, data.TotalThoughts, context)
-	border := strings.Repeat("─", maxLen(len(header), len(data.Thought))+4)
-
-	return fmt.Sprintf("\n┌%s┐\n│ %s │\n├%s┤\n│ %-*s │\n└%s┘",
-		border, header, border, len(border)-2, data.Thought, border)
-}
-
-func maxLen(a, b int) int {
-	if a > b {
-		return a
+	if *dashboard != "" {
+		go func() {
+			if err := http.ListenAndServe(*dashboard, thinker.Dashboard()); err != nil {
+				fmt.Fprintf(os.Stderr, "Dashboard error: %v\n", err)
+			}
+		}()
 	}
-	return b
-}
 
-func (s *SequentialThinkingServer) processThought(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	args := request.GetArguments()
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
+	defer stop()
 
-	validatedInput, err := s.validateThoughtData(args)
-	if err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+	if *toolConfig != "" {
+		hup := make(chan os.Signal, 1)
+		signal.Notify(hup, syscall.SIGHUP)
+		go func() {
+			for range hup {
+				if err := configureTools(thinker, *toolConfig); err != nil {
+					fmt.Fprintf(os.Stderr, "Tool config error: %v\n", err)
+				}
+			}
+		}()
 	}
 
-	if validatedInput.ThoughtNumber > validatedInput.TotalThoughts {
-		validatedInput.TotalThoughts = validatedInput.ThoughtNumber
+	stdio := server.NewStdioServer(s)
+	in, out := thinker.FilterSubscriptions(os.Stdin, os.Stdout)
+	if err := stdio.Listen(ctx, in, out); err != nil {
+		fmt.Fprintf(os.Stderr, "Server error: %v\n", err)
+		os.Exit(1)
 	}
+}
 
-	s.thoughtHistory = append(s.thoughtHistory, *validatedInput)
-
-	if validatedInput.BranchFromThought != nil && validatedInput.BranchId != nil {
-		branchId := *validatedInput.BranchId
-		if s.branches[branchId] == nil {
-			s.branches[branchId] = make([]ThoughtData, 0)
-		}
-		s.branches[branchId] = append(s.branches[branchId], *validatedInput)
+func configureTools(thinker *thinking.SequentialThinkingServer, path string) error {
+	tools, err := thinking.ReadToolConfig(path)
+	if err != nil {
+		return err
 	}
+	return thinker.ConfigureTools(tools)
+}
 
-	if !s.disableThoughtLogging {
-		formattedThought := s.formatThought(validatedInput)
-		fmt.Fprintf(os.Stderr, "%s\n", formattedThought)
+// viewSession browses a session file in the terminal viewer.
+func viewSession(args []string) {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, "usage: gothink view <session.json>")
+		os.Exit(2)
 	}
-
-	branches := make([]string, 0, len(s.branches))
-	for k := range s.branches {
-		branches = append(branches, k)
+	doc, err := thinking.ReadSessionFile(args[0])
+	if err == nil {
+		err = view.Run(doc, os.Stdin, os.Stdout)
 	}
-
-	result := map[string]any{
-		"thoughtNumber":        validatedInput.ThoughtNumber,
-		"totalThoughts":        validatedInput.TotalThoughts,
-		"nextThoughtNeeded":    validatedInput.NextThoughtNeeded,
-		"branches":             branches,
-		"thoughtHistoryLength": len(s.thoughtHistory),
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "View error: %v\n", err)
+		os.Exit(1)
 	}
-
-	jsonBytes, _ := json.MarshalIndent(result, "", "  ")
-	return mcp.NewToolResultText(string(jsonBytes)), nil
 }
 
-func main() {
-	s := server.NewMCPServer(
-		"sequential-thinking-server",
-		"0.2.0",
-	)
-
-	thinkingServer := NewSequentialThinkingServer()
-
-	tool := mcp.NewTool("sequentialthinking",
-		mcp.WithDescription(`A detailed tool for dynamic and reflective problem-solving through thoughts.
-This tool helps analyze problems through a flexible thinking process that can adapt and evolve.
-Each thought can build on, question, or revise previous insights as understanding deepens.
-
-When to use this tool:
-- Breaking down complex problems into steps
-- Planning and design with room for revision
-- Analysis that might need course correction
-- Problems where the full scope might not be clear initially
-- Problems that require a multi-step solution
-- Tasks that need to maintain context over multiple steps
-- Situations where irrelevant information needs to be filtered out
-
-Key features:
-- You can adjust total_thoughts up or down as you progress
-- You can question or revise previous thoughts
-- You can add more thoughts even after reaching what seemed like the end
-- You can express uncertainty and explore alternative approaches
-- Not every thought needs to build linearly - you can branch or backtrack
-- Generates a solution hypothesis
-- Verifies the hypothesis based on the Chain of Thought steps
-- Repeats the process until satisfied
-- Provides a correct answer
-
-Parameters explained:
-- thought: Your current thinking step, which can include:
-* Regular analytical steps
-* Revisions of previous thoughts
-* Questions about previous decisions
-* Realizations about needing more analysis
-* Changes in approach
-* Hypothesis generation
-* Hypothesis verification
-- next_thought_needed: True if you need more thinking, even if at what seemed like the end
-- thought_number: Current number in sequence (can go beyond initial total if needed)
-- total_thoughts: Current estimate of thoughts needed (can be adjusted up/down)
-- is_revision: A boolean indicating if this thought revises previous thinking
-- revises_thought: If is_revision is true, which thought number is being reconsidered
-- branch_from_thought: If branching, which thought number is the branching point
-- branch_id: Identifier for the current branch (if any)
-- needs_more_thoughts: If reaching end but realizing more thoughts needed
-
-You should:
-1. Start with an initial estimate of needed thoughts, but be ready to adjust
-2. Feel free to question or revise previous thoughts
-3. Don't hesitate to add more thoughts if needed, even at the "end"
-4. Express uncertainty when present
-5. Mark thoughts that revise previous thinking or branch into new paths
-6. Ignore information that is irrelevant to the current step
-7. Generate a solution hypothesis when appropriate
-8. Verify the hypothesis based on the Chain of Thought steps
-9. Repeat the process until satisfied with the solution
-10. Provide a single, ideally correct answer as the final output
-11. Only set next_thought_needed to false when truly done and a satisfactory answer is reached`),
-		mcp.WithString("thought",
-			mcp.Required(),
-			mcp.Description("Your current thinking step"),
-		),
-		mcp.WithBoolean("nextThoughtNeeded",
-			mcp.Required(),
-			mcp.Description("Whether another thought step is needed"),
-		),
-		mcp.WithNumber("thoughtNumber",
-			mcp.Required(),
-			mcp.Description("Current thought number"),
-		),
-		mcp.WithNumber("totalThoughts",
-			mcp.Required(),
-			mcp.Description("Estimated total thoughts needed"),
-		),
-		mcp.WithBoolean("isRevision",
-			mcp.Description("Whether this revises previous thinking"),
-		),
-		mcp.WithNumber("revisesThought",
-			mcp.Description("Which thought is being reconsidered"),
-		),
-		mcp.WithNumber("branchFromThought",
-			mcp.Description("Branching point thought number"),
-		),
-		mcp.WithString("branchId",
-			mcp.Description("Branch identifier"),
-		),
-		mcp.WithBoolean("needsMoreThoughts",
-			mcp.Description("If more thoughts are needed"),
-		),
-	)
-
-	s.AddTool(tool, thinkingServer.processThought)
+// tailSession follows the thoughts of a server started with -dashboard.
+func tailSession(args []string) {
+	flags := flag.NewFlagSet("tail", flag.ExitOnError)
+	last := flags.Int("n", 10, "number of recorded thoughts to show first, -1 for all")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: gothink tail [-n N] <dashboard address>")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
 
-	if err := server.ServeStdio(s); err != nil {
-		fmt.Fprintf(os.Stderr, "Server error: %v\n", err)
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
+	defer stop()
+	if err := view.Tail(ctx, flags.Arg(0), *last, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "Tail error: %v\n", err)
 		os.Exit(1)
 	}
 }
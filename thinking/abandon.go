@@ -0,0 +1,45 @@
+package thinking
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func (s *SequentialThinkingServer) abandonBranch(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	branchId, err := request.RequireString("branchId")
+	if err != nil || branchId == "" {
+		return s.fail(ctx, request, invalid("branchId", "non-empty string", request.GetArguments()["branchId"], "must be a non-empty string"))
+	}
+	reason := request.GetString("reason", "")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b := s.branches[branchId]
+	if b == nil {
+		return s.fail(ctx, request, invalid("branchId", "recorded branch ID", branchId, "unknown branch %q", branchId))
+	}
+	if b.AbandonReason != nil {
+		return s.fail(ctx, request, invalid("branchId", "branch that wasn't abandoned", branchId, "branch %q was already abandoned", branchId))
+	}
+
+	b.Status, b.AbandonReason = BranchAbandoned, &reason
+	s.resourcesChanged(branchId)
+
+	if !s.disableThoughtLogging {
+		msg := fmt.Sprintf("🪦 Abandoned branch %s", branchId)
+		if reason != "" {
+			msg += ": " + reason
+		}
+		s.logEvent(s.theme.Alert, msg, "")
+		s.logTree()
+	}
+
+	return s.respond(ctx, request, map[string]any{
+		"abandoned": branchId,
+		"reason":    reason,
+		"branches":  s.branchNames(),
+	})
+}
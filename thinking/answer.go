@@ -0,0 +1,132 @@
+package thinking
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// FinalAnswer is the conclusion of a session, kept apart from the thoughts
+// that led to it.
+type FinalAnswer struct {
+	Answer        string    `json:"answer"`
+	ThoughtNumber int       `json:"thoughtNumber"`
+	ThoughtId     string    `json:"thoughtId,omitempty"`
+	RecordedAt    time.Time `json:"recordedAt"`
+}
+
+// WithRequireFinalAnswer sets whether the main line can only be concluded
+// by a thought marked isFinalAnswer, overriding GOTHINK_REQUIRE_FINAL_ANSWER.
+func WithRequireFinalAnswer(require bool) Option {
+	return func(s *SequentialThinkingServer) {
+		s.requireFinalAnswer = require
+	}
+}
+
+func requireFinalAnswerFromEnv() bool {
+	return strings.ToLower(os.Getenv("GOTHINK_REQUIRE_FINAL_ANSWER")) == "true"
+}
+
+// checkFinalAnswer checks that a thought marked as the final answer
+// concludes the main line, and, if the server requires a final answer, that
+// a thought concluding the main line is marked as one.
+func (s *SequentialThinkingServer) checkFinalAnswer(data *ThoughtData) error {
+	final := data.IsFinalAnswer != nil && *data.IsFinalAnswer
+	switch {
+	case final && data.NextThoughtNeeded:
+		return invalid("nextThoughtNeeded", "false", true, "must be false on the final answer")
+	case final && branchOf(data) != "":
+		return invalid("branchId", "omitted on the final answer", *data.BranchId,
+			"the final answer concludes the main line; merge branch %q into it first", *data.BranchId)
+	case s.requireFinalAnswer && !final && !data.NextThoughtNeeded && branchOf(data) == "":
+		var received any
+		if data.IsFinalAnswer != nil {
+			received = false
+		}
+		return &ValidationError{
+			Code:     "FINAL_ANSWER_REQUIRED",
+			Field:    "isFinalAnswer",
+			Path:     "/isFinalAnswer",
+			Expected: "true on the thought concluding the main line",
+			Received: received,
+			Message: "final answer required: the chain can only be concluded by a thought stating the answer with isFinalAnswer set; " +
+				"set nextThoughtNeeded to keep it open",
+		}
+	}
+	return nil
+}
+
+func (s *SequentialThinkingServer) finalizeAnswer(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	answer, err := request.RequireString("answer")
+	if err != nil || answer == "" {
+		return s.fail(ctx, request, invalid("answer", "non-empty string", request.GetArguments()["answer"], "must be a non-empty string"))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var latest *ThoughtData
+	for i := len(s.thoughtHistory) - 1; i >= 0 && latest == nil; i-- {
+		if t := &s.thoughtHistory[i]; branchOf(t) == "" {
+			latest = t
+		}
+	}
+	switch {
+	case latest == nil:
+		return s.fail(ctx, request, &ValidationError{
+			Code:     "CHAIN_NOT_CONCLUDED",
+			Expected: "main line concluded by a thought with nextThoughtNeeded false",
+			Message:  "cannot finalize: no thoughts recorded on the main line",
+		})
+	case latest.NextThoughtNeeded:
+		return s.fail(ctx, request, &ValidationError{
+			Code:     "CHAIN_NOT_CONCLUDED",
+			Expected: "main line concluded by a thought with nextThoughtNeeded false",
+			Received: latest.ThoughtNumber,
+			Message:  fmt.Sprintf("cannot finalize: thought %d still has nextThoughtNeeded set; conclude the chain first", latest.ThoughtNumber),
+		})
+	}
+
+	replaced := s.finalAnswer != nil
+	s.finalAnswer = &FinalAnswer{
+		Answer:        answer,
+		ThoughtNumber: latest.ThoughtNumber,
+		ThoughtId:     latest.Id,
+		RecordedAt:    time.Now().UTC(),
+	}
+
+	if !s.disableThoughtLogging {
+		s.logEvent(s.theme.Success, "✅ Final answer:", answer)
+	}
+
+	result := map[string]any{
+		"finalAnswer": s.finalAnswer,
+		"replaced":    replaced,
+	}
+	if warnings := s.conclusionWarnings(); len(warnings) > 0 {
+		result["warnings"] = warnings
+	}
+	return s.respond(ctx, request, result)
+}
+
+func (s *SequentialThinkingServer) getFinalAnswer(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.finalAnswer == nil {
+		return s.fail(ctx, request, &ValidationError{
+			Code:     "NO_FINAL_ANSWER",
+			Expected: "final answer recorded",
+			Message:  "no final answer: conclude the main line with a thought marked isFinalAnswer, or call finalize_answer",
+		})
+	}
+	result := map[string]any{"finalAnswer": s.finalAnswer}
+	if t := s.thoughtByID(s.finalAnswer.ThoughtId); t != nil && s.finalAnswer.ThoughtId != "" {
+		result["thought"] = t
+	}
+	return s.respond(ctx, request, result)
+}
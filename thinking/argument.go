@@ -0,0 +1,141 @@
+package thinking
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	ArgumentClaim           = "claim"
+	ArgumentEvidence        = "evidence"
+	ArgumentCounterargument = "counterargument"
+	ArgumentRebuttal        = "rebuttal"
+)
+
+var argumentKinds = []string{ArgumentClaim, ArgumentEvidence, ArgumentCounterargument, ArgumentRebuttal}
+
+// argumentTargets lists the kinds of node each kind may attach to.
+var argumentTargets = map[string][]string{
+	ArgumentEvidence:        {ArgumentClaim, ArgumentRebuttal},
+	ArgumentCounterargument: {ArgumentClaim, ArgumentEvidence, ArgumentRebuttal},
+	ArgumentRebuttal:        {ArgumentCounterargument},
+}
+
+// ArgumentNode is a typed step of a structured debate. Every node but a
+// claim attaches to a target node; counterarguments and rebuttals attack
+// their target, evidence supports it.
+type ArgumentNode struct {
+	ID            string `json:"id"`
+	Kind          string `json:"kind"`
+	Statement     string `json:"statement"`
+	Target        string `json:"target,omitempty"`
+	ThoughtNumber int    `json:"thoughtNumber,omitempty"`
+}
+
+// ArgumentTree is a node with everything attached to it, and whether it
+// still stands: a node falls when an attack on it stands.
+type ArgumentTree struct {
+	ArgumentNode
+	Standing bool           `json:"standing"`
+	Replies  []ArgumentTree `json:"replies"`
+}
+
+func (s *SequentialThinkingServer) findArgument(id string) *ArgumentNode {
+	for i := range s.arguments {
+		if s.arguments[i].ID == id {
+			return &s.arguments[i]
+		}
+	}
+	return nil
+}
+
+// argumentTree builds the debate rooted at the given node.
+func (s *SequentialThinkingServer) argumentTree(node ArgumentNode) ArgumentTree {
+	tree := ArgumentTree{ArgumentNode: node, Standing: true, Replies: make([]ArgumentTree, 0)}
+	for _, n := range s.arguments {
+		if n.Target != node.ID {
+			continue
+		}
+		reply := s.argumentTree(n)
+		if reply.Standing && (n.Kind == ArgumentCounterargument || n.Kind == ArgumentRebuttal) {
+			tree.Standing = false
+		}
+		tree.Replies = append(tree.Replies, reply)
+	}
+	return tree
+}
+
+// argumentClaim returns the claim a node ultimately argues about.
+func (s *SequentialThinkingServer) argumentClaim(node *ArgumentNode) *ArgumentNode {
+	for node.Target != "" {
+		node = s.findArgument(node.Target)
+	}
+	return node
+}
+
+func (s *SequentialThinkingServer) recordArgument(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	kind, err := request.RequireString("kind")
+	if err != nil || !slices.Contains(argumentKinds, kind) {
+		return s.fail(ctx, request, invalid("kind", "one of "+strings.Join(argumentKinds, ", "), request.GetArguments()["kind"], "must be one of %s", strings.Join(argumentKinds, ", ")))
+	}
+	statement, err := request.RequireString("statement")
+	if err != nil || statement == "" {
+		return s.fail(ctx, request, invalid("statement", "non-empty string", request.GetArguments()["statement"], "must be a non-empty string"))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n, err := s.optionalThought(request, "thoughtNumber")
+	if err != nil {
+		return s.fail(ctx, request, err)
+	}
+	if n == 0 && len(s.thoughtHistory) > 0 {
+		n = s.thoughtHistory[len(s.thoughtHistory)-1].ThoughtNumber
+	}
+
+	target := request.GetString("targetId", "")
+	switch {
+	case kind == ArgumentClaim && target != "":
+		return s.fail(ctx, request, invalid("targetId", "omitted for a claim", target, "a claim does not attach to another node"))
+	case kind != ArgumentClaim && target == "":
+		return s.fail(ctx, request, invalid("targetId", "ID of a "+strings.Join(argumentTargets[kind], " or "), nil, "a %s must attach to a %s", kind, strings.Join(argumentTargets[kind], " or ")))
+	case kind != ArgumentClaim:
+		t := s.findArgument(target)
+		if t == nil {
+			return s.fail(ctx, request, invalid("targetId", "recorded argument node ID", target, "unknown argument node %q", target))
+		}
+		if !slices.Contains(argumentTargets[kind], t.Kind) {
+			return s.fail(ctx, request, invalid("targetId", "ID of a "+strings.Join(argumentTargets[kind], " or "), target, "a %s cannot attach to a %s", kind, t.Kind))
+		}
+	}
+
+	s.argumentSeq++
+	node := ArgumentNode{
+		ID:            fmt.Sprintf("ARG%d", s.argumentSeq),
+		Kind:          kind,
+		Statement:     statement,
+		Target:        target,
+		ThoughtNumber: n,
+	}
+	s.arguments = append(s.arguments, node)
+	claim := s.argumentTree(*s.argumentClaim(&node))
+
+	if !s.disableThoughtLogging {
+		verdict := "stands"
+		if !claim.Standing {
+			verdict = "is defeated"
+		}
+		s.logEvent(s.theme.Record, fmt.Sprintf("🗣️  %s %s:", strings.ToUpper(kind[:1])+kind[1:], node.ID),
+			fmt.Sprintf("%s (claim %s %s)", statement, claim.ID, verdict))
+	}
+
+	return s.respond(ctx, request, map[string]any{
+		"node":  node,
+		"claim": claim,
+	})
+}
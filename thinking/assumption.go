@@ -0,0 +1,136 @@
+package thinking
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	AssumptionUnverified  = "unverified"
+	AssumptionConfirmed   = "confirmed"
+	AssumptionInvalidated = "invalidated"
+)
+
+// Assumption is a premise the chain relies on, registered so it can be
+// validated before the answer is final.
+type Assumption struct {
+	ID            string `json:"id"`
+	Statement     string `json:"statement"`
+	Status        string `json:"status"`
+	ThoughtNumber int    `json:"thoughtNumber,omitempty"`
+	Note          string `json:"note,omitempty"`
+}
+
+// unvalidatedAssumptions returns the IDs of assumptions nobody confirmed or
+// invalidated yet.
+func (s *SequentialThinkingServer) unvalidatedAssumptions() []string {
+	ids := make([]string, 0)
+	for _, a := range s.assumptions {
+		if a.Status == AssumptionUnverified {
+			ids = append(ids, a.ID)
+		}
+	}
+	return ids
+}
+
+// conclusionWarnings lists what is still unresolved when the chain concludes.
+func (s *SequentialThinkingServer) conclusionWarnings() []string {
+	var warnings []string
+	if ids := s.unvalidatedAssumptions(); len(ids) > 0 {
+		warnings = append(warnings, fmt.Sprintf("concluding with unvalidated assumptions: %s", strings.Join(ids, ", ")))
+	}
+	if open := s.openQuestions(); len(open) > 0 {
+		ids := make([]string, len(open))
+		for i, q := range open {
+			ids[i] = q.ID
+		}
+		warnings = append(warnings, fmt.Sprintf("concluding with open questions: %s", strings.Join(ids, ", ")))
+	}
+	if warning := s.openUncertaintiesWarning(); warning != "" {
+		warnings = append(warnings, warning)
+	}
+	if warning := s.lowConfidenceWarning(); warning != "" {
+		warnings = append(warnings, warning)
+	}
+	if warning := s.unverifiedHypothesisWarning(); warning != "" {
+		warnings = append(warnings, warning)
+	}
+	return warnings
+}
+
+func (s *SequentialThinkingServer) recordAssumption(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	statement, err := request.RequireString("statement")
+	if err != nil || statement == "" {
+		return s.fail(ctx, request, invalid("statement", "non-empty string", request.GetArguments()["statement"], "must be a non-empty string"))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := request.GetInt("thoughtNumber", 0)
+	if n != 0 && !s.hasThought(n) {
+		return s.fail(ctx, request, invalid("thoughtNumber", "recorded thought number", n, "thought %d does not exist", n))
+	}
+
+	s.assumptionSeq++
+	a := Assumption{
+		ID:            fmt.Sprintf("A%d", s.assumptionSeq),
+		Statement:     statement,
+		Status:        AssumptionUnverified,
+		ThoughtNumber: n,
+	}
+	s.assumptions = append(s.assumptions, a)
+
+	if !s.disableThoughtLogging {
+		s.logEvent(s.theme.Record, fmt.Sprintf("📎 Assumption %s:", a.ID), statement)
+	}
+
+	return s.respond(ctx, request, map[string]any{
+		"assumption":  a,
+		"unvalidated": s.unvalidatedAssumptions(),
+	})
+}
+
+func (s *SequentialThinkingServer) updateAssumption(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	id, err := request.RequireString("assumptionId")
+	if err != nil || id == "" {
+		return s.fail(ctx, request, invalid("assumptionId", "non-empty string", request.GetArguments()["assumptionId"], "must be a non-empty string"))
+	}
+	status, err := request.RequireString("status")
+	switch {
+	case err != nil:
+		return s.fail(ctx, request, invalid("status", "string", request.GetArguments()["status"], "must be a string"))
+	case status != AssumptionUnverified && status != AssumptionConfirmed && status != AssumptionInvalidated:
+		return s.fail(ctx, request, invalid("status", "one of "+AssumptionUnverified+", "+AssumptionConfirmed+", "+AssumptionInvalidated, status, "must be one of %s, %s, %s", AssumptionUnverified, AssumptionConfirmed, AssumptionInvalidated))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var a *Assumption
+	for i := range s.assumptions {
+		if s.assumptions[i].ID == id {
+			a = &s.assumptions[i]
+		}
+	}
+	if a == nil {
+		return s.fail(ctx, request, invalid("assumptionId", "recorded assumption ID", id, "unknown assumption %q", id))
+	}
+
+	a.Status = status
+	if note := request.GetString("note", ""); note != "" {
+		a.Note = note
+	}
+
+	if !s.disableThoughtLogging {
+		s.logEvent(s.theme.Record, fmt.Sprintf("📎 Assumption %s is %s", a.ID, a.Status), "")
+	}
+
+	return s.respond(ctx, request, map[string]any{
+		"assumption":  a,
+		"unvalidated": s.unvalidatedAssumptions(),
+	})
+}
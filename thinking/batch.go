@@ -0,0 +1,124 @@
+package thinking
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// thinkBatch records several thoughts as if each had been sent to
+// sequentialthinking in turn. The batch is all-or-nothing: every thought is
+// checked and stored before any is announced, and if one is rejected, the
+// ones before it are rolled back without subscribers or the log ever seeing
+// them.
+func (s *SequentialThinkingServer) thinkBatch(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	items, ok := request.GetArguments()["thoughts"].([]any)
+	if !ok || len(items) == 0 {
+		return s.fail(ctx, request, invalid("thoughts", "non-empty array of thought objects", request.GetArguments()["thoughts"], "must be a non-empty array of thought objects"))
+	}
+
+	for i, item := range items {
+		if _, ok := item.(map[string]any); !ok {
+			return s.fail(ctx, request, notAnObject("thoughts", i, item))
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// stored is a thought the batch recorded, announced once the whole batch
+	// is accepted.
+	type stored struct {
+		index    int
+		data     *ThoughtData
+		branched bool
+		warnings []string
+	}
+	// Storing a thought can change others, such as the one it revises;
+	// subscribers hear of it only once the whole batch is accepted.
+	s.holdNotifications()
+	before, calls := s.snapshot(), slices.Clone(s.calls)
+	rollback := func(i int, err error) (*mcp.CallToolResult, error) {
+		s.restore(before)
+		s.calls = calls
+		s.releaseNotifications(false)
+		return s.fail(ctx, request, inItem("thoughts", i, err))
+	}
+	accepted := make([]map[string]any, 0, len(items))
+	var batch []stored
+	var last map[string]any
+	for i, item := range items {
+		args := item.(map[string]any)
+		previous, err := s.replay(args)
+		if err != nil {
+			return rollback(i, err)
+		} else if previous != nil {
+			accepted = append(accepted, previous)
+			last = previous
+			continue
+		}
+		screened, noticed, err := s.screen(args)
+		var data *ThoughtData
+		if err == nil {
+			data, err = s.validateThoughtData(screened)
+		}
+		echo := 0
+		if err == nil {
+			echo, err = s.echoCount(screened)
+		}
+		if err == nil {
+			var more []string
+			more, err = s.accept(data)
+			noticed = append(noticed, more...)
+		}
+		if err != nil {
+			return rollback(i, err)
+		}
+		batch = append(batch, stored{index: i, data: data, branched: s.store(data), warnings: noticed})
+		result := map[string]any{
+			"thoughtNumber":     data.ThoughtNumber,
+			"totalThoughts":     data.TotalThoughts,
+			"nextThoughtNeeded": data.NextThoughtNeeded,
+			"id":                data.Id,
+			"receivedAt":        data.ReceivedAt,
+			"tokens":            data.Tokens,
+		}
+		if echo > 0 {
+			result["recentThoughts"] = s.recentThoughts(echo)
+		}
+		s.remember(args, result)
+		accepted = append(accepted, result)
+		last = result
+	}
+
+	var warnings []string
+	for _, b := range batch {
+		s.announce(ctx, b.data, b.branched)
+		s.logWarnings(b.warnings)
+		for _, w := range b.warnings {
+			warnings = append(warnings, fmt.Sprintf("thoughts[%d]: %s", b.index, w))
+		}
+		s.reportProgress(ctx, request, b.data)
+	}
+	s.releaseNotifications(true)
+
+	result := map[string]any{
+		"thoughts":             accepted,
+		"thoughtNumber":        last["thoughtNumber"],
+		"totalThoughts":        last["totalThoughts"],
+		"nextThoughtNeeded":    last["nextThoughtNeeded"],
+		"branches":             s.branchNames(),
+		"thoughtHistoryLength": len(s.thoughtHistory),
+		"sessionTokens":        s.sessionTokens(),
+	}
+	if next, _ := last["nextThoughtNeeded"].(bool); !next {
+		warnings = append(warnings, s.conclusionWarnings()...)
+	}
+	if len(warnings) > 0 {
+		result["warnings"] = warnings
+	}
+
+	return s.respond(ctx, request, result)
+}
@@ -0,0 +1,146 @@
+package thinking
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestThinkBatch(t *testing.T) {
+	tests := []struct {
+		name        string
+		thoughts    []any
+		wantNumbers []int
+		wantPath    string
+	}{
+		{
+			name:        "all accepted",
+			thoughts:    []any{thought(1, 3, "a", nil), thought(2, 3, "b", nil), thought(3, 3, "c", nil)},
+			wantNumbers: []int{1, 2, 3},
+		},
+		{
+			name:        "later thoughts see earlier ones",
+			thoughts:    []any{thought(1, 2, "a", nil), thought(2, 2, "b", map[string]any{"isRevision": true, "revisesThought": 1.0})},
+			wantNumbers: []int{1, 2},
+		},
+		{
+			name:     "invalid item",
+			thoughts: []any{thought(1, 2, "a", nil), thought(2, 2, "b", map[string]any{"thoughtNumber": "two"})},
+			wantPath: "/thoughts/1/thoughtNumber",
+		},
+		{
+			name:     "rejected against an earlier item",
+			thoughts: []any{thought(1, 2, "a", nil), thought(2, 2, "b", map[string]any{"isRevision": true, "revisesThought": 3.0})},
+			wantPath: "/thoughts/1/revisesThought",
+		},
+		{
+			name:     "not an object",
+			thoughts: []any{thought(1, 2, "a", nil), "b"},
+			wantPath: "/thoughts/1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t)
+			changes := s.watch()
+			defer s.unwatch(changes)
+
+			fields, isError := callTool(t, s.thinkBatch, map[string]any{"thoughts": tt.thoughts})
+			if tt.wantPath != "" {
+				if !isError {
+					t.Fatalf("think_batch succeeded, want an error at %s", tt.wantPath)
+				}
+				if fields["path"] != tt.wantPath {
+					t.Errorf("path = %v, want %s", fields["path"], tt.wantPath)
+				}
+				if len(s.thoughtHistory) != 0 {
+					t.Errorf("a rejected batch left %d thoughts", len(s.thoughtHistory))
+				}
+				select {
+				case <-changes:
+					t.Errorf("a rejected batch notified watchers")
+				default:
+				}
+				return
+			}
+			if isError {
+				t.Fatalf("think_batch: %v", fields["error"])
+			}
+			if got := numbers(s); !slices.Equal(got, tt.wantNumbers) {
+				t.Errorf("numbers = %v, want %v", got, tt.wantNumbers)
+			}
+			if got := len(fields["thoughts"].([]any)); got != len(tt.thoughts) {
+				t.Errorf("got %d results, want %d", got, len(tt.thoughts))
+			}
+		})
+	}
+}
+
+func TestThinkBatchItemOptions(t *testing.T) {
+	s := newTestServer(t)
+	first := thought(1, 3, "a", map[string]any{"idempotencyKey": "k1"})
+	mustCall(t, s.thinkBatch, map[string]any{"thoughts": []any{
+		first,
+		thought(2, 3, "b", map[string]any{"echoRecent": 1.0}),
+	}})
+	fields := mustCall(t, s.thinkBatch, map[string]any{"thoughts": []any{
+		first,
+		thought(3, 3, "c", nil),
+	}})
+
+	if got := numbers(s); !slices.Equal(got, []int{1, 2, 3}) {
+		t.Errorf("numbers = %v, want [1 2 3]", got)
+	}
+	results := fields["thoughts"].([]any)
+	if replayed, _ := results[0].(map[string]any)["replayed"].(bool); !replayed {
+		t.Errorf("retried item was not replayed: %v", results[0])
+	}
+
+	// A retry of the item on its own is replayed too.
+	if replayed, _ := mustCall(t, s.processThought, first)["replayed"].(bool); !replayed {
+		t.Errorf("sequentialthinking did not replay a batch item")
+	}
+
+	echoed := mustCall(t, s.thinkBatch, map[string]any{"thoughts": []any{
+		thought(4, 4, "d", map[string]any{"echoRecent": 1.0}),
+	}})["thoughts"].([]any)[0].(map[string]any)
+	if recent, _ := echoed["recentThoughts"].([]any); len(recent) != 1 {
+		t.Errorf("recentThoughts = %v, want one thought", echoed["recentThoughts"])
+	}
+}
+
+func TestThinkBatchHoldsNotifications(t *testing.T) {
+	s := newTestServer(t)
+	mustCall(t, s.processThought, thought(1, 3, "a", nil))
+	changes := s.watch()
+	defer s.unwatch(changes)
+
+	// The revision retracts thought 1 before the last item is rejected.
+	_, isError := callTool(t, s.thinkBatch, map[string]any{"thoughts": []any{
+		thought(2, 3, "a again", map[string]any{"isRevision": true, "revisesThought": 1.0}),
+		thought(3, 3, "c", map[string]any{"thoughtNumber": "three"}),
+	}})
+	if !isError {
+		t.Fatalf("think_batch accepted an invalid thought")
+	}
+	select {
+	case <-changes:
+		t.Errorf("a rejected batch notified watchers")
+	default:
+	}
+	if status := s.thoughtHistory[0].Status; status != ThoughtOpen {
+		t.Errorf("thought 1 is %s after a rejected batch, want %s", status, ThoughtOpen)
+	}
+
+	mustCall(t, s.thinkBatch, map[string]any{"thoughts": []any{
+		thought(2, 3, "a again", map[string]any{"isRevision": true, "revisesThought": 1.0}),
+		thought(3, 3, "c", nil),
+	}})
+	select {
+	case <-changes:
+	default:
+		t.Errorf("an accepted batch didn't notify watchers")
+	}
+	if s.held != nil {
+		t.Errorf("notifications are still held after the batch")
+	}
+}
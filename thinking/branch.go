@@ -0,0 +1,138 @@
+package thinking
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// BranchStatus is where a branch stands: still being explored, folded back
+// by merge_branches, or given up by abandon_branch.
+type BranchStatus string
+
+const (
+	BranchOpen      BranchStatus = "open"
+	BranchMerged    BranchStatus = "merged"
+	BranchAbandoned BranchStatus = "abandoned"
+)
+
+var branchStatuses = []string{string(BranchOpen), string(BranchMerged), string(BranchAbandoned)}
+
+// Branch is a line of thoughts forked from the main line or from another
+// branch. It is created by the first thought recorded on it; its thoughts
+// are copies of those in the history.
+type Branch struct {
+	ID                string `json:"id"`
+	BranchFromThought int    `json:"branchFromThought"`
+	// CreatedAt is when the branch's first thought was recorded.
+	CreatedAt   *time.Time   `json:"createdAt,omitempty"`
+	Description string       `json:"description,omitempty"`
+	Status      BranchStatus `json:"status,omitempty"`
+	// MergedInto is the branch the branch was merged into, empty for the
+	// main line, and AbandonReason why it was abandoned; each is nil until
+	// then.
+	MergedInto    *string       `json:"mergedInto,omitempty"`
+	AbandonReason *string       `json:"abandonReason,omitempty"`
+	Thoughts      []ThoughtData `json:"-"`
+}
+
+// clone returns a copy of b that shares nothing with it.
+func (b *Branch) clone() *Branch {
+	c := *b
+	c.Thoughts = slices.Clone(b.Thoughts)
+	return &c
+}
+
+// mergedInto returns the line a branch was merged into, if it was.
+func (s *SequentialThinkingServer) mergedInto(branchId string) (string, bool) {
+	if b := s.branches[branchId]; b != nil && b.MergedInto != nil {
+		return *b.MergedInto, true
+	}
+	return "", false
+}
+
+// abandonReason returns why a branch was abandoned, if it was.
+func (s *SequentialThinkingServer) abandonReason(branchId string) (string, bool) {
+	if b := s.branches[branchId]; b != nil && b.AbandonReason != nil {
+		return *b.AbandonReason, true
+	}
+	return "", false
+}
+
+// abandonedBranches counts the branches that were abandoned.
+func (s *SequentialThinkingServer) abandonedBranches() int {
+	n := 0
+	for _, b := range s.branches {
+		if b.AbandonReason != nil {
+			n++
+		}
+	}
+	return n
+}
+
+// branchList returns every branch, abandoned ones included, in sorted order.
+func (s *SequentialThinkingServer) branchList() []*Branch {
+	branches := make([]*Branch, 0, len(s.branches))
+	for _, id := range s.allBranches() {
+		branches = append(branches, s.branches[id])
+	}
+	return branches
+}
+
+// BranchInfo is a branch as the branch tools return it.
+type BranchInfo struct {
+	*Branch
+	ThoughtCount  int `json:"thoughtCount"`
+	LatestThought int `json:"latestThought"`
+}
+
+func branchInfo(b *Branch) BranchInfo {
+	return BranchInfo{Branch: b, ThoughtCount: len(b.Thoughts), LatestThought: b.Thoughts[len(b.Thoughts)-1].ThoughtNumber}
+}
+
+func (s *SequentialThinkingServer) listBranches(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	status := request.GetString("status", "")
+	if status != "" && !slices.Contains(branchStatuses, status) {
+		return s.fail(ctx, request, invalid("status", "one of "+strings.Join(branchStatuses, ", "), status, "must be one of %s, got %q", strings.Join(branchStatuses, ", "), status))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	branches := make([]BranchInfo, 0, len(s.branches))
+	for _, b := range s.branchList() {
+		if status == "" || b.Status == BranchStatus(status) {
+			branches = append(branches, branchInfo(b))
+		}
+	}
+	return s.respond(ctx, request, map[string]any{
+		"branches": branches,
+		"count":    len(branches),
+	})
+}
+
+func (s *SequentialThinkingServer) describeBranch(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	branchId, err := request.RequireString("branchId")
+	if err != nil || branchId == "" {
+		return s.fail(ctx, request, invalid("branchId", "non-empty string", request.GetArguments()["branchId"], "must be a non-empty string"))
+	}
+	description, err := request.RequireString("description")
+	if err != nil {
+		return s.fail(ctx, request, invalid("description", "string", request.GetArguments()["description"], "must be a string"))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b := s.branches[branchId]
+	if b == nil {
+		return s.fail(ctx, request, invalid("branchId", "recorded branch ID", branchId, "unknown branch %q", branchId))
+	}
+	b.Description = description
+	s.resourcesChanged(branchId)
+
+	return s.respond(ctx, request, branchInfo(b))
+}
@@ -0,0 +1,308 @@
+package thinking
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const bundleManifest = "index.json"
+
+// BundleManifest is the index.json of an exported session bundle. Every
+// resource maps an MCP resource URI to a file inside the bundle directory.
+type BundleManifest struct {
+	Name        string           `json:"name"`
+	ExportedAt  time.Time        `json:"exportedAt"`
+	Thoughts    int              `json:"thoughts"`
+	Branches    []string         `json:"branches"`
+	FinalAnswer *FinalAnswer     `json:"finalAnswer,omitempty"`
+	Resources   []BundleResource `json:"resources"`
+}
+
+// BundleResource describes one document of a bundle.
+type BundleResource struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	MIMEType    string `json:"mimeType"`
+	File        string `json:"file"`
+}
+
+func bundleURI(name, path string) string {
+	return fmt.Sprintf("thoughts://bundle/%s/%s", url.PathEscape(name), path)
+}
+
+// finalThought returns the last main-line thought that ended the chain, if
+// any. Exports fall back to it when no final answer was recorded.
+func (s *SequentialThinkingServer) finalThought() *ThoughtData {
+	for i := len(s.thoughtHistory) - 1; i >= 0; i-- {
+		t := &s.thoughtHistory[i]
+		if branchOf(t) == "" && !t.NextThoughtNeeded {
+			return t
+		}
+	}
+	return nil
+}
+
+// writeThoughtMarkdown writes a thought as a section whose heading has the
+// given level. A revision's callout shows its diff, if given.
+func writeThoughtMarkdown(b *strings.Builder, t *ThoughtData, level int, diff []DiffOp) {
+	fmt.Fprintf(b, "%s Thought %d/%d\n\n", strings.Repeat("#", level), t.ThoughtNumber, t.TotalThoughts)
+	if t.RevisesThought != nil {
+		if t.RevisesBranchId != nil && *t.RevisesBranchId != "" {
+			fmt.Fprintf(b, "> Revises thought %d in branch %s\n", *t.RevisesThought, *t.RevisesBranchId)
+		} else {
+			fmt.Fprintf(b, "> Revises thought %d\n", *t.RevisesThought)
+		}
+		if diff != nil {
+			fmt.Fprintf(b, ">\n> Changes: %s\n", markdownDiff(diff))
+		}
+		b.WriteString("\n")
+	}
+	if t.MergedBranchId != nil {
+		fmt.Fprintf(b, "> Merges branch %s (conclusions: %s)\n\n", *t.MergedBranchId, joinInts(t.MergedThoughts))
+	}
+	fmt.Fprintf(b, "%s\n\n", t.Thought)
+	if len(t.Citations) > 0 {
+		fmt.Fprintf(b, "Citations: %s\n\n", markdownCitations(t.Citations))
+	}
+	if len(t.Uncertainties) > 0 {
+		fmt.Fprintf(b, "Uncertainties: %s\n\n", strings.Join(t.Uncertainties, "; "))
+	}
+}
+
+// conclusion returns the final answer as a thought, falling back to the
+// thought that ended the chain, or nil if the chain is still open.
+func (s *SequentialThinkingServer) conclusion() *ThoughtData {
+	if s.finalAnswer != nil {
+		return &ThoughtData{ThoughtNumber: s.finalAnswer.ThoughtNumber, Thought: s.finalAnswer.Answer}
+	}
+	return s.finalThought()
+}
+
+// writeRecordsMarkdown lists the hypotheses, assumptions and questions of the
+// session under headings of the given level.
+func (s *SequentialThinkingServer) writeRecordsMarkdown(b *strings.Builder, level int) {
+	heading := strings.Repeat("#", level)
+	if len(s.hypotheses) > 0 {
+		fmt.Fprintf(b, "\n%s Hypotheses\n\n", heading)
+		for _, h := range s.hypotheses {
+			fmt.Fprintf(b, "- **%s** (%s): %s", h.ID, h.Status, h.Statement)
+			if len(h.SupportingThoughts) > 0 {
+				fmt.Fprintf(b, "; supported by %s", joinInts(h.SupportingThoughts))
+			}
+			if len(h.RefutingThoughts) > 0 {
+				fmt.Fprintf(b, "; refuted by %s", joinInts(h.RefutingThoughts))
+			}
+			fmt.Fprintf(b, "\n")
+		}
+	}
+	if len(s.assumptions) > 0 {
+		fmt.Fprintf(b, "\n%s Assumptions\n\n", heading)
+		for _, a := range s.assumptions {
+			fmt.Fprintf(b, "- **%s** (%s): %s\n", a.ID, a.Status, a.Statement)
+		}
+	}
+	if len(s.questions) > 0 {
+		fmt.Fprintf(b, "\n%s Questions\n\n", heading)
+		for _, q := range s.questions {
+			if q.open() {
+				fmt.Fprintf(b, "- **%s** (open): %s\n", q.ID, q.Question)
+			} else {
+				fmt.Fprintf(b, "- **%s**: %s — %s\n", q.ID, q.Question, q.Answer)
+			}
+		}
+	}
+}
+
+func joinInts(numbers []int) string {
+	parts := make([]string, len(numbers))
+	for i, n := range numbers {
+		parts[i] = strconv.Itoa(n)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ExportBundle writes the session to dir as a bundle of Markdown documents
+// (an index, the main line, one document per branch and the final answer)
+// described by an index.json manifest that ServeBundle can mount.
+func (s *SequentialThinkingServer) ExportBundle(dir, name string) (*BundleManifest, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	manifest := &BundleManifest{
+		Name:        name,
+		ExportedAt:  time.Now().UTC(),
+		Thoughts:    len(s.thoughtHistory),
+		Branches:    s.branchNames(),
+		FinalAnswer: s.finalAnswer,
+	}
+	files := make(map[string]string)
+	add := func(path, file, title, description, content string) {
+		manifest.Resources = append(manifest.Resources, BundleResource{
+			URI:         bundleURI(name, path),
+			Name:        title,
+			Description: description,
+			MIMEType:    "text/markdown",
+			File:        file,
+		})
+		files[file] = content
+	}
+
+	var index strings.Builder
+	fmt.Fprintf(&index, "# Session %s\n\n", name)
+	fmt.Fprintf(&index, "Exported %s with %d thoughts.\n\n", manifest.ExportedAt.Format(time.RFC3339), manifest.Thoughts)
+	fmt.Fprintf(&index, "- [Main line](%s)\n", bundleURI(name, "main"))
+	for _, id := range manifest.Branches {
+		branch := s.branches[id]
+		fmt.Fprintf(&index, "- [Branch %s](%s) from thought %d, %d thoughts",
+			id, bundleURI(name, "branch/"+url.PathEscape(id)), branch.BranchFromThought, len(branch.Thoughts))
+		if branch.Description != "" {
+			fmt.Fprintf(&index, ": %s", branch.Description)
+		}
+		index.WriteString("\n")
+	}
+	final := s.conclusion()
+	if final != nil {
+		fmt.Fprintf(&index, "- [Final answer](%s)\n", bundleURI(name, "answer"))
+	}
+	s.writeRecordsMarkdown(&index, 2)
+	add("index", "index.md", name, "Overview of the session", index.String())
+
+	var mainLine strings.Builder
+	fmt.Fprintf(&mainLine, "# Main line\n\n")
+	for i := range s.thoughtHistory {
+		if t := &s.thoughtHistory[i]; branchOf(t) == "" {
+			writeThoughtMarkdown(&mainLine, t, 2, s.revisionDiff(t))
+		}
+	}
+	add("main", "main.md", name+": main line", "Thoughts on the main line", mainLine.String())
+
+	for _, id := range manifest.Branches {
+		branch := s.branches[id]
+		thoughts := branch.Thoughts
+		var b strings.Builder
+		fmt.Fprintf(&b, "# Branch %s\n\nBranched from thought %d.\n\n", id, branch.BranchFromThought)
+		if branch.Description != "" {
+			fmt.Fprintf(&b, "%s\n\n", branch.Description)
+		}
+		for i := range thoughts {
+			writeThoughtMarkdown(&b, &thoughts[i], 2, s.revisionDiff(&thoughts[i]))
+		}
+		add("branch/"+url.PathEscape(id), filepath.Join("branches", url.PathEscape(id)+".md"),
+			name+": branch "+id, "Thoughts on branch "+id, b.String())
+	}
+
+	if final != nil {
+		add("answer", "answer.md", name+": final answer", "The conclusion of the session",
+			fmt.Sprintf("# Final answer\n\nConcluded at thought %d.\n\n%s\n", final.ThoughtNumber, final.Thought))
+	}
+
+	if err := os.MkdirAll(filepath.Join(dir, "branches"), 0o755); err != nil {
+		return nil, err
+	}
+	for file, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(content), 0o644); err != nil {
+			return nil, err
+		}
+	}
+	jsonBytes, _ := json.MarshalIndent(manifest, "", "  ")
+	if err := os.WriteFile(filepath.Join(dir, bundleManifest), jsonBytes, 0o644); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// ServeBundle registers the documents of an exported bundle as read-only
+// resources on srv. Files are read on every request.
+func ServeBundle(srv *server.MCPServer, dir string) error {
+	jsonBytes, err := os.ReadFile(filepath.Join(dir, bundleManifest))
+	if err != nil {
+		return err
+	}
+	var manifest BundleManifest
+	if err := json.Unmarshal(jsonBytes, &manifest); err != nil {
+		return fmt.Errorf("%s: %w", bundleManifest, err)
+	}
+
+	for _, r := range manifest.Resources {
+		path := filepath.Join(dir, filepath.Clean("/"+r.File))
+		srv.AddResource(
+			mcp.NewResource(r.URI, r.Name,
+				mcp.WithResourceDescription(r.Description),
+				mcp.WithMIMEType(r.MIMEType),
+			),
+			func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+				content, err := os.ReadFile(path)
+				if err != nil {
+					return nil, err
+				}
+				return []mcp.ResourceContents{mcp.TextResourceContents{
+					URI:      r.URI,
+					MIMEType: r.MIMEType,
+					Text:     string(content),
+				}}, nil
+			},
+		)
+	}
+	return nil
+}
+
+func (s *SequentialThinkingServer) exportSession(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.exportDir == "" {
+		return s.fail(ctx, request, &ValidationError{
+			Code:     "EXPORT_DISABLED",
+			Expected: "export directory set with GOTHINK_EXPORT_DIR",
+			Message:  "session export is disabled: set GOTHINK_EXPORT_DIR",
+		})
+	}
+
+	name := request.GetString("name", "session-"+time.Now().UTC().Format("20060102-150405"))
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return s.fail(ctx, request, invalid("name", "plain directory name", name, "must be a plain directory name"))
+	}
+
+	dir := filepath.Join(s.exportDir, name)
+	if format := request.GetString("format", "bundle"); format == "obsidian" {
+		notes, err := s.ExportObsidian(dir)
+		if err != nil {
+			return s.fail(ctx, request, invalid("name", "directory that can be written", name, "export failed: %v", err))
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.respond(ctx, request, map[string]any{
+			"name":      name,
+			"directory": dir,
+			"notes":     notes,
+		})
+	} else if format != "bundle" {
+		return s.fail(ctx, request, invalid("format", "bundle or obsidian", format, "must be bundle or obsidian"))
+	}
+
+	manifest, err := s.ExportBundle(dir, name)
+	if err != nil {
+		return s.fail(ctx, request, invalid("name", "directory that can be written", name, "export failed: %v", err))
+	}
+
+	uris := make([]string, 0, len(manifest.Resources))
+	for _, r := range manifest.Resources {
+		uris = append(uris, r.URI)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.respond(ctx, request, map[string]any{
+		"name":      name,
+		"directory": dir,
+		"resources": uris,
+	})
+}
@@ -0,0 +1,43 @@
+package thinking
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// defaultMaxHistory caps the thoughts a session holds unless
+// GOTHINK_MAX_HISTORY or WithMaxHistory sets another cap.
+const defaultMaxHistory = 10000
+
+// WithMaxHistory caps the thoughts a session holds, overriding
+// GOTHINK_MAX_HISTORY. Once it is full, new thoughts are refused until the
+// history is cleared or restored to a smaller checkpoint.
+func WithMaxHistory(n int) Option {
+	return func(s *SequentialThinkingServer) {
+		s.maxHistory = n
+	}
+}
+
+func maxHistoryFromEnv() int {
+	if n, err := strconv.Atoi(os.Getenv("GOTHINK_MAX_HISTORY")); err == nil && n > 0 {
+		return n
+	}
+	return defaultMaxHistory
+}
+
+// checkCapacity refuses n more thoughts if they would take the history past
+// the cap, advising the agent how to go on.
+func (s *SequentialThinkingServer) checkCapacity(n int) error {
+	if len(s.thoughtHistory)+n <= s.maxHistory {
+		return nil
+	}
+	return &ValidationError{
+		Code:     "HISTORY_FULL",
+		Expected: fmt.Sprintf("at most %d thoughts in the session", s.maxHistory),
+		Received: len(s.thoughtHistory) + n,
+		Message: fmt.Sprintf("history full: the session holds %d of at most %d thoughts; "+
+			"call summarize_thoughts to condense it, checkpoint it if you may need it again, then clear_history to continue",
+			len(s.thoughtHistory), s.maxHistory),
+	}
+}
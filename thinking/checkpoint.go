@@ -0,0 +1,201 @@
+package thinking
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// snapshot is a deep copy of the session state that can be restored later.
+type snapshot struct {
+	sessionID      string
+	thoughtHistory []ThoughtData
+	branches       map[string]*Branch
+	finalAnswer    *FinalAnswer
+	hypotheses     []Hypothesis
+	hypothesisSeq  int
+	assumptions    []Assumption
+	assumptionSeq  int
+	questions      []Question
+	questionSeq    int
+	decisions      []DecisionMatrix
+	decisionSeq    int
+	models         []ModelApplication
+	modelSeq       int
+	debugSessions  []DebugSession
+	debugSeq       int
+	inquiries      []Inquiry
+	inquirySeq     int
+	arguments      []ArgumentNode
+	argumentSeq    int
+	assessments    []KnowledgeAssessment
+	reviews        []Review
+	reviewSeq      int
+}
+
+func (s *SequentialThinkingServer) snapshot() snapshot {
+	snap := snapshot{
+		sessionID:      s.sessionID,
+		thoughtHistory: append([]ThoughtData(nil), s.thoughtHistory...),
+		branches:       make(map[string]*Branch, len(s.branches)),
+		finalAnswer:    s.finalAnswer,
+		hypothesisSeq:  s.hypothesisSeq,
+		assumptions:    slices.Clone(s.assumptions),
+		assumptionSeq:  s.assumptionSeq,
+		questions:      slices.Clone(s.questions),
+		questionSeq:    s.questionSeq,
+		decisionSeq:    s.decisionSeq,
+		modelSeq:       s.modelSeq,
+		debugSeq:       s.debugSeq,
+		inquirySeq:     s.inquirySeq,
+		arguments:      slices.Clone(s.arguments),
+		argumentSeq:    s.argumentSeq,
+		reviews:        slices.Clone(s.reviews),
+		reviewSeq:      s.reviewSeq,
+	}
+	for _, h := range s.hypotheses {
+		snap.hypotheses = append(snap.hypotheses, h.clone())
+	}
+	for _, d := range s.decisions {
+		snap.decisions = append(snap.decisions, d.clone())
+	}
+	for _, a := range s.modelApplications {
+		snap.models = append(snap.models, a.clone())
+	}
+	for _, d := range s.debugSessions {
+		snap.debugSessions = append(snap.debugSessions, d.clone())
+	}
+	for _, q := range s.inquiries {
+		snap.inquiries = append(snap.inquiries, q.clone())
+	}
+	for _, k := range s.assessments {
+		snap.assessments = append(snap.assessments, k.clone())
+	}
+	for id, b := range s.branches {
+		snap.branches[id] = b.clone()
+	}
+	return snap
+}
+
+func (s *SequentialThinkingServer) restore(snap snapshot) {
+	s.sessionID = snap.sessionID
+	s.thoughtHistory = append(make([]ThoughtData, 0, len(snap.thoughtHistory)), snap.thoughtHistory...)
+	s.branches = make(map[string]*Branch, len(snap.branches))
+	for id, b := range snap.branches {
+		s.branches[id] = b.clone()
+	}
+	s.finalAnswer = snap.finalAnswer
+	s.hypotheses = nil
+	for _, h := range snap.hypotheses {
+		s.hypotheses = append(s.hypotheses, h.clone())
+	}
+	s.hypothesisSeq = snap.hypothesisSeq
+	s.assumptions = slices.Clone(snap.assumptions)
+	s.assumptionSeq = snap.assumptionSeq
+	s.questions = slices.Clone(snap.questions)
+	s.questionSeq = snap.questionSeq
+	s.decisions = nil
+	for _, d := range snap.decisions {
+		s.decisions = append(s.decisions, d.clone())
+	}
+	s.decisionSeq = snap.decisionSeq
+	s.modelApplications = nil
+	for _, a := range snap.models {
+		s.modelApplications = append(s.modelApplications, a.clone())
+	}
+	s.modelSeq = snap.modelSeq
+	s.debugSessions = nil
+	for _, d := range snap.debugSessions {
+		s.debugSessions = append(s.debugSessions, d.clone())
+	}
+	s.debugSeq = snap.debugSeq
+	s.inquiries = nil
+	for _, q := range snap.inquiries {
+		s.inquiries = append(s.inquiries, q.clone())
+	}
+	s.inquirySeq = snap.inquirySeq
+	s.arguments = slices.Clone(snap.arguments)
+	s.argumentSeq = snap.argumentSeq
+	s.reviews = slices.Clone(snap.reviews)
+	s.reviewSeq = snap.reviewSeq
+	s.assessments = nil
+	for _, k := range snap.assessments {
+		s.assessments = append(s.assessments, k.clone())
+	}
+}
+
+func (s *SequentialThinkingServer) checkpointLabels() []string {
+	labels := make([]string, 0, len(s.checkpoints))
+	for label := range s.checkpoints {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	return labels
+}
+
+func (s *SequentialThinkingServer) checkpoint(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	label, err := request.RequireString("label")
+	if err != nil || label == "" {
+		return s.fail(ctx, request, invalid("label", "non-empty string", request.GetArguments()["label"], "must be a non-empty string"))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, replaced := s.checkpoints[label]
+	s.checkpoints[label] = s.snapshot()
+	s.notifyWatchers()
+
+	if !s.disableThoughtLogging {
+		s.logEvent(s.theme.Record, fmt.Sprintf("📌 Checkpoint %q at %d thoughts", label, len(s.thoughtHistory)), "")
+	}
+
+	return s.respond(ctx, request, map[string]any{
+		"label":                label,
+		"replaced":             replaced,
+		"thoughtHistoryLength": len(s.thoughtHistory),
+		"checkpoints":          s.checkpointLabels(),
+	})
+}
+
+func (s *SequentialThinkingServer) restoreCheckpoint(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	label, err := request.RequireString("label")
+	if err != nil || label == "" {
+		return s.fail(ctx, request, invalid("label", "non-empty string", request.GetArguments()["label"], "must be a non-empty string"))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snap, ok := s.checkpoints[label]
+	if !ok {
+		labels := s.checkpointLabels()
+		if len(labels) == 0 {
+			return s.fail(ctx, request, invalid("label", "recorded checkpoint label", label, "unknown checkpoint %q: no checkpoints recorded", label))
+		}
+		return s.fail(ctx, request, invalid("label", "one of "+strings.Join(labels, ", "), label,
+			"unknown checkpoint %q: available checkpoints are %s", label, strings.Join(labels, ", ")))
+	}
+
+	discarded := max(len(s.thoughtHistory)-len(snap.thoughtHistory), 0)
+	s.restore(snap)
+	s.generation++
+	// Remembered calls may have recorded thoughts the checkpoint lacks.
+	s.calls = nil
+	s.resourcesChanged()
+
+	if !s.disableThoughtLogging {
+		s.logEvent(s.theme.Record, fmt.Sprintf("⏪ Restored checkpoint %q (%d thoughts)", label, len(s.thoughtHistory)), "")
+	}
+
+	return s.respond(ctx, request, map[string]any{
+		"restored":             label,
+		"thoughtHistoryLength": len(s.thoughtHistory),
+		"thoughtsDiscarded":    discarded,
+		"branches":             s.branchNames(),
+	})
+}
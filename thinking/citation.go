@@ -0,0 +1,97 @@
+package thinking
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Kinds of citation a thought can give as evidence.
+const (
+	CitationURL      = "url"
+	CitationFile     = "file"
+	CitationResource = "resource"
+)
+
+var citationKinds = []string{CitationURL, CitationFile, CitationResource}
+
+// citationKind classifies a citation: a web URL, a file path (or file: URL),
+// or the URI of some other resource, such as an MCP resource.
+func citationKind(ref string) string {
+	u, err := url.Parse(ref)
+	switch {
+	case err != nil || len(u.Scheme) < 2:
+		// No scheme, or a Windows drive letter.
+		return CitationFile
+	case u.Scheme == "http" || u.Scheme == "https":
+		return CitationURL
+	case u.Scheme == "file":
+		return CitationFile
+	}
+	return CitationResource
+}
+
+// citationHref returns the link target of a citation: the citation itself,
+// or a file: URL for absolute paths.
+func citationHref(ref string) string {
+	if citationKind(ref) == CitationFile && filepath.IsAbs(ref) {
+		return (&url.URL{Scheme: "file", Path: filepath.ToSlash(ref)}).String()
+	}
+	return ref
+}
+
+// markdownCitations renders citations as a line of Markdown links.
+func markdownCitations(citations []string) string {
+	links := make([]string, len(citations))
+	for i, ref := range citations {
+		links[i] = fmt.Sprintf("[%s](<%s>)", markdownEscaper.Replace(ref), citationHref(ref))
+	}
+	return strings.Join(links, ", ")
+}
+
+var markdownEscaper = strings.NewReplacer(`\`, `\\`, `[`, `\[`, `]`, `\]`)
+
+// Citation is a reference cited by one or more thoughts.
+type Citation struct {
+	Ref      string       `json:"ref"`
+	Kind     string       `json:"kind"`
+	Thoughts []ThoughtRef `json:"thoughts"`
+}
+
+func (s *SequentialThinkingServer) getCitations(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	kind := request.GetString("kind", "")
+	if kind != "" && !slices.Contains(citationKinds, kind) {
+		return s.fail(ctx, request, invalid("kind", "one of "+strings.Join(citationKinds, ", "), kind, "must be one of %s", strings.Join(citationKinds, ", ")))
+	}
+	query := strings.ToLower(request.GetString("query", ""))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	citations := make([]Citation, 0)
+	index := make(map[string]int)
+	for i := range s.thoughtHistory {
+		t := &s.thoughtHistory[i]
+		for _, ref := range t.Citations {
+			if kind != "" && citationKind(ref) != kind || !strings.Contains(strings.ToLower(ref), query) {
+				continue
+			}
+			j, ok := index[ref]
+			if !ok {
+				j = len(citations)
+				index[ref] = j
+				citations = append(citations, Citation{Ref: ref, Kind: citationKind(ref)})
+			}
+			citations[j].Thoughts = append(citations[j].Thoughts, refTo(t, t.Thought))
+		}
+	}
+
+	return s.respond(ctx, request, map[string]any{
+		"citations": citations,
+	})
+}
@@ -0,0 +1,96 @@
+package thinking
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// maxCompletions is the most values a completion may return.
+const maxCompletions = 100
+
+// completion returns the candidates starting with prefix.
+func completion(candidates []string, prefix string) *mcp.Completion {
+	values := make([]string, 0)
+	for _, c := range candidates {
+		if strings.HasPrefix(c, prefix) {
+			values = append(values, c)
+		}
+	}
+	result := &mcp.Completion{Values: values, Total: len(values)}
+	if len(values) > maxCompletions {
+		result.Values, result.HasMore = values[:maxCompletions], true
+	}
+	return result
+}
+
+// revisableThoughts lists the main-line thoughts that haven't been revised.
+func (s *SequentialThinkingServer) revisableThoughts() []string {
+	live := s.liveThoughts()
+	numbers := make([]string, 0)
+	for i := range s.thoughtHistory {
+		if t := &s.thoughtHistory[i]; branchOf(t) == "" && live(t) {
+			numbers = append(numbers, strconv.Itoa(t.ThoughtNumber))
+		}
+	}
+	return numbers
+}
+
+// CompletePromptArgument suggests branch IDs and revisable thought numbers for
+// the workflow prompts. It implements server.PromptCompletionProvider.
+func (s *SequentialThinkingServer) CompletePromptArgument(ctx context.Context, promptName string, argument mcp.CompleteArgument, context mcp.CompleteContext) (*mcp.Completion, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case promptName == "branch-comparison" && argument.Name == "branches":
+		// Complete the last ID of the comma-separated list.
+		i := strings.LastIndex(argument.Value, ",") + 1
+		listed, last := strings.Split(argument.Value[:i], ","), strings.TrimSpace(argument.Value[i:])
+		candidates := make([]string, 0)
+		for _, id := range s.branchNames() {
+			if !slices.Contains(listed, id) {
+				candidates = append(candidates, argument.Value[:i]+id)
+			}
+		}
+		return completion(candidates, argument.Value[:i]+last), nil
+	case promptName == "review-and-revise" && argument.Name == "thoughtNumber":
+		return completion(s.revisableThoughts(), argument.Value), nil
+	}
+	return completion(nil, ""), nil
+}
+
+// CompleteResourceArgument suggests branch IDs for the branch resource
+// template and the session ID and main-line thought numbers for the thought
+// template. It implements server.ResourceCompletionProvider.
+func (s *SequentialThinkingServer) CompleteResourceArgument(ctx context.Context, uri string, argument mcp.CompleteArgument, context mcp.CompleteContext) (*mcp.Completion, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if uri == branchURITemplate && argument.Name == "branchId" {
+		ids := make([]string, 0, len(s.branches))
+		for id := range s.branches {
+			ids = append(ids, id)
+		}
+		slices.Sort(ids)
+		return completion(ids, argument.Value), nil
+	}
+	if uri == thoughtURITemplate {
+		switch argument.Name {
+		case "sid":
+			return completion([]string{s.sessionID}, argument.Value), nil
+		case "n":
+			numbers := make([]string, 0)
+			for _, t := range s.thoughtHistory {
+				if n := strconv.Itoa(t.ThoughtNumber); branchOf(&t) == "" && !slices.Contains(numbers, n) {
+					numbers = append(numbers, n)
+				}
+			}
+			return completion(numbers, argument.Value), nil
+		}
+	}
+	return completion(nil, ""), nil
+}
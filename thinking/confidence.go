@@ -0,0 +1,63 @@
+package thinking
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ConfidenceSummary aggregates the confidence thoughts were given, over the
+// thoughts that still stand.
+type ConfidenceSummary struct {
+	Rated   int     `json:"rated"`
+	Average float64 `json:"average"`
+	Lowest  float64 `json:"lowest"`
+	// Low lists the thoughts rated below 0.5.
+	Low []ThoughtRef `json:"low"`
+}
+
+// confidenceSummary aggregates the confidence of the standing thoughts, or
+// returns nil if none was rated.
+func (s *SequentialThinkingServer) confidenceSummary() *ConfidenceSummary {
+	live := s.liveThoughts()
+	summary := &ConfidenceSummary{Low: make([]ThoughtRef, 0), Lowest: 1}
+	var total float64
+	for i := range s.thoughtHistory {
+		t := &s.thoughtHistory[i]
+		if t.Confidence == nil || !live(t) {
+			continue
+		}
+		summary.Rated++
+		total += *t.Confidence
+		summary.Lowest = min(summary.Lowest, *t.Confidence)
+		if *t.Confidence < lowConfidence {
+			summary.Low = append(summary.Low, refTo(t, t.Thought))
+		}
+	}
+	if summary.Rated == 0 {
+		return nil
+	}
+	summary.Average = total / float64(summary.Rated)
+	return summary
+}
+
+// lowConfidenceWarning flags the standing thoughts rated below 0.5 when the
+// chain concludes, or returns "" if there are none.
+func (s *SequentialThinkingServer) lowConfidenceWarning() string {
+	live := s.liveThoughts()
+	var low []string
+	for i := range s.thoughtHistory {
+		t := &s.thoughtHistory[i]
+		if t.Confidence == nil || *t.Confidence >= lowConfidence || !live(t) {
+			continue
+		}
+		label := fmt.Sprint(t.ThoughtNumber)
+		if id := branchOf(t); id != "" {
+			label += " [" + id + "]"
+		}
+		low = append(low, fmt.Sprintf("%s (%.2g)", label, *t.Confidence))
+	}
+	if len(low) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("concluding with low-confidence thoughts: %s", strings.Join(low, ", "))
+}
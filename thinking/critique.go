@@ -0,0 +1,107 @@
+package thinking
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// gapThreshold is the jump in thought numbers, within one line, above which
+	// steps are considered lost.
+	gapThreshold = 2
+	// shortThoughtLength is the rune count below which a thought is too thin
+	// to carry reasoning.
+	shortThoughtLength = 20
+)
+
+var verificationPattern = regexp.MustCompile(`(?i)\b(verif\w*|check\w*|confirm\w*|validat\w*|test\w*|double-check\w*)\b`)
+
+// Weakness is a problem critique_chain found in the chain.
+type Weakness struct {
+	Kind     string `json:"kind"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	Thoughts []int  `json:"thoughts,omitempty"`
+}
+
+// critique runs the heuristics over the session, most severe findings first.
+func (s *SequentialThinkingServer) critique() []Weakness {
+	weaknesses := make([]Weakness, 0)
+	add := func(kind, severity, message string, thoughts ...int) {
+		weaknesses = append(weaknesses, Weakness{Kind: kind, Severity: severity, Message: message, Thoughts: thoughts})
+	}
+
+	if len(s.thoughtHistory) == 0 {
+		add("empty_chain", "high", "no thoughts have been recorded")
+		return weaknesses
+	}
+
+	verified := s.countHypotheses(HypothesisConfirmed)+s.countHypotheses(HypothesisRefuted) > 0
+	for _, t := range s.thoughtHistory {
+		verified = verified || verificationPattern.MatchString(t.Thought)
+	}
+	if !verified {
+		add("no_verification", "high", "no thought verifies or checks the reasoning, and no hypothesis was confirmed or refuted")
+	}
+
+	for _, h := range s.hypotheses {
+		if h.Status == HypothesisOpen {
+			add("open_hypothesis", "medium", fmt.Sprintf("hypothesis %s was never confirmed or refuted: %s", h.ID, excerpt(h.Statement)))
+		}
+	}
+	for _, a := range s.assumptions {
+		if a.Status == AssumptionUnverified {
+			add("unvalidated_assumption", "medium", fmt.Sprintf("assumption %s is still unverified: %s", a.ID, excerpt(a.Statement)))
+		}
+	}
+	for _, q := range s.openQuestions() {
+		add("open_question", "medium", fmt.Sprintf("question %s is unanswered: %s", q.ID, excerpt(q.Question)))
+	}
+
+	for _, id := range s.branchNames() {
+		if _, ok := s.mergedInto(id); !ok {
+			thoughts := s.branches[id].Thoughts
+			add("unresolved_branch", "medium",
+				fmt.Sprintf("branch %s was never merged or abandoned", id), thoughts[len(thoughts)-1].ThoughtNumber)
+		}
+	}
+
+	last := make(map[string]int)
+	for i := range s.thoughtHistory {
+		t := &s.thoughtHistory[i]
+		line := branchOf(t)
+		if prev, ok := last[line]; ok && t.ThoughtNumber-prev > gapThreshold {
+			add("numbering_gap", "low",
+				fmt.Sprintf("%s jumps from thought %d to %d", describeScope(line), prev, t.ThoughtNumber), prev, t.ThoughtNumber)
+		}
+		last[line] = t.ThoughtNumber
+	}
+
+	var short []int
+	for _, t := range s.thoughtHistory {
+		if utf8.RuneCountInString(strings.TrimSpace(t.Thought)) < shortThoughtLength {
+			short = append(short, t.ThoughtNumber)
+		}
+	}
+	if len(short) > 0 {
+		add("short_thoughts", "low", fmt.Sprintf("%d of %d thoughts have fewer than %d characters", len(short), len(s.thoughtHistory), shortThoughtLength), short...)
+	}
+
+	return weaknesses
+}
+
+func (s *SequentialThinkingServer) critiqueChain(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	weaknesses := s.critique()
+	return s.respond(ctx, request, map[string]any{
+		"weaknesses": weaknesses,
+		"count":      len(weaknesses),
+	})
+}
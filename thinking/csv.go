@@ -0,0 +1,70 @@
+package thinking
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// csvHeader names the columns of the CSV export.
+var csvHeader = []string{"session_id", "thought_number", "branch", "is_revision", "timestamp", "length", "type", "thought_type", "tags", "metadata", "thought_id", "status", "author", "citations", "uncertainties"}
+
+// thoughtKind classifies a thought by how it relates to the rest of the
+// chain: a merge, the final answer, a revision, a thought on a branch, or a
+// plain thought.
+func thoughtKind(t *ThoughtData) string {
+	switch {
+	case t.MergedBranchId != nil:
+		return "merge"
+	case t.IsFinalAnswer != nil && *t.IsFinalAnswer:
+		return "answer"
+	case t.IsRevision != nil && *t.IsRevision:
+		return "revision"
+	case branchOf(t) != "":
+		return "branch"
+	}
+	return "thought"
+}
+
+// writeCSV writes a row of metadata per thought, in recording order, for
+// spreadsheet analysis. The session ID column tells apart rows from several
+// sessions pasted into one sheet, and the timestamp is when the server
+// received the thought.
+func (s *SequentialThinkingServer) writeCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for i := range s.thoughtHistory {
+		t := &s.thoughtHistory[i]
+		isRevision := t.IsRevision != nil && *t.IsRevision
+		timestamp := ""
+		if t.ReceivedAt != nil {
+			timestamp = t.ReceivedAt.UTC().Format(time.RFC3339Nano)
+		}
+		if err := cw.Write([]string{
+			s.sessionID,
+			strconv.Itoa(t.ThoughtNumber),
+			branchOf(t),
+			strconv.FormatBool(isRevision),
+			timestamp,
+			strconv.Itoa(utf8.RuneCountInString(t.Thought)),
+			thoughtKind(t),
+			thoughtTypeOf(t),
+			strings.Join(t.Tags, ";"),
+			metadataJSON(t),
+			t.Id,
+			t.Status,
+			t.Author,
+			strings.Join(t.Citations, " "),
+			strings.Join(t.Uncertainties, ";"),
+		}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,383 @@
+package thinking
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dashboardExtensions names downloads from the export buttons; other
+// formats use the format name.
+var dashboardExtensions = map[string]string{
+	"markdown": "md",
+	"mermaid":  "mmd",
+	"messages": "messages.json",
+}
+
+type dashboardSession struct {
+	Label    string
+	Thoughts int
+	Branches int
+	Current  bool
+}
+
+type dashboardPanel struct {
+	SessionID string
+	Thoughts  int
+	Branches  int
+	Status    string
+	Graph     template.HTML
+	Lines     []htmlLine
+	Sessions  []dashboardSession
+}
+
+var dashboardTemplate = template.Must(template.New("dashboard").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>gothink dashboard</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 0; color: #202124; }
+header { display: flex; align-items: center; gap: 1rem; padding: .6rem 1.2rem; background: #202124; color: #fff; }
+header h1 { font-size: 1.1rem; margin: 0; }
+header .live { margin-left: auto; font-size: .85em; color: #9aa0a6; }
+header .live.on { color: #81c995; }
+main { display: grid; grid-template-columns: 16rem 1fr; gap: 1.5rem; padding: 1rem 1.2rem; }
+aside h2, section h2 { font-size: .95rem; text-transform: uppercase; letter-spacing: .05em; color: #5f6368; }
+aside ul { list-style: none; padding: 0; }
+aside li { padding: .3rem .5rem; border-radius: .3rem; }
+aside li.current { background: #e8f0fe; font-weight: 600; }
+.exports a { display: inline-block; margin: .2rem .2rem 0 0; padding: .25rem .6rem; border: 1px solid #dadce0; border-radius: .3rem; color: #1a73e8; text-decoration: none; font-size: .9em; }
+.graph { overflow-x: auto; border: 1px solid #dadce0; border-radius: .4rem; }
+.graph svg { display: block; }
+.graph .sequence, .graph .branch { stroke: #9aa0a6; }
+.graph .revision { stroke: #d39e00; stroke-dasharray: 4 3; }
+.graph .merge { stroke: #8e44ad; stroke-width: 2; }
+.graph circle { fill: #e8f0fe; stroke: #1a73e8; }
+.graph circle.revision { fill: #fff3cd; stroke: #d39e00; }
+.graph circle.merge { fill: #faf5fd; stroke: #8e44ad; }
+.graph text { font-size: 11px; text-anchor: middle; dominant-baseline: central; }
+.graph text.lane { text-anchor: start; fill: #5f6368; }
+details { border: 1px solid #dadce0; border-radius: .4rem; margin: .5rem 0; padding: .5rem .8rem; }
+details.revision { border-color: #d39e00; background: #fffbea; }
+details.merge { border-color: #8e44ad; background: #faf5fd; }
+summary { cursor: pointer; font-weight: 600; }
+.context, .note { color: #5f6368; font-weight: normal; }
+.text { white-space: pre-wrap; }
+.diff { font-size: .9em; color: #5f6368; }
+del { background: #fad2cf; } ins { background: #ceead6; text-decoration: none; }
+</style>
+</head>
+<body>
+<header><h1>gothink</h1><span id="session"></span><span class="live" id="live">connecting…</span></header>
+<main>
+<aside>
+<h2>Export</h2>
+<div class="exports">{{range .}}<a href="export/{{.}}" download>{{.}}</a>{{end}}</div>
+<div id="sessions"></div>
+</aside>
+<section id="panel"></section>
+</main>
+<script>
+const live = document.getElementById("live");
+async function refresh() {
+  const response = await fetch("panel");
+  const doc = new DOMParser().parseFromString(await response.text(), "text/html");
+  const open = new Set([...document.querySelectorAll("#panel details[open]")].map(d => d.id));
+  for (const id of ["session", "sessions", "panel"]) {
+    document.getElementById(id).innerHTML = doc.getElementById(id).innerHTML;
+  }
+  for (const id of open) {
+    const d = document.getElementById(id);
+    if (d) d.open = true;
+  }
+}
+const events = new EventSource("events");
+events.addEventListener("update", refresh);
+events.onopen = () => { live.textContent = "live"; live.className = "live on"; };
+events.onerror = () => { live.textContent = "disconnected"; live.className = "live"; };
+refresh();
+</script>
+</body>
+</html>
+`))
+
+var dashboardPanelTemplate = template.Must(template.New("panel").Parse(`<span id="session">session {{.SessionID}} · thoughts: {{.Thoughts}} · branches: {{.Branches}} · {{.Status}}</span>
+<div id="sessions">
+<h2>Sessions</h2>
+<ul>{{range .Sessions}}<li{{if .Current}} class="current"{{end}}>{{.Label}} <span class="note">thoughts: {{.Thoughts}}, branches: {{.Branches}}</span></li>{{end}}</ul>
+</div>
+<section id="panel">
+<h2>Branch graph</h2>
+<div class="graph">{{.Graph}}</div>
+{{range $l, $line := .Lines}}<h2>{{$line.Title}}</h2>
+{{with $line.Note}}<p class="note">{{.}}</p>
+{{end}}{{range $i, $t := $line.Thoughts}}<details id="t{{$l}}-{{$i}}" class="{{$t.Kind}}"><summary>{{$t.Number}}/{{$t.Total}} <span class="context">{{$t.Context}}</span></summary>
+<div class="text">{{$t.Text}}</div>
+{{with $t.Diff}}<p class="diff">{{range .}}{{if eq .Op "-"}}<del>{{.Text}}</del>{{else if eq .Op "+"}}<ins>{{.Text}}</ins>{{else}}{{.Text}}{{end}} {{end}}</p>
+{{end}}</details>
+{{end}}{{end}}</section>
+`))
+
+// Dashboard returns a handler serving a local web UI for the session: the
+// thoughts of every line, the branch graph, the checkpoints, and export
+// buttons for every render format. Pages update live over server-sent
+// events; /thoughts streams the thoughts themselves for gothink tail.
+func (s *SequentialThinkingServer) Dashboard() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		formats := s.renderFormats()
+		s.mu.Unlock()
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		dashboardTemplate.Execute(w, formats)
+	})
+	mux.HandleFunc("GET /panel", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		panel := s.dashboardPanel()
+		s.mu.Unlock()
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		dashboardPanelTemplate.Execute(w, panel)
+	})
+	mux.HandleFunc("GET /export/{format}", func(w http.ResponseWriter, r *http.Request) {
+		format := r.PathValue("format")
+		s.mu.Lock()
+		document, mimeType, err := s.render(format)
+		sessionID := s.sessionID
+		s.mu.Unlock()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		extension, ok := dashboardExtensions[format]
+		if !ok {
+			extension = format
+		}
+		w.Header().Set("Content-Type", mimeType+"; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "session-"+sessionID+"."+extension))
+		fmt.Fprint(w, document)
+	})
+	mux.HandleFunc("GET /events", s.dashboardEvents)
+	mux.HandleFunc("GET /thoughts", s.streamThoughts)
+	return mux
+}
+
+// dashboardEvents streams an update event whenever the session changes, and
+// a comment every 30 seconds to keep proxies from closing the stream.
+func (s *SequentialThinkingServer) dashboardEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	changes := s.watch()
+	defer s.unwatch(changes)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	fmt.Fprint(w, "retry: 2000\n\n")
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(30 * time.Second)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-changes:
+			fmt.Fprint(w, "event: update\ndata: {}\n\n")
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+		}
+		flusher.Flush()
+	}
+}
+
+// watch returns a channel that receives a value after the session changes.
+// Changes arriving while one is pending are coalesced.
+func (s *SequentialThinkingServer) watch() chan struct{} {
+	s.subMu.Lock()
+	defer s.subMu.Unlock()
+
+	c := make(chan struct{}, 1)
+	if s.watchers == nil {
+		s.watchers = make(map[chan struct{}]bool)
+	}
+	s.watchers[c] = true
+	return c
+}
+
+func (s *SequentialThinkingServer) unwatch(c chan struct{}) {
+	s.subMu.Lock()
+	defer s.subMu.Unlock()
+
+	delete(s.watchers, c)
+}
+
+// notifyWatchers wakes every watcher.
+func (s *SequentialThinkingServer) notifyWatchers() {
+	s.subMu.Lock()
+	defer s.subMu.Unlock()
+
+	for c := range s.watchers {
+		select {
+		case c <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (s *SequentialThinkingServer) dashboardPanel() dashboardPanel {
+	panel := dashboardPanel{
+		SessionID: s.sessionID,
+		Thoughts:  len(s.thoughtHistory),
+		Branches:  len(s.branches),
+		Status:    "empty",
+		Graph:     s.dashboardGraph(),
+		Lines:     s.htmlLines(),
+		Sessions: []dashboardSession{{
+			Label:    "current",
+			Thoughts: len(s.thoughtHistory),
+			Branches: len(s.branches),
+			Current:  true,
+		}},
+	}
+	switch {
+	case s.finalAnswer != nil:
+		panel.Status = "answered"
+	case len(s.thoughtHistory) > 0 && !s.thoughtHistory[len(s.thoughtHistory)-1].NextThoughtNeeded:
+		panel.Status = "concluded"
+	case len(s.thoughtHistory) > 0:
+		panel.Status = "thinking"
+	}
+	for _, label := range s.checkpointLabels() {
+		snap := s.checkpoints[label]
+		panel.Sessions = append(panel.Sessions, dashboardSession{
+			Label:    "checkpoint " + label,
+			Thoughts: len(snap.thoughtHistory),
+			Branches: len(snap.branches),
+		})
+	}
+	return panel
+}
+
+// dashboardGraph draws the thought graph as SVG, with a lane per line and
+// thoughts placed left to right in the order they were recorded.
+func (s *SequentialThinkingServer) dashboardGraph() template.HTML {
+	const step, laneHeight, left, top, radius = 44, 44, 110, 28, 14
+
+	lanes := map[string]int{"": 0}
+	names := []string{"main"}
+	for _, id := range s.allBranches() {
+		lanes[id] = len(names)
+		names = append(names, id)
+	}
+	x := func(i int) int { return left + i*step }
+	y := func(i int) int { return top + lanes[branchOf(&s.thoughtHistory[i])]*laneHeight }
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d">`,
+		x(len(s.thoughtHistory)), top+len(names)*laneHeight-radius)
+	for lane, name := range names {
+		fmt.Fprintf(&b, `<text class="lane" x="8" y="%d">%s</text>`, top+lane*laneHeight, template.HTMLEscapeString(name))
+	}
+	children, _ := s.thoughtGraph()
+	for _, edges := range children {
+		for _, e := range edges {
+			fmt.Fprintf(&b, `<line class="%s" x1="%d" y1="%d" x2="%d" y2="%d"/>`, e.kind, x(e.from), y(e.from), x(e.to), y(e.to))
+		}
+	}
+	for i := range s.thoughtHistory {
+		t := &s.thoughtHistory[i]
+		kind := "thought"
+		switch {
+		case t.MergedBranchId != nil:
+			kind = "merge"
+		case t.IsRevision != nil && *t.IsRevision:
+			kind = "revision"
+		}
+		fmt.Fprintf(&b, `<g><title>%s</title><circle class="%s" cx="%d" cy="%d" r="%d"/><text x="%d" y="%d">%d</text></g>`,
+			template.HTMLEscapeString(graphLabel(t)), kind, x(i), y(i), radius, x(i), y(i), t.ThoughtNumber)
+	}
+	b.WriteString("</svg>")
+	return template.HTML(b.String())
+}
+
+// streamThoughts streams the thoughts recorded from now on as thought
+// events, preceded by the last ones already recorded (all of them unless the
+// last query parameter limits them). Event IDs carry the session ID, the
+// history generation and the thought's position in the history, so a client
+// reconnecting with Last-Event-ID resumes where it left off. When the
+// history is replaced or rewritten, by clear_history, a checkpoint restore,
+// a session load, split_thought or repair_sequence, a reset event carrying
+// the session ID precedes the thoughts sent again. Nothing is written to the
+// client while the server is locked, so a stalled client can't hold up tool
+// calls.
+func (s *SequentialThinkingServer) streamThoughts(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	changes := s.watch()
+	defer s.unwatch(changes)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	fmt.Fprint(w, "retry: 2000\n\n")
+
+	s.mu.Lock()
+	sessionID, generation, sent := s.sessionID, s.generation, 0
+	stream := fmt.Sprintf("%s/%d", sessionID, generation)
+	if last, err := strconv.Atoi(r.URL.Query().Get("last")); err == nil && last >= 0 {
+		sent = max(len(s.thoughtHistory)-last, 0)
+	}
+	reset := false
+	if id, n, ok := strings.Cut(r.Header.Get("Last-Event-ID"), ":"); ok {
+		if resumed, err := strconv.Atoi(n); err == nil && id == stream && resumed <= len(s.thoughtHistory) {
+			sent = resumed
+		} else if id != stream {
+			reset, sent = true, 0
+		}
+	}
+	s.mu.Unlock()
+	if reset {
+		fmt.Fprintf(w, "event: reset\ndata: %q\n\n", sessionID)
+	}
+
+	heartbeat := time.NewTicker(30 * time.Second)
+	defer heartbeat.Stop()
+	for {
+		s.mu.Lock()
+		reset := s.sessionID != sessionID || s.generation != generation || len(s.thoughtHistory) < sent
+		if reset {
+			sessionID, generation, sent = s.sessionID, s.generation, 0
+		}
+		pending := slices.Clone(s.thoughtHistory[sent:])
+		s.mu.Unlock()
+
+		stream := fmt.Sprintf("%s/%d", sessionID, generation)
+		if reset {
+			fmt.Fprintf(w, "event: reset\ndata: %q\n\n", sessionID)
+		}
+		for i := range pending {
+			sent++
+			jsonBytes, _ := json.Marshal(&pending[i])
+			fmt.Fprintf(w, "id: %s:%d\nevent: thought\ndata: %s\n\n", stream, sent, jsonBytes)
+		}
+		flusher.Flush()
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-changes:
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+		}
+	}
+}
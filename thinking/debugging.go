@@ -0,0 +1,126 @@
+package thinking
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// debuggingApproaches describes the supported debugging strategies.
+var debuggingApproaches = map[string]string{
+	"binary_search":      "Halve the search space (inputs, commits, code paths) until the fault is isolated.",
+	"divide_and_conquer": "Split the system into parts and check each one separately.",
+	"cause_elimination":  "List the possible causes and rule them out one by one.",
+	"backtracking":       "Work backwards from the symptom to where the state first went wrong.",
+	"program_slicing":    "Keep only the code that affects the faulty value and inspect that.",
+}
+
+// DebugEntry is a step or finding, tied to the thought it came from.
+type DebugEntry struct {
+	Text          string `json:"text"`
+	ThoughtNumber int    `json:"thoughtNumber,omitempty"`
+}
+
+// DebugSession tracks the investigation of one issue with a chosen approach.
+type DebugSession struct {
+	ID         string       `json:"id"`
+	Issue      string       `json:"issue"`
+	Approach   string       `json:"approach"`
+	Steps      []DebugEntry `json:"steps"`
+	Findings   []DebugEntry `json:"findings"`
+	Resolution string       `json:"resolution,omitempty"`
+}
+
+func (d DebugSession) clone() DebugSession {
+	d.Steps = slices.Clone(d.Steps)
+	d.Findings = slices.Clone(d.Findings)
+	return d
+}
+
+func (s *SequentialThinkingServer) findDebugSession(id string) *DebugSession {
+	for i := range s.debugSessions {
+		if s.debugSessions[i].ID == id {
+			return &s.debugSessions[i]
+		}
+	}
+	return nil
+}
+
+func approachNames() []string {
+	names := make([]string, 0, len(debuggingApproaches))
+	for name := range debuggingApproaches {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func (s *SequentialThinkingServer) debuggingApproach(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	approach := request.GetString("approach", "")
+	if _, ok := debuggingApproaches[approach]; approach != "" && !ok {
+		return s.fail(ctx, request, invalid("approach", "one of "+strings.Join(approachNames(), ", "), approach,
+			"unknown approach %q, expected one of %s", approach, strings.Join(approachNames(), ", ")))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	thoughtNumber, err := s.optionalThought(request, "thoughtNumber")
+	if err != nil {
+		return s.fail(ctx, request, err)
+	}
+	if thoughtNumber == 0 && len(s.thoughtHistory) > 0 {
+		thoughtNumber = s.thoughtHistory[len(s.thoughtHistory)-1].ThoughtNumber
+	}
+
+	var d *DebugSession
+	if id := request.GetString("sessionId", ""); id != "" {
+		if d = s.findDebugSession(id); d == nil {
+			return s.fail(ctx, request, invalid("sessionId", "recorded debugging session ID", id, "unknown debugging session %q", id))
+		}
+	} else {
+		issue := request.GetString("issue", "")
+		if issue == "" || approach == "" {
+			return s.fail(ctx, request, invalid("issue", "non-empty string", request.GetArguments()["issue"], "issue and approach are required to start a debugging session"))
+		}
+		s.debugSeq++
+		s.debugSessions = append(s.debugSessions, DebugSession{
+			ID:       fmt.Sprintf("DBG%d", s.debugSeq),
+			Issue:    issue,
+			Approach: approach,
+			Steps:    make([]DebugEntry, 0),
+			Findings: make([]DebugEntry, 0),
+		})
+		d = &s.debugSessions[len(s.debugSessions)-1]
+	}
+
+	if approach != "" {
+		d.Approach = approach
+	}
+	for _, step := range request.GetStringSlice("steps", nil) {
+		d.Steps = append(d.Steps, DebugEntry{Text: step, ThoughtNumber: thoughtNumber})
+	}
+	for _, finding := range request.GetStringSlice("findings", nil) {
+		d.Findings = append(d.Findings, DebugEntry{Text: finding, ThoughtNumber: thoughtNumber})
+	}
+	if resolution := request.GetString("resolution", ""); resolution != "" {
+		d.Resolution = resolution
+	}
+
+	if !s.disableThoughtLogging {
+		status := "in progress"
+		if d.Resolution != "" {
+			status = "resolved"
+		}
+		s.logEvent(s.theme.Record, fmt.Sprintf("🐞 Debugging %s (%s): %d steps, %d findings, %s",
+			d.ID, d.Approach, len(d.Steps), len(d.Findings), status), "")
+	}
+
+	return s.respond(ctx, request, map[string]any{
+		"session":  d,
+		"strategy": debuggingApproaches[d.Approach],
+	})
+}
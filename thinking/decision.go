@@ -0,0 +1,192 @@
+package thinking
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Criterion is a weighted column of a decision matrix.
+type Criterion struct {
+	Name   string  `json:"name"`
+	Weight float64 `json:"weight"`
+}
+
+// OptionScore is an option's weighted total across all criteria.
+type OptionScore struct {
+	Option string  `json:"option"`
+	Score  float64 `json:"score"`
+}
+
+// DecisionMatrix scores the options of a decision against weighted criteria.
+// Scores maps option to criterion to score; unscored cells count as zero.
+type DecisionMatrix struct {
+	ID            string                        `json:"id"`
+	Question      string                        `json:"question"`
+	Options       []string                      `json:"options"`
+	Criteria      []Criterion                   `json:"criteria"`
+	Scores        map[string]map[string]float64 `json:"scores"`
+	Ranking       []OptionScore                 `json:"ranking"`
+	ThoughtNumber int                           `json:"thoughtNumber,omitempty"`
+}
+
+func (d DecisionMatrix) clone() DecisionMatrix {
+	d.Options = slices.Clone(d.Options)
+	d.Criteria = slices.Clone(d.Criteria)
+	d.Ranking = slices.Clone(d.Ranking)
+	scores := make(map[string]map[string]float64, len(d.Scores))
+	for option, row := range d.Scores {
+		scores[option] = maps.Clone(row)
+	}
+	d.Scores = scores
+	return d
+}
+
+// rank recomputes the weighted totals, best option first.
+func (d *DecisionMatrix) rank() {
+	d.Ranking = make([]OptionScore, 0, len(d.Options))
+	for _, option := range d.Options {
+		total := 0.0
+		for _, c := range d.Criteria {
+			total += c.Weight * d.Scores[option][c.Name]
+		}
+		d.Ranking = append(d.Ranking, OptionScore{Option: option, Score: total})
+	}
+	sort.SliceStable(d.Ranking, func(i, j int) bool { return d.Ranking[i].Score > d.Ranking[j].Score })
+}
+
+func (s *SequentialThinkingServer) findDecision(id string) *DecisionMatrix {
+	for i := range s.decisions {
+		if s.decisions[i].ID == id {
+			return &s.decisions[i]
+		}
+	}
+	return nil
+}
+
+// objects reads an optional array of objects from the request.
+func objects(request mcp.CallToolRequest, key string) ([]map[string]any, error) {
+	raw, ok := request.GetArguments()[key]
+	if !ok {
+		return nil, nil
+	}
+	items, ok := raw.([]any)
+	if !ok {
+		return nil, invalid(key, "array of objects", raw, "must be an array of objects")
+	}
+	objs := make([]map[string]any, len(items))
+	for i, item := range items {
+		if objs[i], ok = item.(map[string]any); !ok {
+			return nil, notAnObject(key, i, item)
+		}
+	}
+	return objs, nil
+}
+
+func (s *SequentialThinkingServer) decisionMatrix(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	criteria, err := objects(request, "criteria")
+	if err != nil {
+		return s.fail(ctx, request, err)
+	}
+	scores, err := objects(request, "scores")
+	if err != nil {
+		return s.fail(ctx, request, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var d DecisionMatrix
+	if id := request.GetString("decisionId", ""); id != "" {
+		existing := s.findDecision(id)
+		if existing == nil {
+			return s.fail(ctx, request, invalid("decisionId", "recorded decision ID", id, "unknown decision %q", id))
+		}
+		d = existing.clone()
+	} else {
+		question := request.GetString("question", "")
+		if question == "" {
+			return s.fail(ctx, request, invalid("question", "non-empty string", request.GetArguments()["question"], "required when creating a decision"))
+		}
+		d = DecisionMatrix{
+			ID:       fmt.Sprintf("D%d", s.decisionSeq+1),
+			Question: question,
+			Options:  make([]string, 0),
+			Criteria: make([]Criterion, 0),
+			Scores:   make(map[string]map[string]float64),
+		}
+		if len(s.thoughtHistory) > 0 {
+			d.ThoughtNumber = s.thoughtHistory[len(s.thoughtHistory)-1].ThoughtNumber
+		}
+	}
+
+	if n := request.GetInt("thoughtNumber", 0); n != 0 {
+		if !s.hasThought(n) {
+			return s.fail(ctx, request, invalid("thoughtNumber", "recorded thought number", n, "thought %d does not exist", n))
+		}
+		d.ThoughtNumber = n
+	}
+	for _, option := range request.GetStringSlice("options", nil) {
+		if option != "" && !slices.Contains(d.Options, option) {
+			d.Options = append(d.Options, option)
+		}
+	}
+	for i, c := range criteria {
+		name, _ := c["name"].(string)
+		if name == "" {
+			return s.fail(ctx, request, inItem("criteria", i, invalid("name", "non-empty string", c["name"], "must be a non-empty string")))
+		}
+		weight := 1.0
+		if w, ok := c["weight"]; ok {
+			if weight, ok = w.(float64); !ok || weight < 0 {
+				return s.fail(ctx, request, inItem("criteria", i, invalid("weight", "non-negative number", w, "must be a non-negative number")))
+			}
+		}
+		if j := slices.IndexFunc(d.Criteria, func(c Criterion) bool { return c.Name == name }); j >= 0 {
+			d.Criteria[j].Weight = weight
+		} else {
+			d.Criteria = append(d.Criteria, Criterion{Name: name, Weight: weight})
+		}
+	}
+	for i, sc := range scores {
+		option, _ := sc["option"].(string)
+		criterion, _ := sc["criterion"].(string)
+		score, ok := sc["score"].(float64)
+		switch {
+		case !slices.Contains(d.Options, option):
+			return s.fail(ctx, request, inItem("scores", i, invalid("option", "one of the options", sc["option"], "unknown option %q", option)))
+		case !slices.ContainsFunc(d.Criteria, func(c Criterion) bool { return c.Name == criterion }):
+			return s.fail(ctx, request, inItem("scores", i, invalid("criterion", "one of the criteria", sc["criterion"], "unknown criterion %q", criterion)))
+		case !ok:
+			return s.fail(ctx, request, inItem("scores", i, invalid("score", "number", sc["score"], "must be a number")))
+		}
+		if d.Scores[option] == nil {
+			d.Scores[option] = make(map[string]float64)
+		}
+		d.Scores[option][criterion] = score
+	}
+	d.rank()
+
+	if existing := s.findDecision(d.ID); existing != nil {
+		*existing = d
+	} else {
+		s.decisionSeq++
+		s.decisions = append(s.decisions, d)
+	}
+
+	if !s.disableThoughtLogging {
+		leader := "no options yet"
+		if len(d.Ranking) > 0 {
+			leader = fmt.Sprintf("%s leads with %g", d.Ranking[0].Option, d.Ranking[0].Score)
+		}
+		s.logEvent(s.theme.Record, fmt.Sprintf("⚖️  Decision %s:", d.ID), leader)
+	}
+
+	return s.respond(ctx, request, map[string]any{
+		"decision": d,
+	})
+}
@@ -0,0 +1,94 @@
+package thinking
+
+import (
+	"fmt"
+	"slices"
+)
+
+// resolveDependencies pins each thought in dependsOn to exactly one earlier
+// thought, found as for parentThought, and records their IDs.
+func (s *SequentialThinkingServer) resolveDependencies(data *ThoughtData) error {
+	data.DependencyIds = nil
+	for _, n := range data.DependsOn {
+		if n >= data.ThoughtNumber {
+			return invalid("dependsOn", fmt.Sprintf("thought numbers below %d", data.ThoughtNumber), n,
+				"thought %d cannot depend on thought %d; a thought can only depend on earlier ones", data.ThoughtNumber, n)
+		}
+		dependency, err := s.visibleThought(data, "dependsOn", n)
+		if err != nil {
+			return err
+		}
+		data.DependencyIds = append(data.DependencyIds, dependency.Id)
+	}
+	return nil
+}
+
+// historyIndex maps the ID of every recorded thought to its history index.
+func (s *SequentialThinkingServer) historyIndex() map[string]int {
+	index := make(map[string]int, len(s.thoughtHistory))
+	for i := range s.thoughtHistory {
+		index[s.thoughtHistory[i].Id] = i
+	}
+	return index
+}
+
+// dependentsWarning flags, for a revision about to be recorded, the standing
+// thoughts that depend on the thought it revises, or returns "" if there are
+// none.
+func (s *SequentialThinkingServer) dependentsWarning(data *ThoughtData) string {
+	if data.RevisesThought == nil || data.RevisesBranchId == nil {
+		return ""
+	}
+	target := s.indexBefore(*data.RevisesBranchId, *data.RevisesThought, len(s.thoughtHistory))
+	if target < 0 {
+		return ""
+	}
+	live := s.liveThoughts()
+	var dependents []int
+	for i := range s.thoughtHistory {
+		t := &s.thoughtHistory[i]
+		if live(t) && slices.Contains(t.DependencyIds, s.thoughtHistory[target].Id) {
+			dependents = append(dependents, t.ThoughtNumber)
+		}
+	}
+	if len(dependents) == 0 {
+		return ""
+	}
+	if len(dependents) == 1 {
+		return fmt.Sprintf("thought %d depends on thought %d, which this revises; check whether it still holds",
+			dependents[0], *data.RevisesThought)
+	}
+	return fmt.Sprintf("thoughts %s depend on thought %d, which this revises; check whether they still hold",
+		joinInts(dependents), *data.RevisesThought)
+}
+
+// suspectThoughts lists the standing thoughts that depend on a thought
+// revised after them, whose conclusions may no longer hold.
+func (s *SequentialThinkingServer) suspectThoughts() []ThoughtRef {
+	index := s.historyIndex()
+	revisedAt := make(map[int]int)
+	for j := range s.thoughtHistory {
+		r := &s.thoughtHistory[j]
+		if r.RevisesThought == nil || r.RevisesBranchId == nil {
+			continue
+		}
+		if target := s.indexBefore(*r.RevisesBranchId, *r.RevisesThought, j); target >= 0 {
+			revisedAt[target] = j
+		}
+	}
+	live := s.liveThoughts()
+	var suspect []ThoughtRef
+	for i := range s.thoughtHistory {
+		t := &s.thoughtHistory[i]
+		if !live(t) {
+			continue
+		}
+		for _, id := range t.DependencyIds {
+			if d, ok := index[id]; ok && revisedAt[d] > i {
+				suspect = append(suspect, refTo(t, t.Thought))
+				break
+			}
+		}
+	}
+	return suspect
+}
@@ -0,0 +1,125 @@
+package thinking
+
+import "strings"
+
+// DiffOp is a run of words kept, inserted or deleted between two texts.
+type DiffOp struct {
+	Op   string `json:"op"` // "=", "+" or "-"
+	Text string `json:"text"`
+}
+
+// WordDiff compares two texts word by word using their longest common
+// subsequence. Whitespace is normalized to single spaces.
+func WordDiff(from, to string) []DiffOp {
+	a, b := strings.Fields(from), strings.Fields(to)
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var ops []DiffOp
+	emit := func(op, word string) {
+		if n := len(ops); n > 0 && ops[n-1].Op == op {
+			ops[n-1].Text += " " + word
+			return
+		}
+		ops = append(ops, DiffOp{Op: op, Text: word})
+	}
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			emit("=", a[i])
+			i, j = i+1, j+1
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			emit("-", a[i])
+			i++
+		default:
+			emit("+", b[j])
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		emit("-", a[i])
+	}
+	for ; j < len(b); j++ {
+		emit("+", b[j])
+	}
+	return ops
+}
+
+// revisionDiff returns the word diff from the thought t revises to t, or nil
+// when t isn't a revision or the revised thought isn't in the history.
+func (s *SequentialThinkingServer) revisionDiff(t *ThoughtData) []DiffOp {
+	if t.RevisesThought == nil || t.RevisesBranchId == nil {
+		return nil
+	}
+	for i := range s.thoughtHistory {
+		h := &s.thoughtHistory[i]
+		if h.ThoughtNumber != t.ThoughtNumber || branchOf(h) != branchOf(t) || h.Thought != t.Thought {
+			continue
+		}
+		if j := s.indexBefore(*t.RevisesBranchId, *t.RevisesThought, i); j >= 0 {
+			return WordDiff(s.thoughtHistory[j].Thought, t.Thought)
+		}
+		return nil
+	}
+	return nil
+}
+
+// diffStats counts the words a diff deletes and inserts.
+func diffStats(ops []DiffOp) (deleted, inserted int) {
+	for _, op := range ops {
+		switch op.Op {
+		case "-":
+			deleted += len(strings.Fields(op.Text))
+		case "+":
+			inserted += len(strings.Fields(op.Text))
+		}
+	}
+	return deleted, inserted
+}
+
+// markedDiff writes a diff as text, marking deletions [-like this-] and
+// insertions {+like this+}, as git diff --word-diff=plain does.
+func markedDiff(ops []DiffOp) string {
+	words := make([]string, len(ops))
+	for i, op := range ops {
+		switch op.Op {
+		case "-":
+			words[i] = "[-" + op.Text + "-]"
+		case "+":
+			words[i] = "{+" + op.Text + "+}"
+		default:
+			words[i] = op.Text
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// markdownDiff writes a diff as Markdown, deletions struck through and
+// insertions in bold.
+func markdownDiff(ops []DiffOp) string {
+	words := make([]string, len(ops))
+	for i, op := range ops {
+		switch op.Op {
+		case "-":
+			words[i] = "~~" + op.Text + "~~"
+		case "+":
+			words[i] = "**" + op.Text + "**"
+		default:
+			words[i] = op.Text
+		}
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,108 @@
+package thinking
+
+import (
+	"fmt"
+	"strings"
+)
+
+// dotEdgeStyles gives each kind of graph edge its DOT attributes.
+var dotEdgeStyles = map[string]string{
+	"sequence": "",
+	"branch":   ` [label="branch"]`,
+	"revision": ` [label="revised by", style=dashed, color="#d39e00"]`,
+	"merge":    ` [label="merge", style=bold, color="#8e44ad"]`,
+}
+
+// hypothesisColors fills hypothesis nodes by status.
+var hypothesisColors = map[string]string{
+	HypothesisOpen:      "#e8eaed",
+	HypothesisConfirmed: "#ceead6",
+	HypothesisRefuted:   "#fad2cf",
+}
+
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// thoughtIndex returns the history index of the latest thought numbered n,
+// preferring the main line, or -1.
+func (s *SequentialThinkingServer) thoughtIndex(n int) int {
+	if i := s.indexBefore("", n, len(s.thoughtHistory)); i >= 0 {
+		return i
+	}
+	for i := len(s.thoughtHistory) - 1; i >= 0; i-- {
+		if s.thoughtHistory[i].ThoughtNumber == n {
+			return i
+		}
+	}
+	return -1
+}
+
+// renderDOT draws the thought graph in Graphviz DOT. Thoughts are styled by
+// type (revision, branch, merge), branches are clusters, abandoned ones
+// dashed, and hypotheses hang off the thoughts that propose, support or
+// refute them.
+func (s *SequentialThinkingServer) renderDOT() string {
+	var b strings.Builder
+	b.WriteString("digraph thoughts {\n")
+	b.WriteString("  node [shape=box, style=\"rounded,filled\", fillcolor=\"#e8f0fe\", fontname=\"Helvetica\"];\n")
+	b.WriteString("  edge [fontname=\"Helvetica\", fontsize=10];\n")
+
+	lines := make(map[string][]int)
+	for i := range s.thoughtHistory {
+		line := branchOf(&s.thoughtHistory[i])
+		lines[line] = append(lines[line], i)
+	}
+	nodes := func(indent string, indices []int) {
+		for _, i := range indices {
+			t := &s.thoughtHistory[i]
+			fmt.Fprintf(&b, "%sT%d [label=\"%s\"", indent, i, dotEscaper.Replace(graphLabel(t)))
+			switch {
+			case t.MergedBranchId != nil:
+				b.WriteString(`, fillcolor="#f3e8fd"`)
+			case t.IsRevision != nil && *t.IsRevision:
+				b.WriteString(`, fillcolor="#fff3cd"`)
+			case branchOf(t) != "":
+				b.WriteString(`, fillcolor="#e6f4ea"`)
+			}
+			b.WriteString("];\n")
+		}
+	}
+
+	nodes("  ", lines[""])
+	for n, id := range s.allBranches() {
+		fmt.Fprintf(&b, "  subgraph cluster_%d {\n    label=\"branch %s\";\n", n+1, dotEscaper.Replace(id))
+		if _, ok := s.abandonReason(id); ok {
+			b.WriteString("    style=dashed;\n")
+		}
+		nodes("    ", lines[id])
+		b.WriteString("  }\n")
+	}
+
+	children, _ := s.thoughtGraph()
+	for _, edges := range children {
+		for _, e := range edges {
+			fmt.Fprintf(&b, "  T%d -> T%d%s;\n", e.from, e.to, dotEdgeStyles[e.kind])
+		}
+	}
+
+	for _, h := range s.hypotheses {
+		fmt.Fprintf(&b, "  %s [shape=ellipse, label=\"%s: %s (%s)\", fillcolor=\"%s\"];\n",
+			h.ID, h.ID, dotEscaper.Replace(excerpt(h.Statement)), h.Status, hypothesisColors[h.Status])
+		link := func(n int, attrs string) {
+			if i := s.thoughtIndex(n); i >= 0 {
+				fmt.Fprintf(&b, "  T%d -> %s [%s];\n", i, h.ID, attrs)
+			}
+		}
+		if h.ProposedIn > 0 {
+			link(h.ProposedIn, `label="proposes", style=dotted`)
+		}
+		for _, n := range h.SupportingThoughts {
+			link(n, `label="supports", color="#188038"`)
+		}
+		for _, n := range h.RefutingThoughts {
+			link(n, `label="refutes", color="#d93025"`)
+		}
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
@@ -0,0 +1,66 @@
+package thinking
+
+import (
+	"fmt"
+	"os"
+)
+
+// DuplicateMode is what happens to a thought that reuses the number of a
+// thought already recorded on its line without being marked a revision.
+type DuplicateMode string
+
+const (
+	// DuplicateReject rejects the thought, pointing at the next free number.
+	DuplicateReject DuplicateMode = "reject"
+	// DuplicateRevise records the thought as a revision of the one whose
+	// number it reuses, with a warning in the result.
+	DuplicateRevise DuplicateMode = "revise"
+)
+
+// WithDuplicateMode sets how thoughts reusing a recorded number are
+// handled, overriding GOTHINK_DUPLICATES.
+func WithDuplicateMode(mode DuplicateMode) Option {
+	return func(s *SequentialThinkingServer) {
+		s.duplicates = mode
+	}
+}
+
+func duplicateModeFromEnv() DuplicateMode {
+	switch name := os.Getenv("GOTHINK_DUPLICATES"); name {
+	case "", string(DuplicateReject):
+		return DuplicateReject
+	case string(DuplicateRevise):
+		return DuplicateRevise
+	default:
+		fmt.Fprintf(os.Stderr, "unknown duplicate mode %q; using %s\n", name, DuplicateReject)
+		return DuplicateReject
+	}
+}
+
+// checkDuplicate handles a thought numbered like one already recorded on
+// its line: it is rejected, or turned into a revision of that thought with a
+// warning. Revisions and merges may reuse numbers.
+func (s *SequentialThinkingServer) checkDuplicate(data *ThoughtData) (string, error) {
+	if data.IsRevision != nil && *data.IsRevision || data.MergedBranchId != nil {
+		return "", nil
+	}
+	line, n := branchOf(data), data.ThoughtNumber
+	if len(s.thoughtsInScope(line, n)) == 0 {
+		return "", nil
+	}
+
+	if s.duplicates == DuplicateRevise {
+		revision := true
+		data.IsRevision, data.RevisesThought = &revision, &n
+		return fmt.Sprintf("thought %d already exists in %s, so this one was recorded as a revision of it", n, describeScope(line)), nil
+	}
+	next := 0
+	for _, t := range s.thoughtHistory {
+		if branchOf(&t) == line {
+			next = max(next, t.ThoughtNumber)
+		}
+	}
+	return "", invalid("thoughtNumber", fmt.Sprint(next+1), n,
+		"thought %d already exists in %s; set isRevision and revisesThought to revise it, or number this thought %d",
+		n, describeScope(line), next+1)
+}
@@ -0,0 +1,23 @@
+package thinking
+
+import "testing"
+
+func TestDuplicateRevision(t *testing.T) {
+	s := newTestServer(t, WithDuplicateMode(DuplicateRevise))
+	mustCall(t, s.processThought, thought(1, 2, "a", nil))
+	fields := mustCall(t, s.processThought, thought(1, 2, "a, better", nil))
+	if fields["warnings"] == nil {
+		t.Errorf("duplicate was recorded without a warning: %v", fields)
+	}
+
+	if len(s.thoughtHistory) != 2 {
+		t.Fatalf("history length = %d, want 2", len(s.thoughtHistory))
+	}
+	live := s.liveThoughts()
+	if live(&s.thoughtHistory[0]) {
+		t.Errorf("revised thought is still live")
+	}
+	if !live(&s.thoughtHistory[1]) {
+		t.Errorf("revision shares the revised thought's number and is not live")
+	}
+}
@@ -0,0 +1,54 @@
+package thinking
+
+import (
+	"os"
+	"slices"
+	"strconv"
+)
+
+// WithEchoRecent makes every sequentialthinking result repeat the k thoughts
+// recorded before it, overriding GOTHINK_ECHO_RECENT. Calls can override it
+// with echoRecent.
+func WithEchoRecent(k int) Option {
+	return func(s *SequentialThinkingServer) {
+		s.echoRecent = k
+	}
+}
+
+func echoRecentFromEnv() int {
+	k, _ := strconv.Atoi(os.Getenv("GOTHINK_ECHO_RECENT"))
+	return k
+}
+
+// echoCount returns how many recent thoughts a call asks to have echoed.
+func (s *SequentialThinkingServer) echoCount(args map[string]any) (int, error) {
+	val, ok := args["echoRecent"]
+	if !ok {
+		return s.echoRecent, nil
+	}
+	k, ok := number(val)
+	if !ok || k < 0 {
+		return 0, invalid("echoRecent", "non-negative integer", val, "must be a non-negative number")
+	}
+	return int(k), nil
+}
+
+// recentThoughts returns k of the thoughts recorded before the latest one,
+// oldest first, with their full text: the most important ones, and among
+// equally important ones the most recent. Without importances, these are
+// the last k thoughts.
+func (s *SequentialThinkingServer) recentThoughts(k int) []ThoughtRef {
+	indexes := make([]int, max(len(s.thoughtHistory)-1, 0))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	s.rankByImportance(indexes)
+	indexes = indexes[:min(k, len(indexes))]
+	slices.Sort(indexes)
+	recent := make([]ThoughtRef, 0, len(indexes))
+	for _, i := range indexes {
+		t := &s.thoughtHistory[i]
+		recent = append(recent, ThoughtRef{ThoughtNumber: t.ThoughtNumber, BranchId: branchOf(t), Importance: t.Importance, Text: t.Thought})
+	}
+	return recent
+}
@@ -0,0 +1,88 @@
+package thinking
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// clientCapabilities returns what the client calling the tool declared
+// during initialization, or nothing if it is unknown.
+func clientCapabilities(ctx context.Context) mcp.ClientCapabilities {
+	if session, ok := server.ClientSessionFromContext(ctx).(server.SessionWithClientInfo); ok {
+		return session.GetClientCapabilities()
+	}
+	return mcp.ClientCapabilities{}
+}
+
+// missingReferences lists the thought references args implies but leaves
+// out: the thought a revision revises and the thought a branch forks from.
+func missingReferences(args map[string]any) map[string]any {
+	missing := make(map[string]any)
+	if revision, _ := args["isRevision"].(bool); revision && args["revisesThoughtId"] == nil {
+		if _, ok := number(args["revisesThought"]); !ok {
+			missing["revisesThought"] = map[string]any{
+				"type":        "integer",
+				"minimum":     1,
+				"title":       "Revised thought",
+				"description": "Number of the thought this one revises",
+			}
+		}
+	}
+	if _, ok := args["branchId"].(string); ok {
+		if _, ok := number(args["branchFromThought"]); !ok {
+			missing["branchFromThought"] = map[string]any{
+				"type":        "integer",
+				"minimum":     1,
+				"title":       "Branching point",
+				"description": "Number of the thought the branch forks from",
+			}
+		}
+	}
+	return missing
+}
+
+// elicitReferences asks the user for references a thought is missing, when
+// the client supports elicitation, and returns args with the answers filled
+// in. If the user declines or the request fails, args is returned unchanged.
+// It must be called without the lock held, since the user may take a while.
+func (s *SequentialThinkingServer) elicitReferences(ctx context.Context, args map[string]any) map[string]any {
+	missing := missingReferences(args)
+	if len(missing) == 0 || s.srv == nil || clientCapabilities(ctx).Elicitation == nil {
+		return args
+	}
+
+	thoughtNumber, _ := number(args["thoughtNumber"])
+	result, err := s.srv.RequestElicitation(ctx, mcp.ElicitationRequest{
+		Params: mcp.ElicitationParams{
+			Message: fmt.Sprintf("Thought %d doesn't say which thought it refers to.", int(thoughtNumber)),
+			RequestedSchema: map[string]any{
+				"type":       "object",
+				"properties": missing,
+				"required":   slices.Sorted(maps.Keys(missing)),
+			},
+		},
+	})
+	if err != nil {
+		if !s.disableThoughtLogging {
+			s.logEvent(s.theme.Alert, fmt.Sprintf("❓ Elicitation failed: %v", err), "")
+		}
+		return args
+	}
+	content, ok := result.Content.(map[string]any)
+	if result.Action != mcp.ElicitationResponseActionAccept || !ok {
+		return args
+	}
+
+	args = maps.Clone(args)
+	for name := range missing {
+		if n, ok := number(content[name]); ok {
+			args[name] = n
+		}
+	}
+	return args
+}
@@ -0,0 +1,97 @@
+package thinking
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ValidationError is why the server rejected a thought: an invalid argument,
+// or a session that can't take more thoughts. Tool error results carry its
+// fields next to the message, so that clients can correct the call without
+// parsing the text.
+type ValidationError struct {
+	// Code names the problem, such as INVALID_THOUGHT_NUMBER.
+	Code string
+	// Field is the argument at fault, and Path its JSON pointer in the
+	// tool arguments.
+	Field, Path string
+	// Expected describes what the argument should have been.
+	Expected string
+	// Received is the value sent, or nil if the argument was missing.
+	Received any
+	// Message explains the problem and, where it can, how to fix it.
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// invalid reports a bad value of the argument field, coded after it:
+// invalid("thoughtNumber", ...) has the code INVALID_THOUGHT_NUMBER and the
+// message "invalid thoughtNumber: " followed by the formatted explanation.
+func invalid(field, expected string, received any, format string, args ...any) *ValidationError {
+	code := []rune("INVALID_")
+	for _, r := range field {
+		if unicode.IsUpper(r) {
+			code = append(code, '_')
+		}
+		code = append(code, unicode.ToUpper(r))
+	}
+	return &ValidationError{
+		Code:     string(code),
+		Field:    field,
+		Path:     "/" + pointerEscaper.Replace(field),
+		Expected: expected,
+		Received: received,
+		Message:  fmt.Sprintf("invalid %s: %s", field, fmt.Sprintf(format, args...)),
+	}
+}
+
+// inItem places err, about item i of the array argument key, inside the
+// item: the message names the item, and a ValidationError's path points
+// into it.
+func inItem(key string, i int, err error) error {
+	var target *ValidationError
+	if errors.As(err, &target) {
+		target.Path = fmt.Sprintf("/%s/%d%s", key, i, target.Path)
+	}
+	return fmt.Errorf("%s[%d]: %w", key, i, err)
+}
+
+// notAnObject reports item i of the array argument key that isn't an object.
+func notAnObject(key string, i int, item any) error {
+	err := invalid(key, "array of objects", item, "item %d must be an object", i)
+	err.Path = fmt.Sprintf("%s/%d", err.Path, i)
+	return err
+}
+
+// pointerEscaper escapes a key for a JSON pointer (RFC 6901).
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// errorFields returns the fields of the tool error result for err: its
+// message, and the code, field, path, expected value and received value of
+// a ValidationError it wraps.
+func errorFields(err error) map[string]any {
+	fields := map[string]any{"error": err.Error()}
+	var target *ValidationError
+	if !errors.As(err, &target) {
+		return fields
+	}
+	fields["code"] = target.Code
+	if target.Field != "" {
+		fields["field"] = target.Field
+	}
+	if target.Path != "" {
+		fields["path"] = target.Path
+	}
+	if target.Expected != "" {
+		fields["expected"] = target.Expected
+	}
+	if target.Received != nil {
+		fields["received"] = target.Received
+	}
+	return fields
+}
@@ -0,0 +1,125 @@
+package thinking
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestToolErrors(t *testing.T) {
+	blocked := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(blocked, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	branched := func(t *testing.T, s *SequentialThinkingServer) {
+		mustCall(t, s.processThought, thought(1, 2, "a", nil))
+		mustCall(t, s.processThought, thought(2, 2, "b", nil))
+		mustCall(t, s.processThought, thought(2, 2, "c", map[string]any{"branchId": "alt", "branchFromThought": 1.0}))
+		mustCall(t, s.processThought, thought(2, 2, "d", map[string]any{"branchId": "dead", "branchFromThought": 1.0}))
+		mustCall(t, s.abandonBranch, map[string]any{"branchId": "dead", "reason": "no"})
+	}
+	tests := []struct {
+		name     string
+		opts     []Option
+		setup    func(t *testing.T, s *SequentialThinkingServer)
+		tool     func(s *SequentialThinkingServer) handler
+		args     map[string]any
+		wantCode string
+		wantPath string
+	}{
+		{
+			name:     "restore without checkpoints",
+			tool:     func(s *SequentialThinkingServer) handler { return s.restoreCheckpoint },
+			args:     map[string]any{"label": "x"},
+			wantCode: "INVALID_LABEL",
+			wantPath: "/label",
+		},
+		{
+			name: "restore an unknown checkpoint",
+			setup: func(t *testing.T, s *SequentialThinkingServer) {
+				mustCall(t, s.checkpoint, map[string]any{"label": "a"})
+			},
+			tool:     func(s *SequentialThinkingServer) handler { return s.restoreCheckpoint },
+			args:     map[string]any{"label": "x"},
+			wantCode: "INVALID_LABEL",
+			wantPath: "/label",
+		},
+		{
+			name:     "merge an abandoned branch",
+			setup:    branched,
+			tool:     func(s *SequentialThinkingServer) handler { return s.mergeBranches },
+			args:     map[string]any{"branchId": "dead"},
+			wantCode: "INVALID_BRANCH_ID",
+			wantPath: "/branchId",
+		},
+		{
+			name:     "merge into an abandoned branch",
+			setup:    branched,
+			tool:     func(s *SequentialThinkingServer) handler { return s.mergeBranches },
+			args:     map[string]any{"branchId": "alt", "into": "dead"},
+			wantCode: "INVALID_INTO",
+			wantPath: "/into",
+		},
+		{
+			name:     "merge past the thought limit",
+			opts:     []Option{WithMaxThoughts(2)},
+			setup:    branched,
+			tool:     func(s *SequentialThinkingServer) handler { return s.mergeBranches },
+			args:     map[string]any{"branchId": "alt"},
+			wantCode: "INVALID_BRANCH_ID",
+			wantPath: "/branchId",
+		},
+		{
+			name:     "finalize without thoughts",
+			tool:     func(s *SequentialThinkingServer) handler { return s.finalizeAnswer },
+			args:     map[string]any{"answer": "42"},
+			wantCode: "CHAIN_NOT_CONCLUDED",
+		},
+		{
+			name: "finalize an open chain",
+			setup: func(t *testing.T, s *SequentialThinkingServer) {
+				mustCall(t, s.processThought, thought(1, 2, "a", nil))
+			},
+			tool:     func(s *SequentialThinkingServer) handler { return s.finalizeAnswer },
+			args:     map[string]any{"answer": "42"},
+			wantCode: "CHAIN_NOT_CONCLUDED",
+		},
+		{
+			name:     "no final answer",
+			tool:     func(s *SequentialThinkingServer) handler { return s.getFinalAnswer },
+			wantCode: "NO_FINAL_ANSWER",
+		},
+		{
+			name:     "export disabled",
+			opts:     []Option{WithExportDir("")},
+			tool:     func(s *SequentialThinkingServer) handler { return s.exportSession },
+			wantCode: "EXPORT_DISABLED",
+		},
+		{
+			name:     "export to an unwritable directory",
+			opts:     []Option{WithExportDir(blocked)},
+			tool:     func(s *SequentialThinkingServer) handler { return s.exportSession },
+			args:     map[string]any{"name": "x"},
+			wantCode: "INVALID_NAME",
+			wantPath: "/name",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t, tt.opts...)
+			if tt.setup != nil {
+				tt.setup(t, s)
+			}
+			fields, isError := callTool(t, tt.tool(s), tt.args)
+			if !isError {
+				t.Fatalf("call succeeded: %v", fields)
+			}
+			if fields["code"] != tt.wantCode {
+				t.Errorf("code = %v, want %s (%v)", fields["code"], tt.wantCode, fields["error"])
+			}
+			if path, _ := fields["path"].(string); path != tt.wantPath {
+				t.Errorf("path = %q, want %q", path, tt.wantPath)
+			}
+		})
+	}
+}
@@ -0,0 +1,298 @@
+package thinking
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/fatih/color"
+	"github.com/mattn/go-runewidth"
+	"golang.org/x/term"
+)
+
+// defaultLogWidth caps the width of logged thought boxes unless
+// GOTHINK_LOG_WIDTH or WithLogWidth sets another cap.
+const defaultLogWidth = 120
+
+// WithLogWidth caps the width of the thought boxes logged to stderr,
+// overriding GOTHINK_LOG_WIDTH. Boxes are narrower when the terminal is.
+func WithLogWidth(width int) Option {
+	return func(s *SequentialThinkingServer) {
+		s.maxLogWidth = width
+	}
+}
+
+func logWidthFromEnv() int {
+	if width, err := strconv.Atoi(os.Getenv("GOTHINK_LOG_WIDTH")); err == nil && width > 0 {
+		return width
+	}
+	return defaultLogWidth
+}
+
+// logWidth returns the width of logged boxes: the width of the terminal
+// stderr is attached to, at most the configured cap.
+func (s *SequentialThinkingServer) logWidth() int {
+	if width, _, err := term.GetSize(int(os.Stderr.Fd())); err == nil && width > 0 {
+		return min(width, s.maxLogWidth)
+	}
+	return s.maxLogWidth
+}
+
+// kindEmoji marks the kinds of thoughts in the log, outside ASCII mode.
+var kindEmoji = map[string]string{
+	"Thought":  "💭",
+	"Revision": "🔄",
+	"Branch":   "🌿",
+	"Merge":    "🔀",
+}
+
+// boxGlyphs are the characters a box is drawn with.
+type boxGlyphs struct {
+	topLeft, topRight, teeLeft, teeRight, bottomLeft, bottomRight, horizontal, vertical string
+}
+
+var (
+	unicodeBox = boxGlyphs{"┌", "┐", "├", "┤", "└", "┘", "─", "│"}
+	asciiBox   = boxGlyphs{"+", "+", "+", "+", "+", "+", "-", "|"}
+)
+
+// FormatThought draws a thought as the boxed block the server logs to
+// stderr, at most width columns wide and colored by theme. The thought is
+// wrapped to fit, with its Markdown rendered in the theme's style.
+func FormatThought(data *ThoughtData, width int, theme Theme) string {
+	return formatBox(data, nil, width, theme)
+}
+
+// formatBox draws a thought box. A revision's box ends with its word diff
+// against the revised thought, if given.
+func formatBox(data *ThoughtData, diff []DiffOp, width int, theme Theme) string {
+	var label, context string
+	var paint *color.Color
+
+	if data.MergedBranchId != nil {
+		label, paint = "Merge", theme.Merge
+		context = fmt.Sprintf(" (branch %s into %s)", *data.MergedBranchId, describeScope(branchOf(data)))
+	} else if data.IsRevision != nil && *data.IsRevision {
+		label, paint = "Revision", theme.Revision
+		if data.RevisesThought != nil {
+			context = fmt.Sprintf(" (revising thought %d)", *data.RevisesThought)
+			if data.RevisesBranchId != nil && *data.RevisesBranchId != "" {
+				context = fmt.Sprintf(" (revising thought %d in branch %s)", *data.RevisesThought, *data.RevisesBranchId)
+			}
+		}
+	} else if data.BranchFromThought != nil && data.BranchId != nil {
+		label, paint = "Branch", theme.Branch
+		context = fmt.Sprintf(" (from thought %d, ID: %s)", *data.BranchFromThought, *data.BranchId)
+	} else {
+		label, paint = "Thought", theme.Thought
+	}
+	if !theme.ASCII {
+		label = kindEmoji[label] + " " + label
+	}
+	if data.ThoughtType != nil {
+		context += " [" + *data.ThoughtType + "]"
+	}
+	if data.Author != "" {
+		context += " by " + data.Author
+	}
+
+	// The box adds a border and a space on either side.
+	inner := max(width-4, 8)
+	header := wrapText(expandTabs(Printable(fmt.Sprintf("%s %d/%d%s", label, data.ThoughtNumber, data.TotalThoughts, context))), inner)
+	body := MarkdownLines(expandTabs(Printable(data.Thought)), inner, theme.Markdown)
+	var changes []string
+	if diff != nil {
+		changes = wrapText(expandTabs(Printable(markedDiff(diff))), inner)
+	}
+	size := 0
+	for _, lines := range [][]string{header, body, changes} {
+		for _, line := range lines {
+			size = max(size, runewidth.StringWidth(stripANSI(line)))
+		}
+	}
+	if rest, ok := strings.CutPrefix(header[0], label); ok {
+		header[0] = paintf(paint, "%s", label) + rest
+	}
+
+	g := unicodeBox
+	if theme.ASCII {
+		g = asciiBox
+	}
+	border := strings.Repeat(g.horizontal, size+2)
+	var b strings.Builder
+	fmt.Fprintf(&b, "\n%s%s%s\n", g.topLeft, border, g.topRight)
+	writeBoxLines(&b, header, size, g.vertical)
+	fmt.Fprintf(&b, "%s%s%s\n", g.teeLeft, border, g.teeRight)
+	writeBoxLines(&b, body, size, g.vertical)
+	if changes != nil {
+		fmt.Fprintf(&b, "%s%s%s\n", g.teeLeft, border, g.teeRight)
+		writeBoxLines(&b, colorDiffLines(changes, theme), size, g.vertical)
+	}
+	fmt.Fprintf(&b, "%s%s%s", g.bottomLeft, border, g.bottomRight)
+	return b.String()
+}
+
+// colorDiffLines colors the deletions and insertions marked in the lines of
+// a wrapped diff. Marks may span lines.
+func colorDiffLines(lines []string, theme Theme) []string {
+	colored := make([]string, len(lines))
+	var paint *color.Color
+	for i, line := range lines {
+		var b strings.Builder
+		var segment strings.Builder
+		flush := func() {
+			if segment.Len() > 0 {
+				b.WriteString(paintf(paint, "%s", segment.String()))
+				segment.Reset()
+			}
+		}
+		for j := 0; j < len(line); j++ {
+			switch {
+			case paint == nil && strings.HasPrefix(line[j:], "[-"):
+				flush()
+				paint = theme.Deleted
+			case paint == nil && strings.HasPrefix(line[j:], "{+"):
+				flush()
+				paint = theme.Inserted
+			}
+			segment.WriteByte(line[j])
+			if j > 0 && (paint == theme.Deleted && strings.HasSuffix(line[:j+1], "-]") ||
+				paint == theme.Inserted && strings.HasSuffix(line[:j+1], "+}")) {
+				flush()
+				paint = nil
+			}
+		}
+		flush()
+		colored[i] = b.String()
+	}
+	return colored
+}
+
+// writeBoxLines writes lines padded to size columns between vertical box
+// borders. Colored text is padded by its visible width.
+func writeBoxLines(b *strings.Builder, lines []string, size int, vertical string) {
+	for _, line := range lines {
+		visible := runewidth.StringWidth(stripANSI(line))
+		fmt.Fprintf(b, "%s %s%s %s\n", vertical, line, strings.Repeat(" ", max(size-visible, 0)), vertical)
+	}
+}
+
+// tabWidth is the distance between the tab stops of boxed text, as in
+// Markdown.
+const tabWidth = 4
+
+// expandTabs replaces the tabs in text with spaces up to the next tab stop.
+// Boxes measure their lines to pad them, and a terminal would move a tab to
+// its own stops, which don't line up with the box.
+func expandTabs(text string) string {
+	if !strings.Contains(text, "\t") {
+		return text
+	}
+	var b strings.Builder
+	column := 0
+	for _, r := range text {
+		switch r {
+		case '\t':
+			n := tabWidth - column%tabWidth
+			b.WriteString(strings.Repeat(" ", n))
+			column += n
+		case '\n':
+			b.WriteRune(r)
+			column = 0
+		default:
+			b.WriteRune(r)
+			column += runewidth.RuneWidth(r)
+		}
+	}
+	return b.String()
+}
+
+// stripANSI removes terminal escape sequences from s.
+func stripANSI(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\x1b' && i+1 < len(s) && s[i+1] == '[' {
+			i += 2
+			for i < len(s) && (s[i] < 0x40 || s[i] > 0x7e) {
+				i++
+			}
+			continue
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// Printable makes text safe to print on a terminal: control characters
+// other than newlines and tabs, which could move the cursor, recolor the
+// screen or forge log lines, and the bidirectional controls that reorder
+// text are shown as escapes such as \x1b.
+func Printable(text string) string {
+	if !strings.ContainsFunc(text, unprintable) {
+		return text
+	}
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case !unprintable(r):
+			b.WriteRune(r)
+		case r < 0x100:
+			fmt.Fprintf(&b, "\\x%02x", r)
+		default:
+			fmt.Fprintf(&b, "\\u%04x", r)
+		}
+	}
+	return b.String()
+}
+
+func unprintable(r rune) bool {
+	switch {
+	case r == '\n' || r == '\t':
+		return false
+	case r < 0x20 || r >= 0x7f && r < 0xa0:
+		return true
+	case r == 0x61c || r == 0x200e || r == 0x200f:
+		return true
+	case r >= 0x202a && r <= 0x202e || r >= 0x2066 && r <= 0x2069:
+		return true
+	}
+	return false
+}
+
+// wrapText breaks text into lines of at most width columns at spaces,
+// keeping its line breaks. Wide characters such as CJK and emoji take two
+// columns. Words longer than a line are split.
+func wrapText(text string, width int) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			for runewidth.StringWidth(word) > width {
+				if line != "" {
+					lines = append(lines, line)
+					line = ""
+				}
+				head := runewidth.Truncate(word, width, "")
+				if head == "" {
+					_, size := utf8.DecodeRuneInString(word)
+					head = word[:size]
+				}
+				lines = append(lines, head)
+				word = word[len(head):]
+			}
+			switch {
+			case line == "":
+				line = word
+			case runewidth.StringWidth(line)+1+runewidth.StringWidth(word) <= width:
+				line += " " + word
+			default:
+				lines = append(lines, line)
+				line = word
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -0,0 +1,42 @@
+package thinking
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mattn/go-runewidth"
+)
+
+func TestExpandTabs(t *testing.T) {
+	tests := []struct {
+		text, want string
+	}{
+		{"no tabs", "no tabs"},
+		{"\tindented", "    indented"},
+		{"a\tb", "a   b"},
+		{"abcd\te", "abcd    e"},
+		{"世\tx", "世  x"},
+		{"a\tb\n\tc", "a   b\n    c"},
+	}
+	for _, tt := range tests {
+		if got := expandTabs(tt.text); got != tt.want {
+			t.Errorf("expandTabs(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestFormatThoughtTabs(t *testing.T) {
+	for _, style := range []string{"", "notty"} {
+		data := &ThoughtData{Thought: "steps:\n\n\tfirst\tone\n\tsecond\ttwo", ThoughtNumber: 1, TotalThoughts: 1}
+		box := stripANSI(FormatThought(data, 40, Theme{ASCII: true, Markdown: style}))
+		if strings.Contains(box, "\t") {
+			t.Errorf("style %q: box keeps tabs:\n%s", style, box)
+		}
+		lines := strings.Split(strings.TrimPrefix(box, "\n"), "\n")
+		for _, line := range lines {
+			if w, want := runewidth.StringWidth(line), runewidth.StringWidth(lines[0]); w != want {
+				t.Errorf("style %q: line %q is %d columns wide, want %d", style, line, w, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package thinking
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// graphEdge links a parent thought to a child, both as history indices.
+type graphEdge struct {
+	from, to int
+	kind     string
+}
+
+// GraphNode identifies a thought in graph query results.
+type GraphNode struct {
+	ThoughtNumber int    `json:"thoughtNumber"`
+	BranchId      string `json:"branchId,omitempty"`
+	Text          string `json:"text"`
+	Via           string `json:"via,omitempty"`
+}
+
+// indexBefore returns the index of the latest thought numbered n on the given
+// line that precedes history index limit, or -1.
+func (s *SequentialThinkingServer) indexBefore(branchId string, n, limit int) int {
+	for i := min(limit, len(s.thoughtHistory)) - 1; i >= 0; i-- {
+		if t := &s.thoughtHistory[i]; t.ThoughtNumber == n && branchOf(t) == branchId {
+			return i
+		}
+	}
+	return -1
+}
+
+// thoughtGraph derives the reasoning structure from the history: each thought
+// follows its parentThought if it names one, or else the previous one on its
+// line, a branch starts at its branching point, revisions hang off the revised thought, and merges off the merged
+// branch's tip, and dependencies off the thoughts depended on. It returns the edges indexed by parent and by child.
+func (s *SequentialThinkingServer) thoughtGraph() (children, parents [][]graphEdge) {
+	children = make([][]graphEdge, len(s.thoughtHistory))
+	parents = make([][]graphEdge, len(s.thoughtHistory))
+	add := func(from, to int, kind string) {
+		if from < 0 {
+			return
+		}
+		e := graphEdge{from: from, to: to, kind: kind}
+		children[from] = append(children[from], e)
+		parents[to] = append(parents[to], e)
+	}
+
+	index := s.historyIndex()
+	last := make(map[string]int)
+	for i := range s.thoughtHistory {
+		t := &s.thoughtHistory[i]
+		line := branchOf(t)
+		if parent := s.parentIndex(t); parent >= 0 {
+			kind := "sequence"
+			if branchOf(&s.thoughtHistory[parent]) != line {
+				kind = "branch"
+			}
+			add(parent, i, kind)
+		} else if prev, ok := last[line]; ok {
+			add(prev, i, "sequence")
+		} else if line != "" {
+			add(s.indexBefore("", *t.BranchFromThought, i), i, "branch")
+		}
+		if t.RevisesThought != nil && t.RevisesBranchId != nil {
+			add(s.indexBefore(*t.RevisesBranchId, *t.RevisesThought, i), i, "revision")
+		}
+		if t.MergedBranchId != nil {
+			if tip, ok := last[*t.MergedBranchId]; ok {
+				add(tip, i, "merge")
+			}
+		}
+		for _, id := range t.DependencyIds {
+			if d, ok := index[id]; ok {
+				add(d, i, "dependency")
+			}
+		}
+		last[line] = i
+	}
+	return children, parents
+}
+
+func (s *SequentialThinkingServer) graphNode(i int, via string) GraphNode {
+	t := &s.thoughtHistory[i]
+	return GraphNode{ThoughtNumber: t.ThoughtNumber, BranchId: branchOf(t), Text: excerpt(t.Thought), Via: via}
+}
+
+// walk collects every node reachable from start over the given edges,
+// in breadth-first order.
+func (s *SequentialThinkingServer) walk(start int, edges [][]graphEdge, next func(graphEdge) int) []GraphNode {
+	nodes := make([]GraphNode, 0)
+	seen := map[int]bool{start: true}
+	queue := []int{start}
+	for len(queue) > 0 {
+		i := queue[0]
+		queue = queue[1:]
+		for _, e := range edges[i] {
+			if j := next(e); !seen[j] {
+				seen[j] = true
+				nodes = append(nodes, s.graphNode(j, e.kind))
+				queue = append(queue, j)
+			}
+		}
+	}
+	return nodes
+}
+
+// path finds the shortest chain of edges between two thoughts, following
+// edges in either direction. It returns nil if they aren't connected.
+func (s *SequentialThinkingServer) path(from, to int, children, parents [][]graphEdge) []GraphNode {
+	type step struct {
+		prev int
+		kind string
+	}
+	steps := map[int]step{from: {prev: -1}}
+	queue := []int{from}
+	for len(queue) > 0 {
+		i := queue[0]
+		queue = queue[1:]
+		if i == to {
+			break
+		}
+		for _, e := range slices.Concat(children[i], parents[i]) {
+			j := e.to
+			if j == i {
+				j = e.from
+			}
+			if _, ok := steps[j]; !ok {
+				steps[j] = step{prev: i, kind: e.kind}
+				queue = append(queue, j)
+			}
+		}
+	}
+	if _, ok := steps[to]; !ok {
+		return nil
+	}
+
+	var nodes []GraphNode
+	for i := to; i >= 0; i = steps[i].prev {
+		nodes = append(nodes, s.graphNode(i, steps[i].kind))
+	}
+	slices.Reverse(nodes)
+	return nodes
+}
+
+func (s *SequentialThinkingServer) queryThoughtGraph(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	n, err := request.RequireInt("thoughtNumber")
+	if err != nil {
+		return s.fail(ctx, request, invalid("thoughtNumber", "integer", request.GetArguments()["thoughtNumber"], "must be a number"))
+	}
+	query := request.GetString("query", "ancestors")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	node := func(numberKey, branchKey string, n int) (int, error) {
+		line, _, err := s.findThoughtAt(numberKey, branchKey, request.GetString(branchKey, ""), n)
+		if err != nil {
+			return -1, err
+		}
+		return s.indexBefore(line, n, len(s.thoughtHistory)), nil
+	}
+	start, err := node("thoughtNumber", "branchId", n)
+	if err != nil {
+		return s.fail(ctx, request, err)
+	}
+
+	children, parents := s.thoughtGraph()
+	result := map[string]any{
+		"thought": s.graphNode(start, ""),
+		"query":   query,
+	}
+	switch query {
+	case "ancestors":
+		result["nodes"] = s.walk(start, parents, func(e graphEdge) int { return e.from })
+	case "descendants":
+		result["nodes"] = s.walk(start, children, func(e graphEdge) int { return e.to })
+	case "path":
+		to, err := request.RequireInt("toThoughtNumber")
+		if err != nil {
+			return s.fail(ctx, request, invalid("toThoughtNumber", "integer", request.GetArguments()["toThoughtNumber"], "required for path queries"))
+		}
+		end, err := node("toThoughtNumber", "toBranchId", to)
+		if err != nil {
+			return s.fail(ctx, request, err)
+		}
+		nodes := s.path(start, end, children, parents)
+		if nodes == nil {
+			return s.fail(ctx, request, invalid("toThoughtNumber", fmt.Sprintf("thought connected to thought %d", n), to, "thoughts %d and %d are not connected", n, to))
+		}
+		result["nodes"] = nodes
+	default:
+		return s.fail(ctx, request, invalid("query", "ancestors, descendants or path", query, "must be one of ancestors, descendants, path"))
+	}
+
+	return s.respond(ctx, request, result)
+}
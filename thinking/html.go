@@ -0,0 +1,182 @@
+package thinking
+
+import (
+	"fmt"
+	"html/template"
+	"strings"
+	"time"
+)
+
+type htmlThought struct {
+	Number, Total int
+	Kind          string
+	Context       string
+	Text          string
+	At            *time.Time
+	Diff          []DiffOp
+	RevisedBy     string
+	Citations     []htmlCitation
+}
+
+// htmlCitation is a citation in the report. Only web URLs are linked, as
+// browsers won't follow other schemes from a page.
+type htmlCitation struct {
+	Ref, Href string
+}
+
+type htmlLine struct {
+	Title    string
+	Note     string
+	Thoughts []htmlThought
+}
+
+type htmlReport struct {
+	Generated   time.Time
+	Thoughts    int
+	Lines       []htmlLine
+	Final       *ThoughtData
+	FinalAt     *time.Time
+	Hypotheses  []Hypothesis
+	Assumptions []Assumption
+	Questions   []Question
+}
+
+var htmlReportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Thinking session</title>
+<style>
+body { font-family: system-ui, sans-serif; max-width: 56rem; margin: 2rem auto; padding: 0 1rem; color: #202124; }
+.tabs > input { display: none; }
+.tabs > label { display: inline-block; padding: .4rem .9rem; border: 1px solid #dadce0; border-bottom: none; border-radius: .4rem .4rem 0 0; cursor: pointer; background: #f1f3f4; }
+.tabs > input:checked + label { background: #fff; font-weight: bold; }
+.panel { display: none; border-top: 1px solid #dadce0; padding-top: 1rem; }
+{{range $i, $l := .Lines}}#tab{{$i}}:checked ~ #panel{{$i}} { display: block; }
+{{end}}details { border: 1px solid #dadce0; border-radius: .4rem; margin: .5rem 0; padding: .5rem .8rem; }
+details.revision { border-color: #d39e00; background: #fffbea; }
+details.merge { border-color: #8e44ad; background: #faf5fd; }
+summary { cursor: pointer; font-weight: 600; }
+.context { color: #5f6368; font-weight: normal; }
+.text { white-space: pre-wrap; }
+.diff { font-size: .9em; color: #5f6368; }
+del { background: #fad2cf; } ins { background: #ceead6; text-decoration: none; }
+.note { color: #5f6368; }
+footer { margin-top: 2rem; color: #5f6368; font-size: .85em; }
+</style>
+</head>
+<body>
+<h1>Thinking session</h1>
+<p>{{.Thoughts}} thoughts, generated {{.Generated.Format "2006-01-02 15:04:05 MST"}}.</p>
+<div class="tabs">
+{{range $i, $l := .Lines}}<input type="radio" name="line" id="tab{{$i}}"{{if eq $i 0}} checked{{end}}><label for="tab{{$i}}">{{$l.Title}}</label>
+{{end}}{{range $i, $l := .Lines}}<section class="panel" id="panel{{$i}}">
+{{if $l.Note}}<p class="note">{{$l.Note}}</p>
+{{end}}{{range $l.Thoughts}}<details class="{{.Kind}}" open>
+<summary>Thought {{.Number}}/{{.Total}} <span class="context">{{.Context}}</span>{{with .At}} <time datetime="{{.Format "2006-01-02T15:04:05Z07:00"}}">{{.Format "15:04:05"}}</time>{{end}}</summary>
+<div class="text">{{.Text}}</div>
+{{if .Diff}}<p class="diff">Changes: {{range .Diff}}{{if eq .Op "+"}}<ins>{{.Text}}</ins> {{else if eq .Op "-"}}<del>{{.Text}}</del> {{else}}{{.Text}} {{end}}{{end}}</p>
+{{end}}{{with .Citations}}<p class="note">Citations: {{range $i, $c := .}}{{if $i}}, {{end}}{{if $c.Href}}<a href="{{$c.Href}}">{{$c.Ref}}</a>{{else}}<code>{{$c.Ref}}</code>{{end}}{{end}}</p>
+{{end}}{{if .RevisedBy}}<p class="note">Revised by thought {{.RevisedBy}}.</p>
+{{end}}</details>
+{{end}}</section>
+{{end}}</div>
+{{with .Final}}<h2>Final answer</h2>
+<p class="note">Concluded at thought {{.ThoughtNumber}}{{with $.FinalAt}}, {{.Format "2006-01-02 15:04:05 MST"}}{{end}}.</p>
+<div class="text">{{.Thought}}</div>
+{{end}}{{with .Hypotheses}}<h2>Hypotheses</h2>
+<ul>{{range .}}<li><b>{{.ID}}</b> ({{.Status}}): {{.Statement}}</li>{{end}}</ul>
+{{end}}{{with .Assumptions}}<h2>Assumptions</h2>
+<ul>{{range .}}<li><b>{{.ID}}</b> ({{.Status}}): {{.Statement}}</li>{{end}}</ul>
+{{end}}{{with .Questions}}<h2>Questions</h2>
+<ul>{{range .}}<li><b>{{.ID}}</b>: {{.Question}}{{if .Answer}} — {{.Answer}}{{else}} (open){{end}}</li>{{end}}</ul>
+{{end}}<footer>Generated by gothink.</footer>
+</body>
+</html>
+`))
+
+// htmlThoughts prepares the thoughts of a line for the report. Revisions
+// carry a word diff against the thought they revise.
+func (s *SequentialThinkingServer) htmlThoughts(line string) []htmlThought {
+	thoughts := make([]htmlThought, 0)
+	for i := range s.thoughtHistory {
+		t := &s.thoughtHistory[i]
+		if branchOf(t) != line {
+			continue
+		}
+		h := htmlThought{Number: t.ThoughtNumber, Total: t.TotalThoughts, Kind: "thought", Text: t.Thought, At: t.ReceivedAt}
+		switch {
+		case t.MergedBranchId != nil:
+			h.Kind, h.Context = "merge", fmt.Sprintf("merges branch %s", *t.MergedBranchId)
+		case t.RevisesThought != nil && t.RevisesBranchId != nil:
+			h.Kind, h.Context = "revision", fmt.Sprintf("revises thought %d", *t.RevisesThought)
+			if *t.RevisesBranchId != line {
+				h.Context += " in " + describeScope(*t.RevisesBranchId)
+			}
+			if j := s.indexBefore(*t.RevisesBranchId, *t.RevisesThought, i); j >= 0 {
+				h.Diff = WordDiff(s.thoughtHistory[j].Thought, t.Thought)
+			}
+		}
+		for _, ref := range t.Citations {
+			c := htmlCitation{Ref: ref}
+			if citationKind(ref) == CitationURL {
+				c.Href = ref
+			}
+			h.Citations = append(h.Citations, c)
+		}
+		if revisions := s.revisionsOf(line, t.ThoughtNumber); len(revisions) > 0 {
+			numbers := make([]int, len(revisions))
+			for k, r := range revisions {
+				numbers[k] = r.ThoughtNumber
+			}
+			h.RevisedBy = joinInts(numbers)
+		}
+		thoughts = append(thoughts, h)
+	}
+	return thoughts
+}
+
+// htmlLines prepares the main line and then every branch, with a note on
+// where each branch came from and what became of it.
+func (s *SequentialThinkingServer) htmlLines() []htmlLine {
+	lines := []htmlLine{{Title: "Main line", Thoughts: s.htmlThoughts("")}}
+	for _, id := range s.allBranches() {
+		note := fmt.Sprintf("Branched from thought %d", s.branchOrigin(id))
+		if into, ok := s.mergedInto(id); ok {
+			note += ", merged into " + describeScope(into)
+		}
+		if reason, ok := s.abandonReason(id); ok {
+			note += ", abandoned: " + reason
+		}
+		note += "."
+		if description := s.branches[id].Description; description != "" {
+			note += " " + description
+		}
+		lines = append(lines, htmlLine{Title: "Branch " + id, Note: note, Thoughts: s.htmlThoughts(id)})
+	}
+	return lines
+}
+
+// renderHTML renders the session as a self-contained HTML page: a tab per
+// line with collapsible thoughts, word diffs for revisions, the final answer
+// and the session's records. It needs no scripts or external assets.
+func (s *SequentialThinkingServer) renderHTML() string {
+	report := htmlReport{
+		Generated:   time.Now().UTC(),
+		Thoughts:    len(s.thoughtHistory),
+		Lines:       s.htmlLines(),
+		Final:       s.conclusion(),
+		Hypotheses:  s.hypotheses,
+		Assumptions: s.assumptions,
+		Questions:   s.questions,
+	}
+	if s.finalAnswer != nil {
+		report.FinalAt = &s.finalAnswer.RecordedAt
+	}
+
+	var b strings.Builder
+	if err := htmlReportTemplate.Execute(&b, report); err != nil {
+		return fmt.Sprintf("<!DOCTYPE html><p>report failed: %s</p>", template.HTMLEscapeString(err.Error()))
+	}
+	return b.String()
+}
@@ -0,0 +1,167 @@
+package thinking
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	HypothesisOpen      = "open"
+	HypothesisConfirmed = "confirmed"
+	HypothesisRefuted   = "refuted"
+)
+
+// Hypothesis is a candidate solution tracked across the chain, with the
+// thoughts that back it up or contradict it.
+type Hypothesis struct {
+	ID                 string `json:"id"`
+	Statement          string `json:"statement"`
+	Status             string `json:"status"`
+	ProposedIn         int    `json:"proposedIn,omitempty"`
+	SupportingThoughts []int  `json:"supportingThoughts"`
+	RefutingThoughts   []int  `json:"refutingThoughts"`
+	Note               string `json:"note,omitempty"`
+}
+
+func (h Hypothesis) clone() Hypothesis {
+	h.SupportingThoughts = slices.Clone(h.SupportingThoughts)
+	h.RefutingThoughts = slices.Clone(h.RefutingThoughts)
+	return h
+}
+
+// hasThought reports whether any line holds a thought numbered n.
+func (s *SequentialThinkingServer) hasThought(n int) bool {
+	return slices.ContainsFunc(s.thoughtHistory, func(t ThoughtData) bool { return t.ThoughtNumber == n })
+}
+
+// thoughtNumbers reads an optional array of existing thought numbers.
+func (s *SequentialThinkingServer) thoughtNumbers(request mcp.CallToolRequest, key string) ([]int, error) {
+	numbers := request.GetIntSlice(key, nil)
+	for _, n := range numbers {
+		if !s.hasThought(n) {
+			return nil, invalid(key, "recorded thought numbers", n, "thought %d does not exist", n)
+		}
+	}
+	return numbers, nil
+}
+
+func (s *SequentialThinkingServer) findHypothesis(id string) *Hypothesis {
+	for i := range s.hypotheses {
+		if s.hypotheses[i].ID == id {
+			return &s.hypotheses[i]
+		}
+	}
+	return nil
+}
+
+func (s *SequentialThinkingServer) recordHypothesis(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	statement, err := request.RequireString("statement")
+	if err != nil || statement == "" {
+		return s.fail(ctx, request, invalid("statement", "non-empty string", request.GetArguments()["statement"], "must be a non-empty string"))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	proposedIn := request.GetInt("thoughtNumber", 0)
+	if proposedIn == 0 && len(s.thoughtHistory) > 0 {
+		proposedIn = s.thoughtHistory[len(s.thoughtHistory)-1].ThoughtNumber
+	} else if proposedIn != 0 && !s.hasThought(proposedIn) {
+		return s.fail(ctx, request, invalid("thoughtNumber", "recorded thought number", proposedIn, "thought %d does not exist", proposedIn))
+	}
+
+	s.hypothesisSeq++
+	h := Hypothesis{
+		ID:                 fmt.Sprintf("H%d", s.hypothesisSeq),
+		Statement:          statement,
+		Status:             HypothesisOpen,
+		ProposedIn:         proposedIn,
+		SupportingThoughts: make([]int, 0),
+		RefutingThoughts:   make([]int, 0),
+	}
+	s.hypotheses = append(s.hypotheses, h)
+
+	if !s.disableThoughtLogging {
+		s.logEvent(s.theme.Record, fmt.Sprintf("💡 Hypothesis %s:", h.ID), statement)
+	}
+
+	return s.respond(ctx, request, map[string]any{
+		"hypothesis": h,
+		"open":       s.countHypotheses(HypothesisOpen),
+	})
+}
+
+func (s *SequentialThinkingServer) verifyHypothesis(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	id, err := request.RequireString("hypothesisId")
+	if err != nil || id == "" {
+		return s.fail(ctx, request, invalid("hypothesisId", "non-empty string", request.GetArguments()["hypothesisId"], "must be a non-empty string"))
+	}
+	status := request.GetString("status", "")
+	switch status {
+	case "", HypothesisOpen, HypothesisConfirmed, HypothesisRefuted:
+	default:
+		return s.fail(ctx, request, invalid("status", "one of "+HypothesisOpen+", "+HypothesisConfirmed+", "+HypothesisRefuted, status, "must be one of %s, %s, %s", HypothesisOpen, HypothesisConfirmed, HypothesisRefuted))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	h := s.findHypothesis(id)
+	if h == nil {
+		return s.fail(ctx, request, invalid("hypothesisId", "recorded hypothesis ID", id, "unknown hypothesis %q", id))
+	}
+	supporting, err := s.thoughtNumbers(request, "supportingThoughts")
+	if err != nil {
+		return s.fail(ctx, request, err)
+	}
+	refuting, err := s.thoughtNumbers(request, "refutingThoughts")
+	if err != nil {
+		return s.fail(ctx, request, err)
+	}
+
+	for _, n := range supporting {
+		if !slices.Contains(h.SupportingThoughts, n) {
+			h.SupportingThoughts = append(h.SupportingThoughts, n)
+		}
+	}
+	for _, n := range refuting {
+		if !slices.Contains(h.RefutingThoughts, n) {
+			h.RefutingThoughts = append(h.RefutingThoughts, n)
+		}
+	}
+	if status != "" {
+		h.Status = status
+		if t := s.latestNumbered(h.ProposedIn); t != nil {
+			s.setStatus(t.Id, map[string]string{
+				HypothesisOpen:      ThoughtOpen,
+				HypothesisConfirmed: ThoughtConfirmed,
+				HypothesisRefuted:   ThoughtRetracted,
+			}[status])
+		}
+	}
+	if note := request.GetString("note", ""); note != "" {
+		h.Note = note
+	}
+
+	if !s.disableThoughtLogging {
+		s.logEvent(s.theme.Record, fmt.Sprintf("🔬 Hypothesis %s is %s", h.ID, h.Status), "")
+	}
+
+	return s.respond(ctx, request, map[string]any{
+		"hypothesis": h,
+		"open":       s.countHypotheses(HypothesisOpen),
+	})
+}
+
+func (s *SequentialThinkingServer) countHypotheses(status string) int {
+	n := 0
+	for _, h := range s.hypotheses {
+		if h.Status == status {
+			n++
+		}
+	}
+	return n
+}
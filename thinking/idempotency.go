@@ -0,0 +1,72 @@
+package thinking
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+)
+
+// callLimit is how many recent sequentialthinking calls are remembered for
+// retries.
+const callLimit = 64
+
+// call is an accepted sequentialthinking call and its result, remembered so
+// that a retry of it can be answered without recording the thought again.
+type call struct {
+	key    string
+	args   map[string]any
+	result map[string]any
+	// length is the length of the history right after the call.
+	length int
+}
+
+// replay returns the result of the earlier call args retries, marked as
+// replayed: the call with the same idempotencyKey or, for calls without
+// one, the latest call if it sent the same arguments and nothing was
+// recorded since. It returns nil for a new call, and an error for a key
+// reused with other arguments.
+func (s *SequentialThinkingServer) replay(args map[string]any) (map[string]any, error) {
+	key, _ := args["idempotencyKey"].(string)
+	var match *call
+	if key != "" {
+		i := slices.IndexFunc(s.calls, func(c call) bool { return c.key == key })
+		if i < 0 {
+			return nil, nil
+		}
+		match = &s.calls[i]
+		if !reflect.DeepEqual(match.args, args) {
+			return nil, invalid("idempotencyKey", "a key not used before", key,
+				"key %q was already used for another thought; use a new key for each thought", key)
+		}
+	} else {
+		if len(s.calls) == 0 {
+			return nil, nil
+		}
+		match = &s.calls[len(s.calls)-1]
+		if match.length != len(s.thoughtHistory) || !reflect.DeepEqual(withoutKey(match.args), args) {
+			return nil, nil
+		}
+	}
+	result := maps.Clone(match.result)
+	result["replayed"] = true
+	return result, nil
+}
+
+// remember keeps an accepted call for replay, forgetting the oldest beyond
+// callLimit.
+func (s *SequentialThinkingServer) remember(args, result map[string]any) {
+	key, _ := args["idempotencyKey"].(string)
+	s.calls = append(s.calls, call{key: key, args: args, result: result, length: len(s.thoughtHistory)})
+	if len(s.calls) > callLimit {
+		s.calls = slices.Delete(s.calls, 0, len(s.calls)-callLimit)
+	}
+}
+
+func withoutKey(args map[string]any) map[string]any {
+	if _, ok := args["idempotencyKey"]; !ok {
+		return args
+	}
+	args = maps.Clone(args)
+	delete(args, "idempotencyKey")
+	return args
+}
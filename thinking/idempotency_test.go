@@ -0,0 +1,117 @@
+package thinking
+
+import "testing"
+
+func TestReplay(t *testing.T) {
+	tests := []struct {
+		name         string
+		calls        []map[string]any
+		wantReplayed bool
+		wantErr      bool
+		wantLength   int
+	}{
+		{
+			name: "same key and arguments",
+			calls: []map[string]any{
+				thought(1, 2, "a", map[string]any{"idempotencyKey": "k"}),
+				thought(1, 2, "a", map[string]any{"idempotencyKey": "k"}),
+			},
+			wantReplayed: true,
+			wantLength:   1,
+		},
+		{
+			name: "same key after other thoughts",
+			calls: []map[string]any{
+				thought(1, 2, "a", map[string]any{"idempotencyKey": "k"}),
+				thought(2, 2, "b", nil),
+				thought(1, 2, "a", map[string]any{"idempotencyKey": "k"}),
+			},
+			wantReplayed: true,
+			wantLength:   2,
+		},
+		{
+			name: "same key, other arguments",
+			calls: []map[string]any{
+				thought(1, 2, "a", map[string]any{"idempotencyKey": "k"}),
+				thought(2, 2, "b", map[string]any{"idempotencyKey": "k"}),
+			},
+			wantErr:    true,
+			wantLength: 1,
+		},
+		{
+			name: "no key, same arguments",
+			calls: []map[string]any{
+				thought(1, 2, "a", nil),
+				thought(1, 2, "a", nil),
+			},
+			wantReplayed: true,
+			wantLength:   1,
+		},
+		{
+			name: "no key, same arguments after another thought",
+			calls: []map[string]any{
+				thought(1, 3, "a", nil),
+				thought(2, 3, "b", nil),
+				thought(1, 3, "a", map[string]any{"isRevision": true, "revisesThought": 1.0}),
+			},
+			wantLength: 3,
+		},
+		{
+			name: "new key",
+			calls: []map[string]any{
+				thought(1, 2, "a", map[string]any{"idempotencyKey": "k1"}),
+				thought(2, 2, "b", map[string]any{"idempotencyKey": "k2"}),
+			},
+			wantLength: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t)
+			var first map[string]any
+			for i, args := range tt.calls[:len(tt.calls)-1] {
+				fields := mustCall(t, s.processThought, args)
+				if i == 0 {
+					first = fields
+				}
+			}
+			fields, isError := callTool(t, s.processThought, tt.calls[len(tt.calls)-1])
+			if isError != tt.wantErr {
+				t.Fatalf("error = %v, want %v: %v", isError, tt.wantErr, fields["error"])
+			}
+			if replayed, _ := fields["replayed"].(bool); replayed != tt.wantReplayed {
+				t.Errorf("replayed = %v, want %v", replayed, tt.wantReplayed)
+			}
+			if tt.wantReplayed && fields["id"] != first["id"] {
+				t.Errorf("replayed id = %v, want %v", fields["id"], first["id"])
+			}
+			if len(s.thoughtHistory) != tt.wantLength {
+				t.Errorf("history length = %d, want %d", len(s.thoughtHistory), tt.wantLength)
+			}
+		})
+	}
+}
+
+func TestReplayAcrossRestore(t *testing.T) {
+	s := newTestServer(t)
+	keyed := thought(1, 2, "a", map[string]any{"idempotencyKey": "k"})
+	mustCall(t, s.processThought, keyed)
+
+	// A rolled-back batch keeps what was remembered before it.
+	callTool(t, s.thinkBatch, map[string]any{"thoughts": []any{thought(2, 2, "b", nil), "c"}})
+	if replayed, _ := mustCall(t, s.processThought, keyed)["replayed"].(bool); !replayed {
+		t.Errorf("call was forgotten after a rejected batch")
+	}
+
+	// A checkpoint restore forgets calls that recorded thoughts it drops.
+	mustCall(t, s.checkpoint, map[string]any{"label": "first"})
+	mustCall(t, s.processThought, thought(2, 2, "b", map[string]any{"idempotencyKey": "k2"}))
+	mustCall(t, s.restoreCheckpoint, map[string]any{"label": "first"})
+	fields := mustCall(t, s.processThought, thought(2, 2, "b", map[string]any{"idempotencyKey": "k2"}))
+	if replayed, _ := fields["replayed"].(bool); replayed {
+		t.Errorf("call was replayed after the checkpoint dropped its thought")
+	}
+	if len(s.thoughtHistory) != 2 {
+		t.Errorf("history length = %d, want 2", len(s.thoughtHistory))
+	}
+}
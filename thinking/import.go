@@ -0,0 +1,106 @@
+package thinking
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// checkImported holds an imported thought to the ordering a live session
+// would have produced: numbers rise along each line, branches fork from a
+// recorded main-line thought. Revision targets were already resolved by
+// accept.
+func (s *SequentialThinkingServer) checkImported(data *ThoughtData) error {
+	switch {
+	case data.BranchId == nil && data.BranchFromThought != nil:
+		return invalid("branchId", "non-empty string", nil, "branchId and branchFromThought must be set together")
+	case data.BranchId != nil && data.BranchFromThought == nil:
+		return invalid("branchFromThought", "integer", nil, "branchId and branchFromThought must be set together")
+	}
+
+	line := branchOf(data)
+	previous := 0
+	if branch := s.branches[line]; branch != nil {
+		if origin := s.branchOrigin(line); *data.BranchFromThought != origin {
+			return invalid("branchFromThought", fmt.Sprint(origin), *data.BranchFromThought,
+				"branch %q forks from thought %d, not %d", line, origin, *data.BranchFromThought)
+		}
+		previous = branch.Thoughts[len(branch.Thoughts)-1].ThoughtNumber
+	} else if line != "" {
+		if s.indexBefore("", *data.BranchFromThought, len(s.thoughtHistory)) < 0 {
+			return invalid("branchFromThought", "recorded main-line thought number", *data.BranchFromThought,
+				"thought %d does not exist in %s", *data.BranchFromThought, describeScope(""))
+		}
+		previous = *data.BranchFromThought
+	} else {
+		for _, t := range s.thoughtHistory {
+			if branchOf(&t) == "" {
+				previous = t.ThoughtNumber
+			}
+		}
+	}
+	if data.ThoughtNumber <= previous {
+		return invalid("thoughtNumber", fmt.Sprintf("integer above %d", previous), data.ThoughtNumber,
+			"%d does not follow thought %d in %s", data.ThoughtNumber, previous, describeScope(line))
+	}
+	return nil
+}
+
+func (s *SequentialThinkingServer) importThoughts(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	items, ok := request.GetArguments()["thoughts"].([]any)
+	if dump := request.GetString("dump", ""); dump != "" {
+		thoughts, err := typeScriptThoughts([]byte(dump))
+		if err != nil {
+			return s.fail(ctx, request, invalid("dump", "TypeScript server history dump", nil, "%v", err))
+		}
+		items, ok = make([]any, len(thoughts)), true
+		for i, t := range thoughts {
+			items[i] = t
+		}
+	}
+	if !ok || len(items) == 0 {
+		return s.fail(ctx, request, invalid("thoughts", "non-empty array of thought objects", request.GetArguments()["thoughts"], "must be a non-empty array of thought objects"))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	before := s.snapshot()
+	if request.GetBool("replace", false) {
+		s.reset()
+	}
+	logging := s.disableThoughtLogging
+	s.disableThoughtLogging = true
+	defer func() { s.disableThoughtLogging = logging }()
+
+	for i, item := range items {
+		args, ok := item.(map[string]any)
+		if !ok {
+			s.restore(before)
+			return s.fail(ctx, request, notAnObject("thoughts", i, item))
+		}
+		data, err := s.validateThoughtData(normalizeThought(args))
+		if err == nil {
+			_, err = s.accept(data)
+		}
+		if err == nil {
+			err = s.checkImported(data)
+		}
+		if err != nil {
+			s.restore(before)
+			return s.fail(ctx, request, inItem("thoughts", i, err))
+		}
+		s.record(ctx, data)
+	}
+
+	if !logging {
+		s.logEvent(s.theme.Record, fmt.Sprintf("📥 Imported %d thoughts", len(items)), "")
+	}
+
+	return s.respond(ctx, request, map[string]any{
+		"imported":             len(items),
+		"branches":             s.branchNames(),
+		"thoughtHistoryLength": len(s.thoughtHistory),
+	})
+}
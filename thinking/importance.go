@@ -0,0 +1,50 @@
+package thinking
+
+import (
+	"cmp"
+	"slices"
+)
+
+// defaultImportance ranks thoughts that weren't given an importance.
+const defaultImportance = 0.5
+
+// keyThoughtCount is how many of the most important thoughts a summary
+// lists.
+const keyThoughtCount = 5
+
+func importanceOf(t *ThoughtData) float64 {
+	if t.Importance == nil {
+		return defaultImportance
+	}
+	return *t.Importance
+}
+
+// rankByImportance orders history indexes by the importance of their
+// thoughts, most important first; among equally important thoughts, the
+// later one comes first.
+func (s *SequentialThinkingServer) rankByImportance(indexes []int) {
+	slices.SortFunc(indexes, func(a, b int) int {
+		if c := cmp.Compare(importanceOf(&s.thoughtHistory[b]), importanceOf(&s.thoughtHistory[a])); c != 0 {
+			return c
+		}
+		return cmp.Compare(b, a)
+	})
+}
+
+// keyThoughts returns the standing thoughts given an importance of at least
+// defaultImportance, most important first, up to keyThoughtCount.
+func (s *SequentialThinkingServer) keyThoughts() []ThoughtRef {
+	live := s.liveThoughts()
+	var indexes []int
+	for i := range s.thoughtHistory {
+		if t := &s.thoughtHistory[i]; t.Importance != nil && *t.Importance >= defaultImportance && live(t) {
+			indexes = append(indexes, i)
+		}
+	}
+	s.rankByImportance(indexes)
+	var refs []ThoughtRef
+	for _, i := range indexes[:min(len(indexes), keyThoughtCount)] {
+		refs = append(refs, refTo(&s.thoughtHistory[i], s.thoughtHistory[i].Thought))
+	}
+	return refs
+}
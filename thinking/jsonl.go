@@ -0,0 +1,26 @@
+package thinking
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// WriteJSONL streams the thought history to w as JSON Lines, one thought per
+// line in recording order, without building the whole document in memory.
+// The session stays locked until the last thought is written.
+func (s *SequentialThinkingServer) WriteJSONL(w io.Writer) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.writeJSONL(w)
+}
+
+func (s *SequentialThinkingServer) writeJSONL(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	for i := range s.thoughtHistory {
+		if err := enc.Encode(&s.thoughtHistory[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package thinking
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"unicode/utf8"
+)
+
+// defaultMaxThoughtLength caps the characters of a thought unless
+// GOTHINK_MAX_THOUGHT_LENGTH or WithMaxThoughtLength sets another cap.
+const defaultMaxThoughtLength = 20000
+
+// LengthMode is what happens to a thought longer than the cap.
+type LengthMode string
+
+const (
+	// LengthReject rejects the thought.
+	LengthReject LengthMode = "reject"
+	// LengthTruncate records the start of the thought followed by a marker
+	// giving the number of characters cut, with a warning in the result.
+	LengthTruncate LengthMode = "truncate"
+)
+
+// WithMaxThoughtLength caps the characters of a thought, overriding
+// GOTHINK_MAX_THOUGHT_LENGTH, and sets what happens to longer ones,
+// overriding GOTHINK_LONG_THOUGHTS.
+func WithMaxThoughtLength(n int, mode LengthMode) Option {
+	return func(s *SequentialThinkingServer) {
+		s.maxThoughtLength, s.longThoughts = n, mode
+	}
+}
+
+func maxThoughtLengthFromEnv() int {
+	if n, err := strconv.Atoi(os.Getenv("GOTHINK_MAX_THOUGHT_LENGTH")); err == nil && n > 0 {
+		return n
+	}
+	return defaultMaxThoughtLength
+}
+
+func lengthModeFromEnv() LengthMode {
+	switch name := os.Getenv("GOTHINK_LONG_THOUGHTS"); name {
+	case "", string(LengthReject):
+		return LengthReject
+	case string(LengthTruncate):
+		return LengthTruncate
+	default:
+		fmt.Fprintf(os.Stderr, "unknown long thought mode %q; using %s\n", name, LengthReject)
+		return LengthReject
+	}
+}
+
+// checkLength handles a thought longer than the cap: it is rejected, or cut
+// to the cap with a marker saying how much was left out, and a warning.
+func (s *SequentialThinkingServer) checkLength(data *ThoughtData) (string, error) {
+	length := utf8.RuneCountInString(data.Thought)
+	if length <= s.maxThoughtLength {
+		return "", nil
+	}
+	if s.longThoughts != LengthTruncate {
+		return "", invalid("thought", fmt.Sprintf("string of at most %d characters", s.maxThoughtLength), length,
+			"must be at most %d characters, got %d; split it into several thoughts", s.maxThoughtLength, length)
+	}
+
+	marker := fmt.Sprintf(" [truncated %d characters]", length-s.maxThoughtLength)
+	cut := 0
+	for range s.maxThoughtLength {
+		_, size := utf8.DecodeRuneInString(data.Thought[cut:])
+		cut += size
+	}
+	data.Thought = data.Thought[:cut] + marker
+	return fmt.Sprintf("thought %d was %d characters long, so it was truncated to %d", data.ThoughtNumber, length, s.maxThoughtLength), nil
+}
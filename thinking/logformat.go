@@ -0,0 +1,150 @@
+package thinking
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/mattn/go-runewidth"
+)
+
+// LogFormat is the style of the stderr log.
+type LogFormat string
+
+const (
+	// LogFormatBox draws each thought in a box and each event on its own
+	// paragraph.
+	LogFormatBox LogFormat = "box"
+	// LogFormatCompact prints one line per thought or event.
+	LogFormatCompact LogFormat = "compact"
+	// LogFormatJSON prints one JSON object per thought or event, for piping
+	// into other tools.
+	LogFormatJSON LogFormat = "json"
+	// LogFormatTimeline prints compact lines starting with the time each
+	// thought arrived and the gap since the previous one.
+	LogFormatTimeline LogFormat = "timeline"
+)
+
+var logFormats = []LogFormat{LogFormatBox, LogFormatCompact, LogFormatJSON, LogFormatTimeline}
+
+// WithLogFormat sets the style of the stderr log, overriding
+// GOTHINK_LOG_FORMAT.
+func WithLogFormat(format LogFormat) Option {
+	return func(s *SequentialThinkingServer) {
+		s.logFormat = format
+	}
+}
+
+func logFormatFromEnv() LogFormat {
+	name := os.Getenv("GOTHINK_LOG_FORMAT")
+	if name == "" {
+		return LogFormatBox
+	}
+	for _, format := range logFormats {
+		if string(format) == name {
+			return format
+		}
+	}
+	fmt.Fprintf(os.Stderr, "unknown log format %q; using %s\n", name, LogFormatBox)
+	return LogFormatBox
+}
+
+// logEntry is a line of the JSON log.
+type logEntry struct {
+	Time    time.Time    `json:"time"`
+	Kind    string       `json:"kind"`
+	Message string       `json:"message,omitempty"`
+	Detail  string       `json:"detail,omitempty"`
+	Thought *ThoughtData `json:"thought,omitempty"`
+	Diff    []DiffOp     `json:"diff,omitempty"`
+}
+
+func writeLogEntry(entry logEntry) {
+	entry.Time = time.Now().UTC()
+	jsonBytes, _ := json.Marshal(entry)
+	fmt.Fprintf(os.Stderr, "%s\n", jsonBytes)
+}
+
+// logEvent logs something that happened to the session other than a
+// thought: a headline in color c, followed by an optional detail such as
+// the statement of a new hypothesis.
+func (s *SequentialThinkingServer) logEvent(c *color.Color, headline, detail string) {
+	if s.theme.ASCII {
+		headline = plainHeadline(headline)
+	}
+	if s.logFormat != LogFormatJSON {
+		headline, detail = Printable(headline), Printable(detail)
+	}
+	switch s.logFormat {
+	case LogFormatJSON:
+		writeLogEntry(logEntry{Kind: "event", Message: headline, Detail: detail})
+	case LogFormatCompact, LogFormatTimeline:
+		line := paintf(c, "%s", headline)
+		if detail != "" {
+			line += " " + strings.Join(strings.Fields(detail), " ")
+		}
+		fmt.Fprintf(os.Stderr, "%s\n", line)
+	default:
+		if detail != "" {
+			headline = paintf(c, "%s", headline) + " " + detail
+		} else {
+			headline = paintf(c, "%s", headline)
+		}
+		fmt.Fprintf(os.Stderr, "\n%s\n", headline)
+	}
+}
+
+// writeThought logs a thought to stderr in the configured format. Revisions
+// come with their word diff against the revised thought.
+func (s *SequentialThinkingServer) writeThought(data *ThoughtData) {
+	diff := s.revisionDiff(data)
+	switch s.logFormat {
+	case LogFormatJSON:
+		writeLogEntry(logEntry{Kind: "thought", Thought: data, Diff: diff})
+	case LogFormatCompact:
+		fmt.Fprintf(os.Stderr, "%s\n", formatThoughtLine(data, diff, s.logWidth(), s.theme))
+	case LogFormatTimeline:
+		s.writeTimelineLine(data, diff)
+	default:
+		fmt.Fprintf(os.Stderr, "%s\n", formatBox(data, diff, s.logWidth(), s.theme))
+	}
+}
+
+// formatThoughtLine describes a thought on one line of at most width
+// columns: its number, line, what it revises (with the size of the change)
+// or merges, and as much of its text as fits.
+func formatThoughtLine(data *ThoughtData, diff []DiffOp, width int, theme Theme) string {
+	label, paint := "Thought", theme.Thought
+	var context string
+	switch {
+	case data.MergedBranchId != nil:
+		label, paint = "Merge", theme.Merge
+		context = " merges " + *data.MergedBranchId
+	case data.IsRevision != nil && *data.IsRevision:
+		label, paint = "Revision", theme.Revision
+		if data.RevisesThought != nil {
+			context = fmt.Sprintf(" revises %d", *data.RevisesThought)
+		}
+		if diff != nil {
+			deleted, inserted := diffStats(diff)
+			context += fmt.Sprintf(" (-%d +%d words)", deleted, inserted)
+		}
+	case branchOf(data) != "":
+		label, paint = "Branch", theme.Branch
+	}
+	ellipsis := "..."
+	if !theme.ASCII {
+		label, ellipsis = kindEmoji[label], "…"
+	}
+	head := fmt.Sprintf("%s %d/%d", label, data.ThoughtNumber, data.TotalThoughts)
+	if id := branchOf(data); id != "" {
+		head += " [" + id + "]"
+	}
+	head = Printable(head + context)
+	text := Printable(strings.Join(strings.Fields(data.Thought), " "))
+	text = runewidth.Truncate(text, max(width-runewidth.StringWidth(head)-1, 8), ellipsis)
+	return paintf(paint, "%s", head) + " " + text
+}
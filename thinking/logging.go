@@ -0,0 +1,35 @@
+package thinking
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// thoughtLogger names the logger of thought log messages.
+const thoughtLogger = "thoughts"
+
+// thoughtLevel is the log level of a recorded thought: revisions and merges
+// change earlier conclusions, so they are notices; plain steps are info.
+func thoughtLevel(data *ThoughtData) mcp.LoggingLevel {
+	if data.MergedBranchId != nil || data.IsRevision != nil && *data.IsRevision {
+		return mcp.LoggingLevelNotice
+	}
+	return mcp.LoggingLevelInfo
+}
+
+// logThought sends a recorded thought to the client as an MCP log message
+// when the client asked for messages at its level through logging/setLevel.
+// Otherwise the thought goes to the stderr log.
+func (s *SequentialThinkingServer) logThought(ctx context.Context, data *ThoughtData) {
+	level := thoughtLevel(data)
+	session, ok := server.ClientSessionFromContext(ctx).(server.SessionWithLogging)
+	if s.srv != nil && ok && level.ShouldSendTo(session.GetLogLevel()) {
+		err := s.srv.SendLogMessageToClient(ctx, mcp.NewLoggingMessageNotification(level, thoughtLogger, data))
+		if err == nil {
+			return
+		}
+	}
+	s.writeThought(data)
+}
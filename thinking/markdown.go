@@ -0,0 +1,71 @@
+package thinking
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Markdown renders the whole session as one readable document: the main
+// line, a section per branch, the final answer and the session's records.
+func (s *SequentialThinkingServer) Markdown() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.renderMarkdown()
+}
+
+func (s *SequentialThinkingServer) renderMarkdown() string {
+	var b strings.Builder
+	branches := s.branchNames()
+	b.WriteString("# Thinking session\n\n")
+	fmt.Fprintf(&b, "- Thoughts: %d\n- Branches: %d", len(s.thoughtHistory), len(branches))
+	if n := s.abandonedBranches(); n > 0 {
+		fmt.Fprintf(&b, " (%d abandoned, not shown)", n)
+	}
+	b.WriteString("\n\n")
+
+	b.WriteString("## Main line\n\n")
+	for i := range s.thoughtHistory {
+		if t := &s.thoughtHistory[i]; branchOf(t) == "" {
+			s.writeSessionThought(&b, t)
+		}
+	}
+
+	for _, id := range branches {
+		branch := s.branches[id]
+		thoughts := branch.Thoughts
+		fmt.Fprintf(&b, "## Branch %s\n\nBranched from thought %d", id, branch.BranchFromThought)
+		if into, ok := s.mergedInto(id); ok {
+			fmt.Fprintf(&b, ", merged into %s", describeScope(into))
+		}
+		b.WriteString(".\n\n")
+		if branch.Description != "" {
+			fmt.Fprintf(&b, "%s\n\n", branch.Description)
+		}
+		for i := range thoughts {
+			s.writeSessionThought(&b, &thoughts[i])
+		}
+	}
+
+	if final := s.conclusion(); final != nil {
+		fmt.Fprintf(&b, "## Final answer\n\nConcluded at thought %d.\n\n%s\n", final.ThoughtNumber, final.Thought)
+	}
+
+	s.writeRecordsMarkdown(&b, 2)
+	return b.String()
+}
+
+// writeSessionThought writes a thought with a callout naming the thoughts
+// that revised it.
+func (s *SequentialThinkingServer) writeSessionThought(b *strings.Builder, t *ThoughtData) {
+	writeThoughtMarkdown(b, t, 3, s.revisionDiff(t))
+	revisions := s.revisionsOf(branchOf(t), t.ThoughtNumber)
+	if len(revisions) == 0 {
+		return
+	}
+	numbers := make([]int, len(revisions))
+	for i, r := range revisions {
+		numbers[i] = r.ThoughtNumber
+	}
+	fmt.Fprintf(b, "> Revised by thought %s\n\n", joinInts(numbers))
+}
@@ -0,0 +1,115 @@
+package thinking
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// conclusions picks the thoughts of a branch worth referencing from a merge:
+// those reading like decisions, plus the branch tip.
+func conclusions(thoughts []ThoughtData) []int {
+	seen := make(map[int]bool)
+	for _, t := range thoughts {
+		if decisionPattern.MatchString(t.Thought) {
+			seen[t.ThoughtNumber] = true
+		}
+	}
+	seen[thoughts[len(thoughts)-1].ThoughtNumber] = true
+
+	numbers := make([]int, 0, len(seen))
+	for n := range seen {
+		numbers = append(numbers, n)
+	}
+	sort.Ints(numbers)
+	return numbers
+}
+
+func (s *SequentialThinkingServer) mergeBranches(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	source, err := request.RequireString("branchId")
+	if err != nil || source == "" {
+		return s.fail(ctx, request, invalid("branchId", "non-empty string", request.GetArguments()["branchId"], "must be a non-empty string"))
+	}
+	target := request.GetString("into", "")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	branch := s.branches[source]
+	switch {
+	case branch == nil:
+		return s.fail(ctx, request, invalid("branchId", "recorded branch ID", source, "unknown branch %q", source))
+	case source == target:
+		return s.fail(ctx, request, invalid("into", "a branch other than branchId", target, "cannot merge branch %q into itself", source))
+	case target != "" && s.branches[target] == nil:
+		return s.fail(ctx, request, invalid("into", "recorded branch ID", target, "unknown branch %q", target))
+	}
+	for _, side := range []struct{ field, id string }{{"branchId", source}, {"into", target}} {
+		if _, ok := s.abandonReason(side.id); ok {
+			return s.fail(ctx, request, invalid(side.field, "branch that wasn't abandoned", side.id, "branch %q was abandoned", side.id))
+		}
+	}
+	if into, ok := s.mergedInto(source); ok {
+		return s.fail(ctx, request, invalid("branchId", "branch that wasn't merged", source, "branch %q was already merged into %s", source, describeScope(into)))
+	}
+	// A merged branch takes no more thoughts, merges included, which also
+	// rules out merging a branch back into one merged into it.
+	if into, ok := s.mergedInto(target); ok {
+		return s.fail(ctx, request, invalid("into", "branch that wasn't merged", target, "branch %q was already merged into %s", target, describeScope(into)))
+	}
+
+	thoughts := branch.Thoughts
+	merge := &ThoughtData{
+		NextThoughtNeeded: request.GetBool("nextThoughtNeeded", true),
+		MergedBranchId:    &source,
+		MergedThoughts:    conclusions(thoughts),
+	}
+	for _, t := range s.thoughtHistory {
+		merge.ThoughtNumber = max(merge.ThoughtNumber, t.ThoughtNumber)
+		merge.TotalThoughts = t.TotalThoughts
+	}
+	merge.ThoughtNumber++
+	merge.TotalThoughts = max(merge.TotalThoughts, merge.ThoughtNumber)
+	if err := s.checkCapacity(1); err != nil {
+		return s.fail(ctx, request, err)
+	}
+	if err := s.checkBounds(merge); err != nil {
+		return s.fail(ctx, request, invalid("branchId", fmt.Sprintf("branch whose merge fits in %d thoughts", s.maxThoughts), source,
+			"cannot merge: the merge would be thought %d of %d, past the limit of %d", merge.ThoughtNumber, merge.TotalThoughts, s.maxThoughts))
+	}
+
+	merge.Thought = request.GetString("thought", "")
+	if merge.Thought == "" {
+		merge.Thought = fmt.Sprintf("Merged branch %s (conclusion: %s)", source, excerpt(thoughts[len(thoughts)-1].Thought))
+	}
+	warning, err := s.checkLength(merge)
+	if err != nil {
+		return s.fail(ctx, request, err)
+	}
+
+	if target != "" {
+		into, from := target, s.branches[target].BranchFromThought
+		merge.BranchId, merge.BranchFromThought = &into, &from
+	}
+
+	s.record(ctx, merge)
+	branch.Status, branch.MergedInto = BranchMerged, &target
+	s.resourcesChanged(source)
+
+	result := map[string]any{
+		"thoughtNumber":        merge.ThoughtNumber,
+		"totalThoughts":        merge.TotalThoughts,
+		"mergedBranchId":       source,
+		"into":                 target,
+		"mergedThoughts":       merge.MergedThoughts,
+		"branches":             s.branchNames(),
+		"thoughtHistoryLength": len(s.thoughtHistory),
+	}
+	if warning != "" {
+		s.logWarnings([]string{warning})
+		result["warnings"] = []string{warning}
+	}
+	return s.respond(ctx, request, result)
+}
@@ -0,0 +1,60 @@
+package thinking
+
+import "testing"
+
+func TestMergedBranchesAreClosed(t *testing.T) {
+	tests := []struct {
+		name     string
+		tool     func(s *SequentialThinkingServer) handler
+		args     map[string]any
+		wantPath string
+	}{
+		{
+			name:     "merge again",
+			tool:     func(s *SequentialThinkingServer) handler { return s.mergeBranches },
+			args:     map[string]any{"branchId": "a"},
+			wantPath: "/branchId",
+		},
+		{
+			name:     "merge into a merged branch",
+			tool:     func(s *SequentialThinkingServer) handler { return s.mergeBranches },
+			args:     map[string]any{"branchId": "c", "into": "a"},
+			wantPath: "/into",
+		},
+		{
+			name:     "merge back into a branch merged into the source",
+			tool:     func(s *SequentialThinkingServer) handler { return s.mergeBranches },
+			args:     map[string]any{"branchId": "c", "into": "b"},
+			wantPath: "/into",
+		},
+		{
+			name:     "new thought on a merged branch",
+			tool:     func(s *SequentialThinkingServer) handler { return s.processThought },
+			args:     thought(3, 4, "more", map[string]any{"branchId": "a", "branchFromThought": 1.0}),
+			wantPath: "/branchId",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t)
+			mustCall(t, s.processThought, thought(1, 4, "root", nil))
+			for _, id := range []string{"a", "b", "c"} {
+				mustCall(t, s.processThought, thought(2, 4, id, map[string]any{"branchId": id, "branchFromThought": 1.0}))
+			}
+			mustCall(t, s.mergeBranches, map[string]any{"branchId": "a"})
+			mustCall(t, s.mergeBranches, map[string]any{"branchId": "b", "into": "c"})
+			length := len(s.thoughtHistory)
+
+			fields, isError := callTool(t, tt.tool(s), tt.args)
+			if !isError {
+				t.Fatalf("call succeeded: %v", fields)
+			}
+			if fields["path"] != tt.wantPath {
+				t.Errorf("path = %v, want %s (%v)", fields["path"], tt.wantPath, fields["error"])
+			}
+			if len(s.thoughtHistory) != length {
+				t.Errorf("a rejected call recorded %d thoughts", len(s.thoughtHistory)-length)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package thinking
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// graphLabelLength is the most characters of a thought shown in graph nodes.
+const graphLabelLength = 48
+
+// graphLabel shortens a thought to a one-line node label.
+func graphLabel(t *ThoughtData) string {
+	text := strings.Join(strings.Fields(t.Thought), " ")
+	if runes := []rune(text); len(runes) > graphLabelLength {
+		text = string(runes[:graphLabelLength-1]) + "…"
+	}
+	return fmt.Sprintf("%d: %s", t.ThoughtNumber, text)
+}
+
+// allBranches lists every branch ID, abandoned ones included, in sorted order.
+func (s *SequentialThinkingServer) allBranches() []string {
+	ids := make([]string, 0, len(s.branches))
+	for id := range s.branches {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// mermaidArrows draws each kind of graph edge.
+var mermaidArrows = map[string]string{
+	"sequence": "-->",
+	"branch":   "-->|branch|",
+	"revision": "-.->|revised by|",
+	"merge":    "==>|merge|",
+}
+
+var mermaidEscaper = strings.NewReplacer(`"`, "#quot;")
+
+// renderMermaid draws the thought graph as a Mermaid flowchart. Each branch
+// is a subgraph, abandoned ones dashed, and revisions are highlighted.
+func (s *SequentialThinkingServer) renderMermaid() string {
+	var b strings.Builder
+	b.WriteString("graph TD\n")
+	b.WriteString("  classDef revision fill:#fff3cd,stroke:#d39e00\n")
+
+	lines := make(map[string][]int)
+	for i := range s.thoughtHistory {
+		line := branchOf(&s.thoughtHistory[i])
+		lines[line] = append(lines[line], i)
+	}
+	nodes := func(indent string, indices []int) {
+		for _, i := range indices {
+			t := &s.thoughtHistory[i]
+			fmt.Fprintf(&b, "%sT%d[\"%s\"]", indent, i, mermaidEscaper.Replace(graphLabel(t)))
+			if t.IsRevision != nil && *t.IsRevision {
+				b.WriteString(":::revision")
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	nodes("  ", lines[""])
+	for n, id := range s.allBranches() {
+		fmt.Fprintf(&b, "  subgraph B%d[\"branch %s\"]\n", n+1, mermaidEscaper.Replace(id))
+		nodes("    ", lines[id])
+		b.WriteString("  end\n")
+		if _, ok := s.abandonReason(id); ok {
+			fmt.Fprintf(&b, "  style B%d stroke-dasharray: 5 5\n", n+1)
+		}
+	}
+
+	children, _ := s.thoughtGraph()
+	for _, edges := range children {
+		for _, e := range edges {
+			fmt.Fprintf(&b, "  T%d %s T%d\n", e.from, mermaidArrows[e.kind], e.to)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,61 @@
+package thinking
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// chatMessage is a chat turn in the shape shared by the OpenAI and Anthropic
+// message APIs: a role and an array of text content blocks.
+type chatMessage struct {
+	Role    string         `json:"role"`
+	Content []messageBlock `json:"content"`
+}
+
+// messageBlock is a text content block of a chatMessage.
+type messageBlock struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// thoughtHeading introduces a thought in a replayed turn, naming the line and
+// the thought it revises or the branch it merges.
+func thoughtHeading(t *ThoughtData) string {
+	heading := fmt.Sprintf("Thought %d/%d", t.ThoughtNumber, t.TotalThoughts)
+	if line := branchOf(t); line != "" {
+		heading += fmt.Sprintf(" on branch %s (from thought %d)", line, *t.BranchFromThought)
+	}
+	switch {
+	case t.MergedBranchId != nil:
+		heading += fmt.Sprintf(", merging branch %s", *t.MergedBranchId)
+	case t.RevisesThought != nil:
+		heading += fmt.Sprintf(", revising thought %d", *t.RevisesThought)
+		if t.RevisesBranchId != nil && *t.RevisesBranchId != branchOf(t) {
+			heading += " in " + describeScope(*t.RevisesBranchId)
+		}
+	}
+	return heading
+}
+
+// messages converts the session into assistant turns, one per thought in
+// recording order, followed by the final answer, so the trace can be
+// replayed through evaluation harnesses.
+func (s *SequentialThinkingServer) messages() []chatMessage {
+	messages := make([]chatMessage, 0, len(s.thoughtHistory)+1)
+	turn := func(text string) {
+		messages = append(messages, chatMessage{Role: "assistant", Content: []messageBlock{{Type: "text", Text: text}}})
+	}
+	for i := range s.thoughtHistory {
+		t := &s.thoughtHistory[i]
+		turn(fmt.Sprintf("%s:\n\n%s", thoughtHeading(t), t.Thought))
+	}
+	if s.finalAnswer != nil {
+		turn(fmt.Sprintf("Final answer:\n\n%s", s.finalAnswer.Answer))
+	}
+	return messages
+}
+
+func (s *SequentialThinkingServer) renderMessages() (string, error) {
+	jsonBytes, err := json.MarshalIndent(s.messages(), "", "  ")
+	return string(jsonBytes), err
+}
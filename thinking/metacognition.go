@@ -0,0 +1,119 @@
+package thinking
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// lowConfidence is the confidence below which a topic is surfaced with
+// every tool result.
+const lowConfidence = 0.5
+
+// KnowledgeAssessment records what is known, assumed and unknown about a
+// topic, and how confident the reasoning is about it.
+type KnowledgeAssessment struct {
+	Topic         string   `json:"topic"`
+	Known         []string `json:"known"`
+	Assumed       []string `json:"assumed"`
+	Unknown       []string `json:"unknown"`
+	Confidence    float64  `json:"confidence"`
+	Calibration   string   `json:"calibration,omitempty"`
+	ThoughtNumber int      `json:"thoughtNumber,omitempty"`
+}
+
+func (k KnowledgeAssessment) clone() KnowledgeAssessment {
+	k.Known = slices.Clone(k.Known)
+	k.Assumed = slices.Clone(k.Assumed)
+	k.Unknown = slices.Clone(k.Unknown)
+	return k
+}
+
+// calibrate flags confidence that doesn't match the stated knowledge: high
+// confidence despite mostly unknowns, or low confidence with nothing unknown.
+func (k *KnowledgeAssessment) calibrate() {
+	switch {
+	case k.Confidence >= 0.7 && len(k.Unknown) > len(k.Known):
+		k.Calibration = "possibly overconfident: more unknowns than knowns"
+	case k.Confidence < 0.3 && len(k.Unknown) == 0 && len(k.Known) > 0:
+		k.Calibration = "possibly underconfident: nothing is listed as unknown"
+	default:
+		k.Calibration = ""
+	}
+}
+
+// lowConfidenceTopics lists the assessments below the confidence threshold.
+func (s *SequentialThinkingServer) lowConfidenceTopics() []KnowledgeAssessment {
+	var low []KnowledgeAssessment
+	for _, k := range s.assessments {
+		if k.Confidence < lowConfidence {
+			low = append(low, k)
+		}
+	}
+	return low
+}
+
+// appendNew adds the non-empty items not already in list.
+func appendNew(list []string, items []string) []string {
+	for _, item := range items {
+		if item != "" && !slices.Contains(list, item) {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+func (s *SequentialThinkingServer) assessKnowledge(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	topic, err := request.RequireString("topic")
+	if err != nil || topic == "" {
+		return s.fail(ctx, request, invalid("topic", "non-empty string", request.GetArguments()["topic"], "must be a non-empty string"))
+	}
+	confidence, err := request.RequireFloat("confidence")
+	if err != nil || confidence < 0 || confidence > 1 {
+		return s.fail(ctx, request, invalid("confidence", "number between 0 and 1", request.GetArguments()["confidence"], "must be a number between 0 and 1"))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n, err := s.optionalThought(request, "thoughtNumber")
+	if err != nil {
+		return s.fail(ctx, request, err)
+	}
+	if n == 0 && len(s.thoughtHistory) > 0 {
+		n = s.thoughtHistory[len(s.thoughtHistory)-1].ThoughtNumber
+	}
+
+	i := slices.IndexFunc(s.assessments, func(k KnowledgeAssessment) bool { return k.Topic == topic })
+	if i < 0 {
+		s.assessments = append(s.assessments, KnowledgeAssessment{
+			Topic:   topic,
+			Known:   make([]string, 0),
+			Assumed: make([]string, 0),
+			Unknown: make([]string, 0),
+		})
+		i = len(s.assessments) - 1
+	}
+	k := s.assessments[i].clone()
+	known := request.GetStringSlice("known", nil)
+	k.Known = appendNew(k.Known, known)
+	k.Assumed = appendNew(k.Assumed, request.GetStringSlice("assumed", nil))
+	// Whatever became known is no longer unknown.
+	k.Unknown = slices.DeleteFunc(appendNew(k.Unknown, request.GetStringSlice("unknown", nil)),
+		func(u string) bool { return slices.Contains(known, u) })
+	k.Confidence = confidence
+	k.ThoughtNumber = n
+	k.calibrate()
+	s.assessments[i] = k
+
+	if !s.disableThoughtLogging {
+		s.logEvent(s.theme.Record, fmt.Sprintf("🪞 %s: confidence %.2f (%d known, %d assumed, %d unknown)",
+			topic, confidence, len(k.Known), len(k.Assumed), len(k.Unknown)), "")
+	}
+
+	return s.respond(ctx, request, map[string]any{
+		"assessment": k,
+	})
+}
@@ -0,0 +1,152 @@
+package thinking
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// MentalModel is a named reasoning technique with the steps to apply it.
+type MentalModel struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Steps       []string `json:"steps"`
+}
+
+var mentalModels = map[string]MentalModel{
+	"first_principles": {
+		Name:        "first_principles",
+		Description: "Break the problem down to fundamental truths and rebuild the solution from them.",
+		Steps: []string{
+			"State the problem and the conventional answer",
+			"List the assumptions behind it",
+			"Keep only what is known to be true",
+			"Build a solution from those truths alone",
+		},
+	},
+	"inversion": {
+		Name:        "inversion",
+		Description: "Think about what would guarantee failure, then avoid it.",
+		Steps: []string{
+			"State the goal",
+			"List what would make it fail",
+			"Check the current plan against each failure mode",
+			"Change the plan to avoid them",
+		},
+	},
+	"occams_razor": {
+		Name:        "occams_razor",
+		Description: "Prefer the explanation that needs the fewest assumptions.",
+		Steps: []string{
+			"List the competing explanations",
+			"Count the assumptions each one needs",
+			"Start from the simplest one that fits all the evidence",
+		},
+	},
+	"second_order_thinking": {
+		Name:        "second_order_thinking",
+		Description: "Follow the consequences of a choice past the immediate effect.",
+		Steps: []string{
+			"State the choice and its immediate effect",
+			"Ask what happens as a result of that effect",
+			"Repeat over longer time frames",
+			"Weigh the choice on the whole chain of effects",
+		},
+	},
+	"opportunity_cost": {
+		Name:        "opportunity_cost",
+		Description: "Judge an option against the best alternative it rules out.",
+		Steps: []string{
+			"Name the option",
+			"Name the best alternative it excludes",
+			"Compare what each gains and gives up",
+		},
+	},
+	"pareto": {
+		Name:        "pareto",
+		Description: "Find the few causes that account for most of the effect.",
+		Steps: []string{
+			"List the contributing factors",
+			"Estimate each factor's share of the effect",
+			"Focus on the few that dominate",
+		},
+	},
+	"rubber_duck": {
+		Name:        "rubber_duck",
+		Description: "Explain the problem step by step as if to someone new to it.",
+		Steps: []string{
+			"Explain what should happen",
+			"Explain what actually happens, one step at a time",
+			"Note where the explanation falters",
+		},
+	},
+}
+
+// modelNames lists the catalog in sorted order.
+func modelNames() []string {
+	names := make([]string, 0, len(mentalModels))
+	for name := range mentalModels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ModelApplication records a mental model applied to some thoughts.
+type ModelApplication struct {
+	ID         string `json:"id"`
+	Model      string `json:"model"`
+	Thoughts   []int  `json:"thoughts"`
+	Problem    string `json:"problem,omitempty"`
+	Conclusion string `json:"conclusion,omitempty"`
+}
+
+func (a ModelApplication) clone() ModelApplication {
+	a.Thoughts = slices.Clone(a.Thoughts)
+	return a
+}
+
+func (s *SequentialThinkingServer) applyMentalModel(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	name, err := request.RequireString("model")
+	if err != nil || name == "" {
+		return s.fail(ctx, request, invalid("model", "non-empty string", request.GetArguments()["model"], "must be a non-empty string"))
+	}
+	model, ok := mentalModels[name]
+	if !ok {
+		return s.fail(ctx, request, invalid("model", "one of "+strings.Join(modelNames(), ", "), name, "unknown model %q, expected one of %s", name, strings.Join(modelNames(), ", ")))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	thoughts, err := s.thoughtNumbers(request, "thoughtNumbers")
+	if err != nil {
+		return s.fail(ctx, request, err)
+	}
+	if len(thoughts) == 0 && len(s.thoughtHistory) > 0 {
+		thoughts = []int{s.thoughtHistory[len(s.thoughtHistory)-1].ThoughtNumber}
+	}
+
+	s.modelSeq++
+	a := ModelApplication{
+		ID:         fmt.Sprintf("M%d", s.modelSeq),
+		Model:      name,
+		Thoughts:   append(make([]int, 0, len(thoughts)), thoughts...),
+		Problem:    request.GetString("problem", ""),
+		Conclusion: request.GetString("conclusion", ""),
+	}
+	s.modelApplications = append(s.modelApplications, a)
+
+	if !s.disableThoughtLogging {
+		s.logEvent(s.theme.Record, fmt.Sprintf("🧠 Applied %s to thoughts %s", name, joinInts(a.Thoughts)), "")
+	}
+
+	return s.respond(ctx, request, map[string]any{
+		"application": a,
+		"model":       model,
+	})
+}
@@ -0,0 +1,164 @@
+package thinking
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// obsidianSessionNote is the name of the note that indexes an Obsidian export.
+const obsidianSessionNote = "Session"
+
+// obsidianName strips the characters Obsidian doesn't allow in note names
+// and links.
+var obsidianName = strings.NewReplacer("[", "", "]", "", "|", "-", "#", "", "^", "", ":", "-", "/", "-", `\`, "-")
+
+// obsidianNotes names a note for every thought: "Thought 3" on the main line
+// and "Thought 3 (branch)" on a branch, with a suffix for repeated numbers.
+func (s *SequentialThinkingServer) obsidianNotes() []string {
+	names := make([]string, len(s.thoughtHistory))
+	used := map[string]bool{obsidianSessionNote: true}
+	for i := range s.thoughtHistory {
+		t := &s.thoughtHistory[i]
+		name := fmt.Sprintf("Thought %d", t.ThoughtNumber)
+		if line := branchOf(t); line != "" {
+			name += " (" + obsidianName.Replace(line) + ")"
+		}
+		base := name
+		for n := 2; used[name]; n++ {
+			name = fmt.Sprintf("%s %d", base, n)
+		}
+		used[name] = true
+		names[i] = name
+	}
+	return names
+}
+
+// edgeLabels describe graph edges from the child's and the parent's side.
+var edgeLabels = map[string][2]string{
+	"sequence": {"Previous", "Next"},
+	"branch":   {"Branched from", "Branches"},
+	"revision": {"Revises", "Revised by"},
+	"merge":    {"Merges", "Merged into"},
+}
+
+// ExportObsidian writes the session to dir as a folder of Obsidian notes: one
+// per thought, with YAML frontmatter and wikilinks along sequences,
+// revisions, branches and merges, plus a session note linking them all. It
+// returns the names of the notes written.
+func (s *SequentialThinkingServer) ExportObsidian(dir string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	names := s.obsidianNotes()
+	children, parents := s.thoughtGraph()
+	files := make(map[string]string)
+
+	for i := range s.thoughtHistory {
+		t := &s.thoughtHistory[i]
+		var b strings.Builder
+		b.WriteString("---\n")
+		fmt.Fprintf(&b, "session: %s\n", strconv.Quote(s.sessionID))
+		if t.Id != "" {
+			fmt.Fprintf(&b, "id: %s\n", t.Id)
+		}
+		fmt.Fprintf(&b, "thought: %d\ntotal: %d\ntype: %s\n", t.ThoughtNumber, t.TotalThoughts, thoughtKind(t))
+		if line := branchOf(t); line != "" {
+			fmt.Fprintf(&b, "branch: %s\n", strconv.Quote(line))
+		}
+		if t.Author != "" {
+			fmt.Fprintf(&b, "author: %s\n", strconv.Quote(t.Author))
+		}
+		if t.Status != "" {
+			fmt.Fprintf(&b, "status: %s\n", t.Status)
+		}
+		if t.ThoughtType != nil {
+			fmt.Fprintf(&b, "thoughtType: %s\n", *t.ThoughtType)
+		}
+		if t.Metadata != nil {
+			fmt.Fprintf(&b, "metadata: %s\n", metadataJSON(t))
+		}
+		if len(t.Citations) > 0 {
+			b.WriteString("citations:\n")
+			for _, ref := range t.Citations {
+				fmt.Fprintf(&b, "  - %s\n", strconv.Quote(ref))
+			}
+		}
+		if len(t.Uncertainties) > 0 {
+			b.WriteString("uncertainties:\n")
+			for _, text := range t.Uncertainties {
+				fmt.Fprintf(&b, "  - %s\n", strconv.Quote(text))
+			}
+		}
+		if len(t.Tags) > 0 {
+			b.WriteString("tags:\n")
+			for _, tag := range t.Tags {
+				fmt.Fprintf(&b, "  - %s\n", strconv.Quote(tag))
+			}
+		}
+		b.WriteString("---\n\n")
+		fmt.Fprintf(&b, "%s\n", t.Thought)
+
+		links := make(map[string][]string)
+		var order []string
+		link := func(label string, j int) {
+			if links[label] == nil {
+				order = append(order, label)
+			}
+			links[label] = append(links[label], "[["+names[j]+"]]")
+		}
+		for _, e := range parents[i] {
+			link(edgeLabels[e.kind][0], e.from)
+		}
+		for _, e := range children[i] {
+			link(edgeLabels[e.kind][1], e.to)
+		}
+		if len(order) > 0 {
+			b.WriteString("\n")
+			for _, label := range order {
+				fmt.Fprintf(&b, "- %s: %s\n", label, strings.Join(links[label], ", "))
+			}
+		}
+		fmt.Fprintf(&b, "\nPart of [[%s]].\n", obsidianSessionNote)
+		files[names[i]] = b.String()
+	}
+
+	var index strings.Builder
+	fmt.Fprintf(&index, "---\nsession: %s\nthoughts: %d\n---\n\n# Thinking session\n\n## Main line\n\n",
+		strconv.Quote(s.sessionID), len(s.thoughtHistory))
+	for i := range s.thoughtHistory {
+		if branchOf(&s.thoughtHistory[i]) == "" {
+			fmt.Fprintf(&index, "- [[%s]]\n", names[i])
+		}
+	}
+	for _, id := range s.allBranches() {
+		fmt.Fprintf(&index, "\n## Branch %s\n\n", id)
+		if description := s.branches[id].Description; description != "" {
+			fmt.Fprintf(&index, "%s\n\n", description)
+		}
+		if reason, ok := s.abandonReason(id); ok {
+			fmt.Fprintf(&index, "Abandoned: %s\n\n", reason)
+		}
+		for i := range s.thoughtHistory {
+			if branchOf(&s.thoughtHistory[i]) == id {
+				fmt.Fprintf(&index, "- [[%s]]\n", names[i])
+			}
+		}
+	}
+	if final := s.conclusion(); final != nil {
+		fmt.Fprintf(&index, "\n## Final answer\n\n%s\n", final.Thought)
+	}
+	files[obsidianSessionNote] = index.String()
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name+".md"), []byte(content), 0o644); err != nil {
+			return nil, err
+		}
+	}
+	return append([]string{obsidianSessionNote}, names...), nil
+}
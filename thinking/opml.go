@@ -0,0 +1,70 @@
+package thinking
+
+import (
+	"encoding/xml"
+	"fmt"
+)
+
+type opmlOutline struct {
+	Text     string        `xml:"text,attr"`
+	Note     string        `xml:"_note,attr,omitempty"`
+	Outlines []opmlOutline `xml:"outline"`
+}
+
+type opmlDocument struct {
+	XMLName xml.Name      `xml:"opml"`
+	Version string        `xml:"version,attr"`
+	Title   string        `xml:"head>title"`
+	Body    []opmlOutline `xml:"body>outline"`
+}
+
+// opmlThought outlines a thought, noting its type unless it's a plain one.
+func opmlThought(t *ThoughtData) opmlOutline {
+	outline := opmlOutline{Text: fmt.Sprintf("Thought %d: %s", t.ThoughtNumber, t.Thought)}
+	if kind := thoughtKind(t); kind != "thought" {
+		outline.Note = kind
+	}
+	return outline
+}
+
+// renderOPML outlines the main line with each branch nested under the
+// thought it forks from, for outliners such as Workflowy and Dynalist.
+func (s *SequentialThinkingServer) renderOPML() (string, error) {
+	doc := opmlDocument{Version: "2.0", Title: "Thinking session"}
+	forked := make(map[string]bool)
+	for i := range s.thoughtHistory {
+		t := &s.thoughtHistory[i]
+		if branchOf(t) != "" {
+			continue
+		}
+		outline := opmlThought(t)
+		for _, id := range s.allBranches() {
+			if forked[id] || s.branchOrigin(id) != t.ThoughtNumber {
+				continue
+			}
+			forked[id] = true
+			branch := opmlOutline{Text: "Branch " + id, Note: s.branches[id].Description}
+			if into, ok := s.mergedInto(id); ok {
+				branch.Note = "merged into " + describeScope(into)
+			}
+			if reason, ok := s.abandonReason(id); ok {
+				branch.Note = "abandoned: " + reason
+			}
+			thoughts := s.branches[id].Thoughts
+			for j := range thoughts {
+				branch.Outlines = append(branch.Outlines, opmlThought(&thoughts[j]))
+			}
+			outline.Outlines = append(outline.Outlines, branch)
+		}
+		doc.Body = append(doc.Body, outline)
+	}
+	if final := s.conclusion(); final != nil {
+		doc.Body = append(doc.Body, opmlOutline{Text: "Final answer: " + final.Thought})
+	}
+
+	out, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return xml.Header + string(out) + "\n", nil
+}
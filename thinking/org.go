@@ -0,0 +1,170 @@
+package thinking
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// orgText indents lines that Org would otherwise read as headings.
+func orgText(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, "*") {
+			lines[i] = " " + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// writeOrgProperties writes a property drawer, skipping empty values, or
+// nothing if all values are empty.
+func writeOrgProperties(b *strings.Builder, properties [][2]string) {
+	if !slices.ContainsFunc(properties, func(p [2]string) bool { return p[1] != "" }) {
+		return
+	}
+	b.WriteString(":PROPERTIES:\n")
+	for _, p := range properties {
+		if p[1] != "" {
+			fmt.Fprintf(b, ":%s: %s\n", p[0], p[1])
+		}
+	}
+	b.WriteString(":END:\n")
+}
+
+func (s *SequentialThinkingServer) writeOrgThought(b *strings.Builder, t *ThoughtData) {
+	fmt.Fprintf(b, "** Thought %d/%d\n", t.ThoughtNumber, t.TotalThoughts)
+	properties := [][2]string{
+		{"ID", t.Id},
+		{"THOUGHT_NUMBER", fmt.Sprint(t.ThoughtNumber)},
+		{"TYPE", thoughtKind(t)},
+		{"STATUS", t.Status},
+		{"AUTHOR", t.Author},
+		{"CITATIONS", strings.Join(t.Citations, " ")},
+		{"TAGS", strings.Join(t.Tags, ", ")},
+	}
+	if t.ThoughtType != nil {
+		properties = append(properties, [2]string{"THOUGHT_TYPE", *t.ThoughtType})
+	}
+	if len(t.Uncertainties) > 0 {
+		properties = append(properties, [2]string{"UNCERTAINTIES", strings.Join(t.Uncertainties, "; ")})
+	}
+	if t.Metadata != nil {
+		properties = append(properties, [2]string{"METADATA", metadataJSON(t)})
+	}
+	if t.ParentThought != nil {
+		properties = append(properties, [2]string{"PARENT", fmt.Sprint(*t.ParentThought)})
+	}
+	if len(t.DependsOn) > 0 {
+		properties = append(properties, [2]string{"DEPENDS_ON", joinInts(t.DependsOn)})
+	}
+	if t.RevisesThought != nil {
+		properties = append(properties, [2]string{"REVISES", fmt.Sprint(*t.RevisesThought)})
+		if t.RevisesBranchId != nil {
+			properties = append(properties, [2]string{"REVISES_BRANCH", *t.RevisesBranchId})
+		}
+	}
+	if t.MergedBranchId != nil {
+		properties = append(properties,
+			[2]string{"MERGES", *t.MergedBranchId},
+			[2]string{"MERGED_THOUGHTS", joinInts(t.MergedThoughts)})
+	}
+	if revisions := s.revisionsOf(branchOf(t), t.ThoughtNumber); len(revisions) > 0 {
+		numbers := make([]int, len(revisions))
+		for i, r := range revisions {
+			numbers[i] = r.ThoughtNumber
+		}
+		properties = append(properties, [2]string{"REVISED_BY", joinInts(numbers)})
+	}
+	writeOrgProperties(b, properties)
+	fmt.Fprintf(b, "%s\n", orgText(t.Thought))
+}
+
+// renderOrg renders the session as an Org document: a heading per line with
+// a subheading per thought, property drawers for their metadata, and
+// questions as TODO items until they're answered.
+func (s *SequentialThinkingServer) renderOrg() string {
+	var b strings.Builder
+	b.WriteString("#+TITLE: Thinking session\n")
+	fmt.Fprintf(&b, "#+PROPERTY: SESSION_ID %s\n\n", s.sessionID)
+
+	b.WriteString("* Main line\n")
+	for i := range s.thoughtHistory {
+		if t := &s.thoughtHistory[i]; branchOf(t) == "" {
+			s.writeOrgThought(&b, t)
+		}
+	}
+
+	for _, id := range s.allBranches() {
+		branch := s.branches[id]
+		fmt.Fprintf(&b, "* Branch %s\n", id)
+		mergedInto, merged := s.mergedInto(id)
+		if merged && mergedInto == "" {
+			mergedInto = "main"
+		}
+		abandoned, _ := s.abandonReason(id)
+		created := ""
+		if branch.CreatedAt != nil {
+			created = branch.CreatedAt.UTC().Format(time.RFC3339)
+		}
+		writeOrgProperties(&b, [][2]string{
+			{"BRANCH_ID", id},
+			{"BRANCH_FROM", fmt.Sprint(branch.BranchFromThought)},
+			{"STATUS", string(branch.Status)},
+			{"CREATED", created},
+			{"DESCRIPTION", branch.Description},
+			{"MERGED_INTO", mergedInto},
+			{"ABANDONED", abandoned},
+		})
+		for i := range branch.Thoughts {
+			s.writeOrgThought(&b, &branch.Thoughts[i])
+		}
+	}
+
+	if final := s.conclusion(); final != nil {
+		b.WriteString("* Final answer\n")
+		writeOrgProperties(&b, [][2]string{{"THOUGHT_NUMBER", fmt.Sprint(final.ThoughtNumber)}})
+		fmt.Fprintf(&b, "%s\n", orgText(final.Thought))
+	}
+
+	if len(s.questions) > 0 {
+		b.WriteString("* Questions\n")
+		for _, q := range s.questions {
+			keyword := "DONE"
+			if q.open() {
+				keyword = "TODO"
+			}
+			fmt.Fprintf(&b, "** %s %s %s\n", keyword, q.ID, orgText(q.Question))
+			writeOrgProperties(&b, [][2]string{
+				{"CATEGORY", q.Category},
+				{"RAISED_IN", optionalInt(q.RaisedIn)},
+				{"ANSWERED_IN", optionalInt(q.AnsweredIn)},
+			})
+			if !q.open() {
+				fmt.Fprintf(&b, "%s\n", orgText(q.Answer))
+			}
+		}
+	}
+	if len(s.hypotheses) > 0 {
+		b.WriteString("* Hypotheses\n")
+		for _, h := range s.hypotheses {
+			fmt.Fprintf(&b, "- %s (%s): %s\n", h.ID, h.Status, h.Statement)
+		}
+	}
+	if len(s.assumptions) > 0 {
+		b.WriteString("* Assumptions\n")
+		for _, a := range s.assumptions {
+			fmt.Fprintf(&b, "- %s (%s): %s\n", a.ID, a.Status, a.Statement)
+		}
+	}
+	return b.String()
+}
+
+// optionalInt formats n, leaving zero (unset) empty.
+func optionalInt(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return fmt.Sprint(n)
+}
@@ -0,0 +1,59 @@
+package thinking
+
+import "fmt"
+
+// resolveParent pins parentThought to exactly one earlier thought the
+// thought can follow from: one on its own line or, inside a branch, a
+// main-line thought up to the branching point. It records the parent's ID,
+// which the graph follows instead of the order of the line.
+func (s *SequentialThinkingServer) resolveParent(data *ThoughtData) error {
+	if data.ParentThought == nil {
+		return nil
+	}
+	n := *data.ParentThought
+	if n >= data.ThoughtNumber {
+		return invalid("parentThought", fmt.Sprintf("integer below %d", data.ThoughtNumber), n,
+			"thought %d cannot follow from thought %d; a parent comes before its children", data.ThoughtNumber, n)
+	}
+	parent, err := s.visibleThought(data, "parentThought", n)
+	if err != nil {
+		return err
+	}
+	data.ParentId = parent.Id
+	return nil
+}
+
+// visibleThought finds the one recorded thought numbered n that data can
+// refer to by number alone: on its own line or, inside a branch, on the main
+// line up to the branching point. field names the argument for errors.
+func (s *SequentialThinkingServer) visibleThought(data *ThoughtData, field string, n int) (*ThoughtData, error) {
+	line := branchOf(data)
+	origin := 0
+	if line != "" {
+		origin = *data.BranchFromThought
+	}
+	scope, matches := s.lookup(line, origin, n)
+	switch {
+	case len(matches) > 1:
+		return nil, invalid(field, "unambiguous thought number", n,
+			"thought %d is ambiguous, %s has %d thoughts with that number", n, describeScope(scope), len(matches))
+	case len(matches) == 0:
+		return nil, invalid(field, "recorded thought number", n,
+			"thought %d does not exist in %s", n, describeScope(line))
+	}
+	return &matches[0], nil
+}
+
+// parentIndex returns the history index of the parent a thought names, or -1
+// if it names none.
+func (s *SequentialThinkingServer) parentIndex(t *ThoughtData) int {
+	if t.ParentId == "" {
+		return -1
+	}
+	for i := range s.thoughtHistory {
+		if s.thoughtHistory[i].Id == t.ParentId {
+			return i
+		}
+	}
+	return -1
+}
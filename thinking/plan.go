@@ -0,0 +1,58 @@
+package thinking
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// planTags mark thoughts that describe something to do.
+var planTags = []string{"action", "next-step", "next_step", "todo"}
+
+// PlanStep is one entry of the plan extracted by extract_plan.
+type PlanStep struct {
+	Step          int      `json:"step"`
+	Action        string   `json:"action"`
+	ThoughtNumber int      `json:"thoughtNumber"`
+	BranchId      string   `json:"branchId,omitempty"`
+	Tags          []string `json:"tags,omitempty"`
+}
+
+func isPlanStep(t *ThoughtData) bool {
+	return slices.ContainsFunc(t.Tags, func(tag string) bool {
+		return slices.Contains(planTags, strings.ToLower(tag))
+	})
+}
+
+// plan lists the live action thoughts in history order.
+func (s *SequentialThinkingServer) plan() []PlanStep {
+	live := s.liveThoughts()
+	steps := make([]PlanStep, 0)
+	for i := range s.thoughtHistory {
+		t := &s.thoughtHistory[i]
+		if !live(t) || !isPlanStep(t) {
+			continue
+		}
+		steps = append(steps, PlanStep{
+			Step:          len(steps) + 1,
+			Action:        t.Thought,
+			ThoughtNumber: t.ThoughtNumber,
+			BranchId:      branchOf(t),
+			Tags:          t.Tags,
+		})
+	}
+	return steps
+}
+
+func (s *SequentialThinkingServer) extractPlan(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	steps := s.plan()
+	return s.respond(ctx, request, map[string]any{
+		"steps":     steps,
+		"stepCount": len(steps),
+	})
+}
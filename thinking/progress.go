@@ -0,0 +1,28 @@
+package thinking
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// reportProgress sends a progress notification for a recorded thought if the
+// request asked for progress, so hosts can render how far the chain has come.
+// A concluding thought reports the chain as complete.
+func (s *SequentialThinkingServer) reportProgress(ctx context.Context, request mcp.CallToolRequest, data *ThoughtData) {
+	if s.srv == nil || request.Params.Meta == nil || request.Params.Meta.ProgressToken == nil {
+		return
+	}
+	progress := data.ThoughtNumber
+	if !data.NextThoughtNeeded {
+		progress = data.TotalThoughts
+	}
+	// Progress is best effort: a client that went away just misses it.
+	_ = s.srv.SendNotificationToClient(ctx, "notifications/progress", map[string]any{
+		"progressToken": request.Params.Meta.ProgressToken,
+		"progress":      progress,
+		"total":         data.TotalThoughts,
+		"message":       fmt.Sprintf("Thought %d of %d", data.ThoughtNumber, data.TotalThoughts),
+	})
+}
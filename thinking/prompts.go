@@ -0,0 +1,181 @@
+package thinking
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// registerPrompts adds prompts that bootstrap common thinking workflows,
+// filled in from the current session.
+func (s *SequentialThinkingServer) registerPrompts(srv *server.MCPServer) {
+	srv.AddPrompt(mcp.NewPrompt("start-sequential-analysis",
+		mcp.WithPromptDescription("Start working through a problem with the sequentialthinking tool"),
+		mcp.WithArgument("problem",
+			mcp.RequiredArgument(),
+			mcp.ArgumentDescription("The problem to analyze"),
+		),
+		mcp.WithArgument("estimatedThoughts",
+			mcp.ArgumentDescription("Initial estimate of the thoughts needed (defaults to 5)"),
+		),
+	), s.startAnalysisPrompt)
+	srv.AddPrompt(mcp.NewPrompt("review-and-revise",
+		mcp.WithPromptDescription("Review the chain so far and revise the weak thoughts"),
+		mcp.WithArgument("focus",
+			mcp.ArgumentDescription("Aspect to pay particular attention to"),
+		),
+		mcp.WithArgument("thoughtNumber",
+			mcp.ArgumentDescription("Main-line thought to start the revision from"),
+		),
+	), s.reviewPrompt)
+	srv.AddPrompt(mcp.NewPrompt("branch-comparison",
+		mcp.WithPromptDescription("Compare the open branches and settle on one"),
+		mcp.WithArgument("branches",
+			mcp.ArgumentDescription("Comma-separated branch IDs to compare (defaults to all open branches)"),
+		),
+	), s.branchComparisonPrompt)
+}
+
+func promptResult(description, text string) *mcp.GetPromptResult {
+	return mcp.NewGetPromptResult(description, []mcp.PromptMessage{
+		mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(text)),
+	})
+}
+
+func (s *SequentialThinkingServer) startAnalysisPrompt(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	problem := request.Params.Arguments["problem"]
+	if problem == "" {
+		return nil, fmt.Errorf("invalid problem: must be a non-empty string")
+	}
+	estimate := 5
+	if v := request.Params.Arguments["estimatedThoughts"]; v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("invalid estimatedThoughts: must be a positive number")
+		}
+		estimate = n
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Work through the following problem step by step with the sequentialthinking tool.\n\nProblem: %s\n\n", problem)
+	if n := len(s.thoughtHistory); n > 0 {
+		latest := s.thoughtHistory[n-1]
+		fmt.Fprintf(&b, "The session already holds %d thoughts (latest: thought %d of %d). "+
+			"Call clear_history first to start fresh, or continue from thought %d.\n\n",
+			n, latest.ThoughtNumber, latest.TotalThoughts, latest.ThoughtNumber+1)
+	} else {
+		fmt.Fprintf(&b, "Start with thoughtNumber 1 and an estimated totalThoughts of %d.\n\n", estimate)
+	}
+	b.WriteString(`- Record one step of reasoning per call and set nextThoughtNeeded to true until you are done.
+- Adjust totalThoughts whenever the estimate turns out wrong.
+- When a step turns out wrong, revise it with isRevision and revisesThought instead of ignoring it.
+- To explore an alternative, branch with branchFromThought and a branchId.
+- Register hypotheses and assumptions with record_hypothesis and record_assumption, and verify them before concluding.
+- Finish with nextThoughtNeeded set to false and call finalize_answer.`)
+
+	return promptResult("Sequential analysis of a problem", b.String()), nil
+}
+
+func (s *SequentialThinkingServer) reviewPrompt(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var b strings.Builder
+	b.WriteString("Review the reasoning recorded so far and revise whatever doesn't hold up.\n")
+	if focus := request.Params.Arguments["focus"]; focus != "" {
+		fmt.Fprintf(&b, "Pay particular attention to: %s\n", focus)
+	}
+	if v := request.Params.Arguments["thoughtNumber"]; v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid thoughtNumber: must be a number")
+		}
+		_, t, err := s.findThought("", n)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&b, "Start with thought %d, revising it with isRevision set to true and revisesThought set to %d:\n\n%s\n",
+			n, n, t.Thought)
+	}
+
+	live := s.liveThoughts()
+	b.WriteString("\nCurrent thoughts:\n")
+	for i := range s.thoughtHistory {
+		t := &s.thoughtHistory[i]
+		if !live(t) {
+			continue
+		}
+		scope := ""
+		if line := branchOf(t); line != "" {
+			scope = fmt.Sprintf(" [branch %s]", line)
+		}
+		fmt.Fprintf(&b, "- Thought %d%s: %s\n", t.ThoughtNumber, scope, excerpt(t.Thought))
+	}
+	if len(s.thoughtHistory) == 0 {
+		b.WriteString("- (none yet)\n")
+	}
+
+	if weaknesses := s.critique(); len(weaknesses) > 0 {
+		b.WriteString("\nIssues found by critique_chain:\n")
+		for _, w := range weaknesses {
+			fmt.Fprintf(&b, "- [%s] %s\n", w.Severity, w.Message)
+		}
+	}
+
+	b.WriteString("\nFor each thought that is wrong or incomplete, call sequentialthinking with isRevision set to true " +
+		"and revisesThought set to its number. Then continue the chain or conclude it.")
+
+	return promptResult("Review and revise the current chain", b.String()), nil
+}
+
+func (s *SequentialThinkingServer) branchComparisonPrompt(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	names := s.branchNames()
+	if v := request.Params.Arguments["branches"]; v != "" {
+		names = nil
+		for _, id := range strings.Split(v, ",") {
+			id = strings.TrimSpace(id)
+			if s.branches[id] == nil {
+				return nil, fmt.Errorf("invalid branches: unknown branch %q", id)
+			}
+			names = append(names, id)
+		}
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no branches to compare")
+	}
+
+	var b strings.Builder
+	b.WriteString("Compare the following branches of reasoning and decide which one to pursue.\n")
+	for _, id := range names {
+		branch := s.branches[id]
+		thoughts := branch.Thoughts
+		fmt.Fprintf(&b, "\nBranch %s (from thought %d, %d thoughts):\n", id, branch.BranchFromThought, len(thoughts))
+		if branch.Description != "" {
+			fmt.Fprintf(&b, "%s\n", branch.Description)
+		}
+		for _, n := range conclusions(thoughts) {
+			for _, t := range thoughts {
+				if t.ThoughtNumber == n {
+					fmt.Fprintf(&b, "- Thought %d: %s\n", n, excerpt(t.Thought))
+				}
+			}
+		}
+		if into, ok := s.mergedInto(id); ok {
+			fmt.Fprintf(&b, "- already merged into %s\n", describeScope(into))
+		}
+	}
+	b.WriteString("\nWeigh the branches against each other (decision_matrix can help), then merge the chosen one " +
+		"with merge_branches and drop the others with abandon_branch, giving a reason.")
+
+	return promptResult("Compare branches of reasoning", b.String()), nil
+}
@@ -0,0 +1,122 @@
+package thinking
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func (s *SequentialThinkingServer) getBranch(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	branchId, err := request.RequireString("branchId")
+	if err != nil || branchId == "" {
+		return s.fail(ctx, request, invalid("branchId", "non-empty string", request.GetArguments()["branchId"], "must be a non-empty string"))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	branch := s.branches[branchId]
+	if branch == nil {
+		return s.fail(ctx, request, invalid("branchId", "recorded branch ID", branchId, "unknown branch %q", branchId))
+	}
+
+	origin := branch.BranchFromThought
+	result := map[string]any{
+		"branchId":          branchId,
+		"branchFromThought": origin,
+		"status":            branch.Status,
+		"thoughts":          branch.Thoughts,
+		"thoughtCount":      len(branch.Thoughts),
+	}
+	if branch.CreatedAt != nil {
+		result["createdAt"] = branch.CreatedAt
+	}
+	if branch.Description != "" {
+		result["description"] = branch.Description
+	}
+	if point := s.thoughtsInScope("", origin); len(point) == 1 {
+		result["branchPoint"] = point[0]
+	}
+	if into, ok := s.mergedInto(branchId); ok {
+		result["mergedInto"] = into
+	}
+	if reason, ok := s.abandonReason(branchId); ok {
+		result["abandoned"] = true
+		result["abandonReason"] = reason
+	}
+
+	return s.respond(ctx, request, result)
+}
+
+// findThought resolves a thought number as seen from a line, returning the
+// line it lives on.
+func (s *SequentialThinkingServer) findThought(branchId string, n int) (string, *ThoughtData, error) {
+	return s.findThoughtAt("thoughtNumber", "branchId", branchId, n)
+}
+
+// findThoughtAt is findThought for a thought named by the arguments
+// numberKey and branchKey.
+func (s *SequentialThinkingServer) findThoughtAt(numberKey, branchKey, branchId string, n int) (string, *ThoughtData, error) {
+	if branchId != "" && s.branches[branchId] == nil {
+		return "", nil, invalid(branchKey, "recorded branch ID", branchId, "unknown branch %q", branchId)
+	}
+
+	line, matches := s.lookup(branchId, s.branchOrigin(branchId), n)
+	switch {
+	case len(matches) == 0:
+		return "", nil, invalid(numberKey, "recorded thought number", n, "thought %d does not exist in %s", n, describeScope(branchId))
+	case len(matches) > 1:
+		return "", nil, invalid(numberKey, "unambiguous thought number", n,
+			"thought %d is ambiguous, %s has %d thoughts with that number", n, describeScope(line), len(matches))
+	}
+	return line, &matches[0], nil
+}
+
+// targetThought finds the thought a request names, by thoughtId or else by
+// thoughtNumber and branchId, and returns it with its line.
+func (s *SequentialThinkingServer) targetThought(request mcp.CallToolRequest) (string, *ThoughtData, error) {
+	if id := request.GetString("thoughtId", ""); id != "" {
+		t := s.thoughtByID(id)
+		if t == nil {
+			return "", nil, invalid("thoughtId", "recorded thought ID", id, "thought %s does not exist", id)
+		}
+		return branchOf(t), t, nil
+	}
+	n, err := request.RequireInt("thoughtNumber")
+	if err != nil {
+		return "", nil, invalid("thoughtNumber", "integer", request.GetArguments()["thoughtNumber"], "must be a number, unless thoughtId is set")
+	}
+	return s.findThought(request.GetString("branchId", ""), n)
+}
+
+func (s *SequentialThinkingServer) getThought(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	line, thought, err := s.targetThought(request)
+	if err != nil {
+		return s.fail(ctx, request, err)
+	}
+	chain := s.versionsOf(s.historyIndex()[thought.Id])
+	versions := make([]ThoughtData, len(chain))
+	for j, i := range chain {
+		versions[j] = s.thoughtHistory[i]
+	}
+	version := request.GetInt("version", len(versions))
+	if version < 1 || version > len(versions) {
+		return s.fail(ctx, request, invalid("version", fmt.Sprintf("integer from 1 to %d", len(versions)), version, "thought %d has versions 1 to %d", thought.ThoughtNumber, len(versions)))
+	}
+
+	result := map[string]any{
+		"thought":       versions[version-1],
+		"version":       version,
+		"latestVersion": len(versions),
+		"versions":      versions,
+		"revisions":     s.revisionsOf(line, thought.ThoughtNumber),
+	}
+	if line != "" {
+		result["branchId"] = line
+	}
+	return s.respond(ctx, request, result)
+}
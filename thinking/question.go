@@ -0,0 +1,117 @@
+package thinking
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Question is a loose end raised during the chain. Open questions are
+// attached to every tool result until they are answered.
+type Question struct {
+	ID         string `json:"id"`
+	Question   string `json:"question"`
+	Category   string `json:"category,omitempty"`
+	RaisedIn   int    `json:"raisedIn,omitempty"`
+	Answer     string `json:"answer,omitempty"`
+	AnsweredIn int    `json:"answeredIn,omitempty"`
+}
+
+func (q Question) open() bool {
+	return q.Answer == ""
+}
+
+// openQuestions returns the questions that haven't been answered yet.
+func (s *SequentialThinkingServer) openQuestions() []Question {
+	var open []Question
+	for _, q := range s.questions {
+		if q.open() {
+			open = append(open, q)
+		}
+	}
+	return open
+}
+
+// optionalThought reads an optional reference to an existing thought.
+func (s *SequentialThinkingServer) optionalThought(request mcp.CallToolRequest, key string) (int, error) {
+	n := request.GetInt(key, 0)
+	if n != 0 && !s.hasThought(n) {
+		return 0, invalid(key, "recorded thought number", n, "thought %d does not exist", n)
+	}
+	return n, nil
+}
+
+func (s *SequentialThinkingServer) raiseQuestion(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	question, err := request.RequireString("question")
+	if err != nil || question == "" {
+		return s.fail(ctx, request, invalid("question", "non-empty string", request.GetArguments()["question"], "must be a non-empty string"))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n, err := s.optionalThought(request, "thoughtNumber")
+	if err != nil {
+		return s.fail(ctx, request, err)
+	}
+
+	s.questionSeq++
+	q := Question{
+		ID:       fmt.Sprintf("Q%d", s.questionSeq),
+		Question: question,
+		RaisedIn: n,
+	}
+	s.questions = append(s.questions, q)
+
+	if !s.disableThoughtLogging {
+		s.logEvent(s.theme.Record, fmt.Sprintf("❓ Question %s:", q.ID), question)
+	}
+
+	return s.respond(ctx, request, map[string]any{
+		"question": q,
+	})
+}
+
+func (s *SequentialThinkingServer) answerQuestion(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	id, err := request.RequireString("questionId")
+	if err != nil || id == "" {
+		return s.fail(ctx, request, invalid("questionId", "non-empty string", request.GetArguments()["questionId"], "must be a non-empty string"))
+	}
+	answer, err := request.RequireString("answer")
+	if err != nil || answer == "" {
+		return s.fail(ctx, request, invalid("answer", "non-empty string", request.GetArguments()["answer"], "must be a non-empty string"))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n, err := s.optionalThought(request, "thoughtNumber")
+	if err != nil {
+		return s.fail(ctx, request, err)
+	}
+
+	var q *Question
+	for i := range s.questions {
+		if s.questions[i].ID == id {
+			q = &s.questions[i]
+		}
+	}
+	switch {
+	case q == nil:
+		return s.fail(ctx, request, invalid("questionId", "recorded question ID", id, "unknown question %q", id))
+	case !q.open():
+		return s.fail(ctx, request, invalid("questionId", "ID of an open question", id, "question %s was already answered", id))
+	}
+
+	q.Answer = answer
+	q.AnsweredIn = n
+
+	if !s.disableThoughtLogging {
+		s.logEvent(s.theme.Record, fmt.Sprintf("💬 Answered %s:", q.ID), answer)
+	}
+
+	return s.respond(ctx, request, map[string]any{
+		"question": q,
+	})
+}
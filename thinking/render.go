@@ -0,0 +1,173 @@
+package thinking
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	exportURIPrefix   = "thoughts://export/"
+	exportURITemplate = exportURIPrefix + "{format}"
+)
+
+// Exporter renders a session document in one output format. Exporters added
+// with WithExporter become formats of render_session and of the
+// thoughts://export/{format} resource.
+type Exporter interface {
+	// Format is the name clients select the exporter by.
+	Format() string
+	// MIMEType is the media type of the rendered documents.
+	MIMEType() string
+	// Render renders a session. It must not modify doc.
+	Render(doc *SessionDocument) ([]byte, error)
+}
+
+// builtinExporter renders straight from the server state. Its Render loads
+// the document into a scratch server first.
+type builtinExporter struct {
+	format, mimeType string
+	render           func(s *SequentialThinkingServer) (string, error)
+}
+
+func (e builtinExporter) Format() string   { return e.format }
+func (e builtinExporter) MIMEType() string { return e.mimeType }
+
+func (e builtinExporter) Render(doc *SessionDocument) ([]byte, error) {
+	scratch := NewSequentialThinkingServer()
+	scratch.disableThoughtLogging = true
+	if err := scratch.replaceSession(context.Background(), doc); err != nil {
+		return nil, err
+	}
+	document, err := e.render(scratch)
+	return []byte(document), err
+}
+
+// writerExporter adapts a function streaming a format to a renderer.
+func writerExporter(write func(s *SequentialThinkingServer, b *strings.Builder) error) func(*SequentialThinkingServer) (string, error) {
+	return func(s *SequentialThinkingServer) (string, error) {
+		var b strings.Builder
+		err := write(s, &b)
+		return b.String(), err
+	}
+}
+
+// plainExporter adapts a renderer that can't fail.
+func plainExporter(render func(s *SequentialThinkingServer) string) func(*SequentialThinkingServer) (string, error) {
+	return func(s *SequentialThinkingServer) (string, error) {
+		return render(s), nil
+	}
+}
+
+// builtinExporters are the formats every server provides, in the order they
+// are listed.
+var builtinExporters = []builtinExporter{
+	{"markdown", "text/markdown", plainExporter((*SequentialThinkingServer).renderMarkdown)},
+	{"mermaid", "text/vnd.mermaid", plainExporter((*SequentialThinkingServer).renderMermaid)},
+	{"dot", "text/vnd.graphviz", plainExporter((*SequentialThinkingServer).renderDOT)},
+	{"html", "text/html", plainExporter((*SequentialThinkingServer).renderHTML)},
+	{"json", "application/json", func(s *SequentialThinkingServer) (string, error) {
+		jsonBytes, err := json.MarshalIndent(s.sessionDocument(), "", "  ")
+		return string(jsonBytes), err
+	}},
+	{"jsonl", "application/jsonl", writerExporter(func(s *SequentialThinkingServer, b *strings.Builder) error { return s.writeJSONL(b) })},
+	{"csv", "text/csv", writerExporter(func(s *SequentialThinkingServer, b *strings.Builder) error { return s.writeCSV(b) })},
+	{"org", "text/org", plainExporter((*SequentialThinkingServer).renderOrg)},
+	{"messages", "application/json", (*SequentialThinkingServer).renderMessages},
+	{"opml", "text/x-opml", (*SequentialThinkingServer).renderOPML},
+	{"tree", "text/plain", plainExporter((*SequentialThinkingServer).renderTree)},
+	{"timeline", "text/plain", plainExporter((*SequentialThinkingServer).renderTimeline)},
+}
+
+// WithExporter adds e to the formats sessions can be rendered in, replacing
+// the exporter of the same format, built-in ones included.
+func WithExporter(e Exporter) Option {
+	return func(s *SequentialThinkingServer) {
+		for i, existing := range s.exporters {
+			if existing.Format() == e.Format() {
+				s.exporters[i] = e
+				return
+			}
+		}
+		s.exporters = append(s.exporters, e)
+	}
+}
+
+// renderFormats lists the formats sessions can be rendered in.
+func (s *SequentialThinkingServer) renderFormats() []string {
+	formats := make([]string, len(s.exporters))
+	for i, e := range s.exporters {
+		formats[i] = e.Format()
+	}
+	return formats
+}
+
+// render returns the session as a document in the given format, with the
+// document's MIME type. Built-in formats render from the state directly;
+// other exporters get a session document.
+func (s *SequentialThinkingServer) render(format string) (string, string, error) {
+	for _, e := range s.exporters {
+		if e.Format() != format {
+			continue
+		}
+		if builtin, ok := e.(builtinExporter); ok {
+			document, err := builtin.render(s)
+			return document, e.MIMEType(), err
+		}
+		document, err := e.Render(s.sessionDocument())
+		return string(document), e.MIMEType(), err
+	}
+	return "", "", fmt.Errorf("unknown format %q; available formats are %s", format, strings.Join(s.renderFormats(), ", "))
+}
+
+// authoredBy returns a scratch server holding a copy of the session with
+// only the thoughts of author, to render from; s is left as it is.
+func (s *SequentialThinkingServer) authoredBy(author string) *SequentialThinkingServer {
+	snap := s.snapshot()
+	snap.thoughtHistory = slices.DeleteFunc(snap.thoughtHistory, func(t ThoughtData) bool { return t.Author != author })
+	scratch := NewSequentialThinkingServer()
+	scratch.disableThoughtLogging = true
+	scratch.exporters = s.exporters
+	scratch.restore(snap)
+	scratch.rebuildBranches()
+	return scratch
+}
+
+func (s *SequentialThinkingServer) renderSession(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	format := request.GetString("format", "markdown")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	source := s
+	if author, ok := request.GetArguments()["author"].(string); ok {
+		source = s.authoredBy(author)
+	}
+	document, _, err := source.render(format)
+	if err != nil {
+		return s.fail(ctx, request, invalid("format", "one of "+strings.Join(s.renderFormats(), ", "), format, "%v", err))
+	}
+	return s.respond(ctx, request, map[string]any{
+		"format":   format,
+		"document": document,
+	})
+}
+
+func (s *SequentialThinkingServer) readExport(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	document, mimeType, err := s.render(templateArgument(request, "format"))
+	if err != nil {
+		return nil, err
+	}
+	return []mcp.ResourceContents{mcp.TextResourceContents{
+		URI:      request.Params.URI,
+		MIMEType: mimeType,
+		Text:     document,
+	}}, nil
+}
@@ -0,0 +1,37 @@
+package thinking
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestRenderSessionByAuthor(t *testing.T) {
+	s := newTestServer(t)
+	mustCall(t, s.processThought, thought(1, 3, "a", map[string]any{"author": "alice"}))
+	mustCall(t, s.processThought, thought(2, 3, "b", map[string]any{"author": "bob"}))
+	mustCall(t, s.processThought, thought(2, 3, "c", map[string]any{"author": "bob", "branchId": "alt", "branchFromThought": 1.0}))
+	mustCall(t, s.processThought, thought(3, 3, "d", map[string]any{"author": "alice"}))
+	branches := s.branches
+	before := sessionJSON(t, s.ExportSession())
+
+	fields := mustCall(t, s.renderSession, map[string]any{"format": "json", "author": "alice"})
+	var doc SessionDocument
+	if err := json.Unmarshal([]byte(fields["document"].(string)), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Thoughts) != 2 || len(doc.Branches) != 0 {
+		t.Errorf("rendered %d thoughts and %d branches, want 2 and 0", len(doc.Thoughts), len(doc.Branches))
+	}
+	for _, th := range doc.Thoughts {
+		if th.Author != "alice" {
+			t.Errorf("rendered thought %d by %s", th.ThoughtNumber, th.Author)
+		}
+	}
+
+	if after := sessionJSON(t, s.ExportSession()); after != before {
+		t.Errorf("rendering changed the session:\ngot  %s\nwant %s", after, before)
+	}
+	if len(s.branches) != 1 || s.branches["alt"] != branches["alt"] {
+		t.Errorf("rendering replaced the branches")
+	}
+}
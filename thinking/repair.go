@@ -0,0 +1,210 @@
+package thinking
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Renumbering records how repair_sequence moved one thought.
+type Renumbering struct {
+	From     int    `json:"from"`
+	To       int    `json:"to"`
+	BranchId string `json:"branchId,omitempty"`
+}
+
+// rebuildBranches regenerates the per-branch copies from the history,
+// keeping what else is known of each branch.
+func (s *SequentialThinkingServer) rebuildBranches() {
+	branches := make(map[string]*Branch, len(s.branches))
+	for _, t := range s.thoughtHistory {
+		id := branchOf(&t)
+		if id == "" {
+			continue
+		}
+		b := branches[id]
+		if b == nil {
+			b = &Branch{ID: id, CreatedAt: t.ReceivedAt, Status: BranchOpen}
+			if old := s.branches[id]; old != nil {
+				b = old.clone()
+				b.Thoughts = nil
+			}
+			b.BranchFromThought = *t.BranchFromThought
+			branches[id] = b
+		}
+		b.Thoughts = append(b.Thoughts, t)
+	}
+	s.branches = branches
+}
+
+// remapReferences rewrites the thought numbers held outside the history,
+// which don't name a line.
+func (s *SequentialThinkingServer) remapReferences(remap func(int) int) {
+	for i := range s.hypotheses {
+		h := &s.hypotheses[i]
+		h.ProposedIn = remap(h.ProposedIn)
+		for j := range h.SupportingThoughts {
+			h.SupportingThoughts[j] = remap(h.SupportingThoughts[j])
+		}
+		for j := range h.RefutingThoughts {
+			h.RefutingThoughts[j] = remap(h.RefutingThoughts[j])
+		}
+	}
+	for i := range s.assumptions {
+		s.assumptions[i].ThoughtNumber = remap(s.assumptions[i].ThoughtNumber)
+	}
+	for i := range s.questions {
+		s.questions[i].RaisedIn = remap(s.questions[i].RaisedIn)
+		s.questions[i].AnsweredIn = remap(s.questions[i].AnsweredIn)
+	}
+	if s.finalAnswer != nil {
+		answer := *s.finalAnswer
+		answer.ThoughtNumber = remap(answer.ThoughtNumber)
+		s.finalAnswer = &answer
+	}
+	for i := range s.decisions {
+		s.decisions[i].ThoughtNumber = remap(s.decisions[i].ThoughtNumber)
+	}
+	for i := range s.modelApplications {
+		a := &s.modelApplications[i]
+		for j := range a.Thoughts {
+			a.Thoughts[j] = remap(a.Thoughts[j])
+		}
+	}
+	for i := range s.debugSessions {
+		d := &s.debugSessions[i]
+		for j := range d.Steps {
+			d.Steps[j].ThoughtNumber = remap(d.Steps[j].ThoughtNumber)
+		}
+		for j := range d.Findings {
+			d.Findings[j].ThoughtNumber = remap(d.Findings[j].ThoughtNumber)
+		}
+	}
+	for i := range s.inquiries {
+		q := &s.inquiries[i]
+		for j := range q.Steps {
+			q.Steps[j].ThoughtNumber = remap(q.Steps[j].ThoughtNumber)
+		}
+	}
+	for i := range s.arguments {
+		s.arguments[i].ThoughtNumber = remap(s.arguments[i].ThoughtNumber)
+	}
+	for i := range s.assessments {
+		s.assessments[i].ThoughtNumber = remap(s.assessments[i].ThoughtNumber)
+	}
+	for i := range s.reviews {
+		s.reviews[i].ThoughtNumber = remap(s.reviews[i].ThoughtNumber)
+	}
+}
+
+// renumber assigns consecutive numbers to the history in recording order and
+// rewrites every reference to the old numbers.
+func (s *SequentialThinkingServer) renumber() []Renumbering {
+	repaired, changes, mapBare := s.renumbering()
+	s.remapReferences(mapBare)
+
+	s.thoughtHistory = repaired
+	s.rebuildBranches()
+	return changes
+}
+
+// renumbering works out renumber without changing the session: it returns
+// the renumbered history, the changes, and how bare references map to the
+// new numbers. Estimates of the total shift along with the numbers.
+// Line-scoped references (revisions, branch points) are resolved against the
+// thoughts recorded before the referencing one; bare references elsewhere
+// prefer the main line.
+func (s *SequentialThinkingServer) renumbering() ([]ThoughtData, []Renumbering, func(int) int) {
+	history := s.thoughtHistory
+	remap := func(line string, old, limit int) (int, bool) {
+		if i := s.indexBefore(line, old, limit); i >= 0 {
+			return i + 1, true
+		}
+		return old, false
+	}
+	bare := make(map[int]int)
+	for i := len(history) - 1; i >= 0; i-- {
+		if _, ok := bare[history[i].ThoughtNumber]; !ok || branchOf(&history[i]) == "" {
+			bare[history[i].ThoughtNumber] = i + 1
+		}
+	}
+	mapBare := func(old int) int {
+		if n, ok := bare[old]; ok {
+			return n
+		}
+		return old
+	}
+
+	index := s.historyIndex()
+	repaired := make([]ThoughtData, len(history))
+	changes := make([]Renumbering, 0)
+	for i, t := range history {
+		line := branchOf(&t)
+		if t.RevisesThought != nil && t.RevisesBranchId != nil {
+			if n, ok := remap(*t.RevisesBranchId, *t.RevisesThought, i); ok {
+				t.RevisesThought = &n
+			}
+		}
+		if t.BranchFromThought != nil {
+			if n, ok := remap("", *t.BranchFromThought, i); ok {
+				t.BranchFromThought = &n
+			}
+		}
+		if parent := s.parentIndex(&t); parent >= 0 {
+			n := parent + 1
+			t.ParentThought = &n
+		}
+		if t.DependencyIds != nil {
+			dependsOn := make([]int, 0, len(t.DependencyIds))
+			for _, id := range t.DependencyIds {
+				if d, ok := index[id]; ok {
+					dependsOn = append(dependsOn, d+1)
+				}
+			}
+			t.DependsOn = dependsOn
+		}
+		if t.MergedBranchId != nil {
+			merged := make([]int, len(t.MergedThoughts))
+			for j, old := range t.MergedThoughts {
+				merged[j], _ = remap(*t.MergedBranchId, old, i)
+			}
+			t.MergedThoughts = merged
+		}
+		if t.ThoughtNumber != i+1 {
+			changes = append(changes, Renumbering{From: t.ThoughtNumber, To: i + 1, BranchId: line})
+		}
+		t.TotalThoughts = max(t.TotalThoughts-(t.ThoughtNumber-(i+1)), i+1)
+		t.ThoughtNumber = i + 1
+		repaired[i] = t
+	}
+	return repaired, changes, mapBare
+}
+
+func (s *SequentialThinkingServer) repairSequence(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	dryRun := request.GetBool("dryRun", false)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var changes []Renumbering
+	if dryRun {
+		_, changes, _ = s.renumbering()
+	} else {
+		changes = s.renumber()
+		if len(changes) > 0 {
+			s.generation++
+		}
+		s.resourcesChanged()
+		if !s.disableThoughtLogging && len(changes) > 0 {
+			s.logEvent(s.theme.Record, fmt.Sprintf("🔢 Renumbered %d thoughts", len(changes)), "")
+		}
+	}
+
+	return s.respond(ctx, request, map[string]any{
+		"renumbered":           changes,
+		"changed":              len(changes),
+		"dryRun":               dryRun,
+		"thoughtHistoryLength": len(s.thoughtHistory),
+	})
+}
@@ -0,0 +1,107 @@
+package thinking
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+func TestRepairSequence(t *testing.T) {
+	tests := []struct {
+		name        string
+		history     []map[string]any
+		wantChanges []Renumbering
+		wantNumbers []int
+	}{
+		{
+			name: "consecutive",
+			history: []map[string]any{
+				thought(1, 2, "a", nil),
+				thought(2, 2, "b", nil),
+			},
+			wantChanges: []Renumbering{},
+			wantNumbers: []int{1, 2},
+		},
+		{
+			name: "gap",
+			history: []map[string]any{
+				thought(1, 5, "a", nil),
+				thought(3, 5, "b", nil),
+				thought(5, 5, "c", nil),
+			},
+			wantChanges: []Renumbering{{From: 3, To: 2}, {From: 5, To: 3}},
+			wantNumbers: []int{1, 2, 3},
+		},
+		{
+			name: "revision follows its target",
+			history: []map[string]any{
+				thought(1, 4, "a", nil),
+				thought(4, 4, "b", nil),
+				thought(5, 5, "b again", map[string]any{"isRevision": true, "revisesThought": 4.0}),
+			},
+			wantChanges: []Renumbering{{From: 4, To: 2}, {From: 5, To: 3}},
+			wantNumbers: []int{1, 2, 3},
+		},
+		{
+			name: "branch",
+			history: []map[string]any{
+				thought(1, 3, "a", nil),
+				thought(3, 3, "b", map[string]any{"branchId": "alt", "branchFromThought": 1.0}),
+			},
+			wantChanges: []Renumbering{{From: 3, To: 2, BranchId: "alt"}},
+			wantNumbers: []int{1, 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t)
+			for _, args := range tt.history {
+				mustCall(t, s.processThought, args)
+			}
+			before, generation := numbers(s), s.generation
+
+			dry := mustCall(t, s.repairSequence, map[string]any{"dryRun": true})
+			if got := numbers(s); !slices.Equal(got, before) {
+				t.Errorf("dry run changed the numbers to %v", got)
+			}
+			if s.generation != generation {
+				t.Errorf("dry run rewrote the history")
+			}
+			if got := renumberings(t, dry); !slices.Equal(got, tt.wantChanges) {
+				t.Errorf("dry run renumbered = %v, want %v", got, tt.wantChanges)
+			}
+
+			done := mustCall(t, s.repairSequence, nil)
+			if got := renumberings(t, done); !slices.Equal(got, tt.wantChanges) {
+				t.Errorf("renumbered = %v, want %v", got, tt.wantChanges)
+			}
+			if got := numbers(s); !slices.Equal(got, tt.wantNumbers) {
+				t.Errorf("numbers = %v, want %v", got, tt.wantNumbers)
+			}
+			for _, th := range s.thoughtHistory {
+				if th.RevisesThought != nil && *th.RevisesThought >= th.ThoughtNumber {
+					t.Errorf("thought %d revises thought %d", th.ThoughtNumber, *th.RevisesThought)
+				}
+			}
+
+			again := mustCall(t, s.repairSequence, nil)
+			if got := renumberings(t, again); len(got) != 0 {
+				t.Errorf("a second repair renumbered %v", got)
+			}
+		})
+	}
+}
+
+// renumberings reads the renumbered field of a repair_sequence result.
+func renumberings(t *testing.T, fields map[string]any) []Renumbering {
+	t.Helper()
+	jsonBytes, err := json.Marshal(fields["renumbered"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var changes []Renumbering
+	if err := json.Unmarshal(jsonBytes, &changes); err != nil {
+		t.Fatal(err)
+	}
+	return changes
+}
@@ -0,0 +1,299 @@
+package thinking
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	historyURI         = "thoughts://history"
+	branchURIPrefix    = "thoughts://branch/"
+	branchURITemplate  = branchURIPrefix + "{branchId}"
+	sessionURIPrefix   = "thoughts://session/"
+	thoughtURITemplate = sessionURIPrefix + "{sid}/thought/{n}"
+)
+
+func branchURI(branchId string) string {
+	return branchURIPrefix + url.PathEscape(branchId)
+}
+
+// registerResources exposes the live history and its branches as resources.
+func (s *SequentialThinkingServer) registerResources(srv *server.MCPServer) {
+	s.srv = srv
+	srv.AddResource(
+		mcp.NewResource(historyURI, "Thought history",
+			mcp.WithResourceDescription("Every thought recorded in the session, in order, with the branch IDs"),
+			mcp.WithMIMEType("application/json"),
+		),
+		s.readHistory,
+	)
+	srv.AddResourceTemplate(
+		mcp.NewResourceTemplate(branchURITemplate, "Branch",
+			mcp.WithTemplateDescription("The thoughts of one branch with its branching point and status"),
+			mcp.WithTemplateMIMEType("application/json"),
+		),
+		s.readBranch,
+	)
+	srv.AddResourceTemplate(
+		mcp.NewResourceTemplate(thoughtURITemplate, "Thought",
+			mcp.WithTemplateDescription("One main-line thought of the session with its revisions and the branches forked from it"),
+			mcp.WithTemplateMIMEType("application/json"),
+		),
+		s.readThought,
+	)
+	srv.AddResourceTemplate(
+		mcp.NewResourceTemplate(exportURITemplate, "Rendered session",
+			mcp.WithTemplateDescription("The whole session rendered as a document: "+strings.Join(s.renderFormats(), ", ")),
+		),
+		s.readExport,
+	)
+}
+
+func jsonContents(uri string, v any) ([]mcp.ResourceContents, error) {
+	jsonBytes, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return []mcp.ResourceContents{mcp.TextResourceContents{
+		URI:      uri,
+		MIMEType: "application/json",
+		Text:     string(jsonBytes),
+	}}, nil
+}
+
+func (s *SequentialThinkingServer) readHistory(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return jsonContents(request.Params.URI, map[string]any{
+		"sessionId": s.sessionID,
+		"thoughts":  s.thoughtHistory,
+		"branches":  s.branchNames(),
+	})
+}
+
+func (s *SequentialThinkingServer) readBranch(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	branchId := templateArgument(request, "branchId")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b := s.branches[branchId]
+	if b == nil {
+		return nil, fmt.Errorf("unknown branch %q", branchId)
+	}
+	branch := map[string]any{
+		"branchId":          branchId,
+		"branchFromThought": b.BranchFromThought,
+		"status":            b.Status,
+		"thoughts":          b.Thoughts,
+	}
+	if b.CreatedAt != nil {
+		branch["createdAt"] = b.CreatedAt
+	}
+	if b.Description != "" {
+		branch["description"] = b.Description
+	}
+	if into, ok := s.mergedInto(branchId); ok {
+		branch["mergedInto"] = into
+	}
+	if reason, ok := s.abandonReason(branchId); ok {
+		branch["abandoned"] = true
+		branch["abandonReason"] = reason
+	}
+	return jsonContents(request.Params.URI, branch)
+}
+
+// templateArgument returns a URI template variable as a string.
+func templateArgument(request mcp.ReadResourceRequest, name string) string {
+	switch v := request.Params.Arguments[name].(type) {
+	case string:
+		return v
+	case []string:
+		return strings.Join(v, ",")
+	}
+	return ""
+}
+
+// readThought serves a main-line thought of the current session. Links into
+// a session that was cleared since don't resolve.
+func (s *SequentialThinkingServer) readThought(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	sid := templateArgument(request, "sid")
+	n, err := strconv.Atoi(templateArgument(request, "n"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid thought number %q", templateArgument(request, "n"))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if sid != s.sessionID {
+		return nil, fmt.Errorf("unknown session %q", sid)
+	}
+	_, thought, err := s.findThought("", n)
+	if err != nil {
+		return nil, err
+	}
+
+	forks := make([]string, 0)
+	for _, id := range s.branchNames() {
+		if s.branchOrigin(id) == n {
+			forks = append(forks, id)
+		}
+	}
+	return jsonContents(request.Params.URI, map[string]any{
+		"sessionId": s.sessionID,
+		"thought":   thought,
+		"live":      s.liveThoughts()(thought),
+		"revisions": s.revisionsOf("", n),
+		"branches":  forks,
+	})
+}
+
+// resourcesChanged notifies subscribers that the history changed, along with
+// the branches of the given lines. Without lines, every subscribed resource
+// is notified. Dashboard pages are told about every change.
+func (s *SequentialThinkingServer) resourcesChanged(lines ...string) {
+	if s.held != nil {
+		s.held.all = s.held.all || len(lines) == 0
+		s.held.lines = append(s.held.lines, lines...)
+		return
+	}
+	s.notifyWatchers()
+	if s.srv == nil {
+		return
+	}
+	s.subMu.Lock()
+	defer s.subMu.Unlock()
+
+	for uri := range s.subscribed {
+		changed := len(lines) == 0 || uri == historyURI ||
+			strings.HasPrefix(uri, sessionURIPrefix) || strings.HasPrefix(uri, exportURIPrefix)
+		for _, line := range lines {
+			changed = changed || line != "" && uri == branchURI(line)
+		}
+		if changed {
+			s.srv.SendNotificationToAllClients(mcp.MethodNotificationResourceUpdated, map[string]any{"uri": uri})
+		}
+	}
+}
+
+// heldChanges collects the changes resourcesChanged was told about while
+// notifications are held.
+type heldChanges struct {
+	all   bool
+	lines []string
+}
+
+// holdNotifications makes resourcesChanged collect changes instead of
+// notifying, until releaseNotifications.
+func (s *SequentialThinkingServer) holdNotifications() {
+	s.held = &heldChanges{}
+}
+
+// releaseNotifications stops holding notifications and, if send is set,
+// notifies once of every change held; otherwise the changes are dropped.
+func (s *SequentialThinkingServer) releaseNotifications(send bool) {
+	held := s.held
+	s.held = nil
+	switch {
+	case !send:
+	case held.all:
+		s.resourcesChanged()
+	case len(held.lines) > 0:
+		slices.Sort(held.lines)
+		s.resourcesChanged(slices.Compact(held.lines)...)
+	}
+}
+
+// FilterSubscriptions answers the resources/subscribe and
+// resources/unsubscribe requests read from in, which mcp-go doesn't route,
+// writing the responses to out. All other messages pass through to the
+// returned reader, which should feed a stdio server that writes to the
+// returned writer, so that its messages and the responses don't interleave:
+//
+//	stdio := server.NewStdioServer(srv)
+//	in, out := s.FilterSubscriptions(os.Stdin, os.Stdout)
+//	stdio.Listen(ctx, in, out)
+func (s *SequentialThinkingServer) FilterSubscriptions(in io.Reader, out io.Writer) (io.Reader, io.Writer) {
+	out = &lockedWriter{w: out}
+	r, w := io.Pipe()
+	go func() {
+		reader := bufio.NewReader(in)
+		for {
+			line, err := reader.ReadBytes('\n')
+			if len(line) > 0 && !s.handleSubscription(line, out) {
+				if _, err := w.Write(line); err != nil {
+					return
+				}
+			}
+			if err == io.EOF {
+				w.Close()
+				return
+			} else if err != nil {
+				w.CloseWithError(err)
+				return
+			}
+		}
+	}()
+	return r, out
+}
+
+// lockedWriter serializes writes to w; each message goes out in one write.
+type lockedWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (l *lockedWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.w.Write(p)
+}
+
+// handleSubscription answers line if it is a subscription request.
+func (s *SequentialThinkingServer) handleSubscription(line []byte, out io.Writer) bool {
+	var message struct {
+		ID     json.RawMessage `json:"id"`
+		Method string          `json:"method"`
+		Params struct {
+			URI string `json:"uri"`
+		} `json:"params"`
+	}
+	if json.Unmarshal(line, &message) != nil || message.ID == nil {
+		return false
+	}
+	subscribe := message.Method == "resources/subscribe"
+	if !subscribe && message.Method != "resources/unsubscribe" {
+		return false
+	}
+
+	response := map[string]any{"jsonrpc": mcp.JSONRPC_VERSION, "id": message.ID}
+	if message.Params.URI == "" {
+		response["error"] = map[string]any{"code": mcp.INVALID_PARAMS, "message": "invalid uri: must be a non-empty string"}
+	} else {
+		s.subMu.Lock()
+		if subscribe {
+			s.subscribed[message.Params.URI] = true
+		} else {
+			delete(s.subscribed, message.Params.URI)
+		}
+		s.subMu.Unlock()
+		response["result"] = map[string]any{}
+	}
+
+	jsonBytes, _ := json.Marshal(response)
+	out.Write(append(jsonBytes, '\n'))
+	return true
+}
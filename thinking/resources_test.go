@@ -0,0 +1,66 @@
+package thinking
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestFilterSubscriptions(t *testing.T) {
+	s := newTestServer(t)
+	input := strings.Join([]string{
+		`{"jsonrpc":"2.0","id":1,"method":"resources/subscribe","params":{"uri":"thinking://history"}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"resources/unsubscribe","params":{"uri":"thinking://summary"}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"resources/subscribe","params":{}}`,
+	}, "\n") + "\n"
+
+	var buf strings.Builder
+	in, out := s.FilterSubscriptions(strings.NewReader(input), &buf)
+
+	// The stdio server writes while the subscriptions are answered.
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for range 100 {
+			out.Write([]byte(`{"jsonrpc":"2.0","method":"notifications/message"}` + "\n"))
+		}
+	}()
+	passed, err := io.ReadAll(in)
+	wg.Wait()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(passed), `{"jsonrpc":"2.0","id":2,"method":"tools/list"}`+"\n"; got != want {
+		t.Errorf("passed through %q, want %q", got, want)
+	}
+
+	responses := make(map[float64]map[string]any)
+	scanner := bufio.NewScanner(strings.NewReader(buf.String()))
+	for scanner.Scan() {
+		var message map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &message); err != nil {
+			t.Fatalf("interleaved output %q: %v", scanner.Text(), err)
+		}
+		if id, ok := message["id"].(float64); ok {
+			responses[id] = message
+		}
+	}
+	for id, wantErr := range map[float64]bool{1: false, 3: false, 4: true} {
+		response, ok := responses[id]
+		if !ok {
+			t.Errorf("request %v was not answered", id)
+			continue
+		}
+		if _, isError := response["error"]; isError != wantErr {
+			t.Errorf("request %v: response %v, want error %v", id, response, wantErr)
+		}
+	}
+	if !s.subscribed["thinking://history"] {
+		t.Errorf("thinking://history is not subscribed")
+	}
+}
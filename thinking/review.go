@@ -0,0 +1,126 @@
+package thinking
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// reviewTimeout bounds how long a self-review waits for the client.
+	reviewTimeout = 2 * time.Minute
+	// reviewMaxTokens caps the length of a sampled critique.
+	reviewMaxTokens = 600
+)
+
+// Review is a critique of the chain written by the client's model.
+type Review struct {
+	ID            string    `json:"id"`
+	ThoughtNumber int       `json:"thoughtNumber"`
+	Critique      string    `json:"critique"`
+	Model         string    `json:"model,omitempty"`
+	RecordedAt    time.Time `json:"recordedAt"`
+}
+
+// WithSelfReview makes the server ask the client's model for a short
+// critique of the chain after every n recorded thoughts, overriding
+// GOTHINK_SELF_REVIEW_EVERY. Reviews are only requested from clients that
+// support sampling; n <= 0 disables them.
+func WithSelfReview(n int) Option {
+	return func(s *SequentialThinkingServer) {
+		s.reviewEvery = n
+	}
+}
+
+func reviewEveryFromEnv() int {
+	n, _ := strconv.Atoi(os.Getenv("GOTHINK_SELF_REVIEW_EVERY"))
+	return n
+}
+
+// reviewDue reports whether the latest thought completes another interval of
+// n thoughts and the client can be asked for a review.
+func (s *SequentialThinkingServer) reviewDue(ctx context.Context) bool {
+	if s.srv == nil || s.reviewEvery <= 0 || len(s.thoughtHistory)%s.reviewEvery != 0 {
+		return false
+	}
+	return clientCapabilities(ctx).Sampling != nil
+}
+
+// reviewRequest asks for a critique of the thoughts that still stand.
+func (s *SequentialThinkingServer) reviewRequest() mcp.CreateMessageRequest {
+	var b strings.Builder
+	b.WriteString("Critique this chain of reasoning in a few sentences. Point out errors, gaps, unverified steps " +
+		"and conclusions that don't follow. Don't solve the problem yourself.\n\n")
+	live := s.liveThoughts()
+	for i := range s.thoughtHistory {
+		t := &s.thoughtHistory[i]
+		if !live(t) {
+			continue
+		}
+		if line := branchOf(t); line != "" {
+			fmt.Fprintf(&b, "Thought %d (branch %s): %s\n", t.ThoughtNumber, line, t.Thought)
+		} else {
+			fmt.Fprintf(&b, "Thought %d: %s\n", t.ThoughtNumber, t.Thought)
+		}
+	}
+
+	return mcp.CreateMessageRequest{
+		CreateMessageParams: mcp.CreateMessageParams{
+			Messages: []mcp.SamplingMessage{
+				{Role: mcp.RoleUser, Content: mcp.NewTextContent(b.String())},
+			},
+			SystemPrompt: "You review reasoning for flaws. Be brief and specific, and cite thought numbers.",
+			MaxTokens:    reviewMaxTokens,
+		},
+	}
+}
+
+// startReview requests a review of the chain as it stands after thought
+// thoughtNumber in the background. It must be called with the lock held.
+func (s *SequentialThinkingServer) startReview(ctx context.Context, thoughtNumber int) {
+	go s.selfReview(ctx, s.reviewRequest(), thoughtNumber, s.sessionID, s.generation)
+}
+
+// selfReview requests a critique from the client and records it. It runs
+// without the lock held, since the client may take a while to answer. The
+// critique is dropped if the history it reviewed was reset, restored or
+// rewritten meanwhile.
+func (s *SequentialThinkingServer) selfReview(ctx context.Context, request mcp.CreateMessageRequest, thoughtNumber int, sessionID string, generation int) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), reviewTimeout)
+	defer cancel()
+
+	result, err := s.srv.RequestSampling(ctx, request)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err != nil {
+		if !s.disableThoughtLogging {
+			s.logEvent(s.theme.Alert, fmt.Sprintf("🔍 Self-review failed: %v", err), "")
+		}
+		return
+	}
+	if s.sessionID != sessionID || s.generation != generation {
+		return
+	}
+
+	s.reviewSeq++
+	review := Review{
+		ID:            fmt.Sprintf("R%d", s.reviewSeq),
+		ThoughtNumber: thoughtNumber,
+		Critique:      mcp.GetTextFromContent(result.Content),
+		Model:         result.Model,
+		RecordedAt:    time.Now().UTC(),
+	}
+	s.reviews = append(s.reviews, review)
+	s.resourcesChanged()
+
+	if !s.disableThoughtLogging {
+		s.logEvent(s.theme.Record, fmt.Sprintf("🔍 Review %s of thought %d:", review.ID, thoughtNumber), review.Critique)
+	}
+}
@@ -0,0 +1,87 @@
+package thinking
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// heldSampler answers sampling requests once the test sends on release.
+type heldSampler struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (h *heldSampler) CreateMessage(ctx context.Context, request mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+	h.started <- struct{}{}
+	<-h.release
+	return &mcp.CreateMessageResult{
+		SamplingMessage: mcp.SamplingMessage{Role: mcp.RoleAssistant, Content: mcp.NewTextContent("thought 1 is unverified")},
+		Model:           "test",
+	}, nil
+}
+
+func TestSelfReview(t *testing.T) {
+	tests := []struct {
+		name        string
+		meanwhile   string
+		wantReviews int
+	}{
+		{name: "recorded", wantReviews: 1},
+		{name: "dropped after a reset", meanwhile: "clear_history"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t, WithSelfReview(1))
+			srv := server.NewMCPServer("test", "0", server.WithResourceCapabilities(true, false))
+			s.Register(srv)
+			sampler := &heldSampler{started: make(chan struct{}, 1), release: make(chan struct{})}
+			c, err := client.NewInProcessClientWithSamplingHandler(srv, sampler)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx := context.Background()
+			if err := c.Start(ctx); err != nil {
+				t.Fatal(err)
+			}
+			defer c.Close()
+			if _, err := c.Initialize(ctx, mcp.InitializeRequest{}); err != nil {
+				t.Fatal(err)
+			}
+
+			var request mcp.CallToolRequest
+			request.Params.Name = "sequentialthinking"
+			request.Params.Arguments = thought(1, 2, "a", nil)
+			if _, err := c.CallTool(ctx, request); err != nil {
+				t.Fatal(err)
+			}
+			<-sampler.started
+			if tt.meanwhile != "" {
+				request.Params.Name, request.Params.Arguments = tt.meanwhile, nil
+				if _, err := c.CallTool(ctx, request); err != nil {
+					t.Fatal(err)
+				}
+			}
+			changes := s.watch()
+			defer s.unwatch(changes)
+			close(sampler.release)
+
+			select {
+			case <-changes:
+			case <-time.After(200 * time.Millisecond):
+				if tt.wantReviews > 0 {
+					t.Fatalf("the review was not announced")
+				}
+			}
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			if len(s.reviews) != tt.wantReviews {
+				t.Errorf("got %d reviews, want %d", len(s.reviews), tt.wantReviews)
+			}
+		})
+	}
+}
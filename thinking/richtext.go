@@ -0,0 +1,82 @@
+package thinking
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/glamour/styles"
+)
+
+// MarkdownLines renders Markdown text for the terminal in the glamour style
+// named style (such as dark, light or notty), wrapped to width columns.
+// With an empty or unknown style, text is wrapped as written.
+func MarkdownLines(text string, width int, style string) []string {
+	config, ok := styles.DefaultStyles[style]
+	if !ok {
+		return wrapText(text, width)
+	}
+	// Boxes and panes have their own margins.
+	document := *config
+	document.Document.Margin = new(uint)
+	renderer, err := glamour.NewTermRenderer(glamour.WithStyles(document), glamour.WithWordWrap(width))
+	if err != nil {
+		return wrapText(text, width)
+	}
+	rendered, err := renderer.Render(text)
+	if err != nil {
+		return wrapText(text, width)
+	}
+
+	// Glamour pads every line to the wrap width and surrounds the document
+	// with blank lines.
+	lines := strings.Split(rendered, "\n")
+	for i, line := range lines {
+		lines[i] = trimRightANSI(line)
+	}
+	for len(lines) > 0 && stripANSI(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && stripANSI(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) == 0 {
+		return []string{""}
+	}
+	return lines
+}
+
+// trimRightANSI removes the trailing spaces of a colored line, resetting
+// colors if escape sequences were among them.
+func trimRightANSI(s string) string {
+	end := 0
+	for i := 0; i < len(s); {
+		if n := escapeLength(s[i:]); n > 0 {
+			i += n
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		if !unicode.IsSpace(r) {
+			end = i
+		}
+	}
+	if end > 0 && strings.Contains(s[end:], "\x1b[") {
+		return s[:end] + "\x1b[0m"
+	}
+	return s[:end]
+}
+
+// escapeLength returns the length of the terminal escape sequence s starts
+// with, or 0 if it doesn't start with one.
+func escapeLength(s string) int {
+	if !strings.HasPrefix(s, "\x1b[") {
+		return 0
+	}
+	i := 2
+	for i < len(s) && (s[i] < 0x40 || s[i] > 0x7e) {
+		i++
+	}
+	return min(i+1, len(s))
+}
@@ -0,0 +1,154 @@
+package thinking
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// wholeNumber declares a number property a JSON Schema integer.
+func wholeNumber() mcp.PropertyOption {
+	return func(schema map[string]any) {
+		schema["type"] = "integer"
+	}
+}
+
+// checkArguments validates tool arguments against the tool's input schema,
+// so that what a tool advertises is what it accepts: required properties
+// must be present, and present ones must have the declared type and keep to
+// its minimum, maximum, minLength, maxLength and enum. Properties the schema
+// doesn't declare are left alone.
+func checkArguments(schema mcp.ToolInputSchema, args map[string]any) error {
+	for _, name := range schema.Required {
+		if _, ok := args[name]; !ok {
+			property, _ := schema.Properties[name].(map[string]any)
+			return invalid(name, describeProperty(property), nil, "%s", typeProblem(property))
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(args)) {
+		property, ok := schema.Properties[name].(map[string]any)
+		if !ok {
+			continue
+		}
+		if err := checkProperty(name, property, args[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkProperty checks the value of one argument, or one item of an array
+// argument, against its schema.
+func checkProperty(name string, property map[string]any, val any) *ValidationError {
+	expected := describeProperty(property)
+	switch property["type"] {
+	case "integer", "number":
+		num, ok := number(val)
+		if !ok {
+			return invalid(name, expected, val, "must be a number")
+		}
+		if property["type"] == "integer" && num != math.Trunc(num) {
+			return invalid(name, expected, val, "must be a whole number, got %v", num)
+		}
+		if minimum, ok := property["minimum"].(float64); ok && num < minimum {
+			return invalid(name, expected, val, "must be at least %v, got %v", minimum, num)
+		}
+		if maximum, ok := property["maximum"].(float64); ok && num > maximum {
+			return invalid(name, expected, val, "must be at most %v, got %v", maximum, num)
+		}
+	case "string":
+		text, ok := val.(string)
+		if !ok {
+			return invalid(name, expected, val, "%s", typeProblem(property))
+		}
+		length := utf8.RuneCountInString(text)
+		if minLength, ok := property["minLength"].(int); ok && length < minLength {
+			return invalid(name, expected, val, "%s", typeProblem(property))
+		}
+		if maxLength, ok := property["maxLength"].(int); ok && length > maxLength {
+			return invalid(name, expected, val, "must be at most %d characters, got %d", maxLength, length)
+		}
+		if values, ok := property["enum"].([]string); ok && !slices.Contains(values, text) {
+			return invalid(name, expected, val, "must be one of %s, got %q", strings.Join(values, ", "), text)
+		}
+	case "boolean":
+		if _, ok := val.(bool); !ok {
+			return invalid(name, expected, val, "must be a boolean")
+		}
+	case "object":
+		if _, ok := val.(map[string]any); !ok {
+			return invalid(name, expected, val, "%s", typeProblem(property))
+		}
+	case "array":
+		list, ok := val.([]any)
+		if !ok {
+			return invalid(name, expected, val, "%s", typeProblem(property))
+		}
+		items, _ := property["items"].(map[string]any)
+		for i, item := range list {
+			if problem := checkProperty(name, items, item); problem != nil {
+				problem.Message = fmt.Sprintf("invalid %s: item %d %s", name, i, strings.TrimPrefix(problem.Message, "invalid "+name+": "))
+				problem.Path = fmt.Sprintf("%s/%d", problem.Path, i)
+				return problem
+			}
+		}
+	}
+	return nil
+}
+
+// typeProblem says what type a property must have, for a value that is
+// missing or of another type.
+func typeProblem(property map[string]any) string {
+	switch property["type"] {
+	case "integer", "number":
+		return "must be a number"
+	case "string":
+		if minLength, _ := property["minLength"].(int); minLength > 0 {
+			return "must be a non-empty string"
+		}
+		return "must be a string"
+	case "array":
+		if items, ok := property["items"].(map[string]any); ok {
+			return fmt.Sprintf("must be an array of %vs", items["type"])
+		}
+		return "must be an array"
+	case "object":
+		return "must be an object"
+	default:
+		return fmt.Sprintf("must be a %v", property["type"])
+	}
+}
+
+// describeProperty describes the values a property accepts, such as
+// "integer of at least 1" or "non-empty string".
+func describeProperty(property map[string]any) string {
+	if values, ok := property["enum"].([]string); ok {
+		return "one of " + strings.Join(values, ", ")
+	}
+	kind, _ := property["type"].(string)
+	if items, ok := property["items"].(map[string]any); kind == "array" && ok {
+		return fmt.Sprintf("array of %vs", items["type"])
+	}
+	var limits []string
+	if minimum, ok := property["minimum"].(float64); ok {
+		limits = append(limits, fmt.Sprintf("at least %v", minimum))
+	}
+	if maximum, ok := property["maximum"].(float64); ok {
+		limits = append(limits, fmt.Sprintf("at most %v", maximum))
+	}
+	if minLength, _ := property["minLength"].(int); minLength > 0 {
+		kind = "non-empty " + kind
+	}
+	if maxLength, ok := property["maxLength"].(int); ok {
+		limits = append(limits, fmt.Sprintf("at most %d characters", maxLength))
+	}
+	if len(limits) == 0 {
+		return kind
+	}
+	return kind + " of " + strings.Join(limits, " and ")
+}
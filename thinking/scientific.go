@@ -0,0 +1,129 @@
+package thinking
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	StageObservation = "observation"
+	StageHypothesis  = "hypothesis"
+	StageExperiment  = "experiment"
+	StageResult      = "result"
+	StageConclusion  = "conclusion"
+)
+
+var inquiryStages = []string{StageObservation, StageHypothesis, StageExperiment, StageResult, StageConclusion}
+
+// nextStages lists the stages allowed after the given one. A stage may be
+// repeated, a result may send the cycle back to a new hypothesis, and a
+// conclusion ends the inquiry.
+func nextStages(stage string) []string {
+	switch stage {
+	case "":
+		return []string{StageObservation}
+	case StageObservation:
+		return []string{StageObservation, StageHypothesis}
+	case StageHypothesis:
+		return []string{StageHypothesis, StageExperiment}
+	case StageExperiment:
+		return []string{StageExperiment, StageResult}
+	case StageResult:
+		return []string{StageResult, StageHypothesis, StageConclusion}
+	}
+	return nil
+}
+
+// InquiryStep is one stage of a scientific-method inquiry.
+type InquiryStep struct {
+	Stage         string `json:"stage"`
+	Content       string `json:"content"`
+	ThoughtNumber int    `json:"thoughtNumber,omitempty"`
+}
+
+// Inquiry is an observation → hypothesis → experiment → result → conclusion
+// cycle whose steps are checked to come in order.
+type Inquiry struct {
+	ID    string        `json:"id"`
+	Steps []InquiryStep `json:"steps"`
+}
+
+func (q Inquiry) clone() Inquiry {
+	q.Steps = slices.Clone(q.Steps)
+	return q
+}
+
+func (q Inquiry) stage() string {
+	if len(q.Steps) == 0 {
+		return ""
+	}
+	return q.Steps[len(q.Steps)-1].Stage
+}
+
+func (s *SequentialThinkingServer) findInquiry(id string) *Inquiry {
+	for i := range s.inquiries {
+		if s.inquiries[i].ID == id {
+			return &s.inquiries[i]
+		}
+	}
+	return nil
+}
+
+func (s *SequentialThinkingServer) scientificMethod(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	stage, err := request.RequireString("stage")
+	if err != nil || !slices.Contains(inquiryStages, stage) {
+		return s.fail(ctx, request, invalid("stage", "one of "+strings.Join(inquiryStages, ", "), request.GetArguments()["stage"], "must be one of %s", strings.Join(inquiryStages, ", ")))
+	}
+	content, err := request.RequireString("content")
+	if err != nil || content == "" {
+		return s.fail(ctx, request, invalid("content", "non-empty string", request.GetArguments()["content"], "must be a non-empty string"))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n, err := s.optionalThought(request, "thoughtNumber")
+	if err != nil {
+		return s.fail(ctx, request, err)
+	}
+	if n == 0 && len(s.thoughtHistory) > 0 {
+		n = s.thoughtHistory[len(s.thoughtHistory)-1].ThoughtNumber
+	}
+
+	q := &Inquiry{ID: fmt.Sprintf("S%d", s.inquirySeq+1), Steps: make([]InquiryStep, 0)}
+	if id := request.GetString("inquiryId", ""); id != "" {
+		if q = s.findInquiry(id); q == nil {
+			return s.fail(ctx, request, invalid("inquiryId", "recorded inquiry ID", id, "unknown inquiry %q", id))
+		}
+	}
+	allowed := nextStages(q.stage())
+	switch {
+	case q.stage() == "" && stage != StageObservation:
+		return s.fail(ctx, request, invalid("stage", StageObservation, stage, "a new inquiry must start with an observation"))
+	case q.stage() == StageConclusion:
+		return s.fail(ctx, request, invalid("inquiryId", "ID of an inquiry that isn't concluded", q.ID, "inquiry %s is already concluded", q.ID))
+	case !slices.Contains(allowed, stage):
+		return s.fail(ctx, request, invalid("stage", strings.Join(allowed, " or "), stage,
+			"%s cannot follow %s in inquiry %s, expected %s", stage, q.stage(), q.ID, strings.Join(allowed, " or ")))
+	}
+
+	q.Steps = append(q.Steps, InquiryStep{Stage: stage, Content: content, ThoughtNumber: n})
+	if len(q.Steps) == 1 {
+		s.inquirySeq++
+		s.inquiries = append(s.inquiries, *q)
+		q = &s.inquiries[len(s.inquiries)-1]
+	}
+
+	if !s.disableThoughtLogging {
+		s.logEvent(s.theme.Record, fmt.Sprintf("🧪 Inquiry %s %s:", q.ID, stage), content)
+	}
+
+	return s.respond(ctx, request, map[string]any{
+		"inquiry":    q,
+		"nextStages": append(make([]string, 0), nextStages(stage)...),
+	})
+}
@@ -0,0 +1,114 @@
+package thinking
+
+import (
+	"context"
+	"regexp"
+	"slices"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	defaultSearchLimit = 20
+	snippetContext     = 40
+)
+
+// SearchMatch is a thought matching a search_thoughts query.
+type SearchMatch struct {
+	ThoughtNumber int    `json:"thoughtNumber"`
+	BranchId      string `json:"branchId,omitempty"`
+	IsRevision    bool   `json:"isRevision,omitempty"`
+	Version       int    `json:"version,omitempty"`
+	Author        string `json:"author,omitempty"`
+	Snippet       string `json:"snippet"`
+}
+
+// snippet cuts the text around the byte range [start, end) with a little
+// context on both sides.
+func snippet(text string, start, end int) string {
+	before := []rune(text[:start])
+	after := []rune(text[end:])
+	prefix, suffix := "", ""
+	if len(before) > snippetContext {
+		before = before[len(before)-snippetContext:]
+		prefix = "…"
+	}
+	if len(after) > snippetContext {
+		after = after[:snippetContext]
+		suffix = "…"
+	}
+	return prefix + string(before) + text[start:end] + string(after) + suffix
+}
+
+func (s *SequentialThinkingServer) searchThoughts(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	query, err := request.RequireString("query")
+	if err != nil || query == "" {
+		return s.fail(ctx, request, invalid("query", "non-empty string", request.GetArguments()["query"], "must be a non-empty string"))
+	}
+
+	pattern := query
+	if !request.GetBool("regex", false) {
+		pattern = regexp.QuoteMeta(query)
+	}
+	if !request.GetBool("caseSensitive", false) {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return s.fail(ctx, request, invalid("query", "valid regular expression", query, "%v", err))
+	}
+
+	branchId, filterBranch := request.GetArguments()["branchId"].(string)
+	revisionsOnly := request.GetBool("revisionsOnly", false)
+	allVersions := request.GetBool("allVersions", false)
+	author, filterAuthor := request.GetArguments()["author"].(string)
+	tags := normalizeTags(request.GetStringSlice("tags", nil))
+	from := request.GetInt("fromThought", 0)
+	to := request.GetInt("toThought", 0)
+	limit := request.GetInt("limit", defaultSearchLimit)
+	if limit < 1 {
+		return s.fail(ctx, request, invalid("limit", "integer of at least 1", limit, "must be at least 1"))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	superseded := s.superseded()
+	matches := make([]SearchMatch, 0)
+	total := 0
+	for i := range s.thoughtHistory {
+		t := &s.thoughtHistory[i]
+		isRevision := t.IsRevision != nil && *t.IsRevision
+		switch {
+		case filterBranch && branchOf(t) != branchId,
+			revisionsOnly && !isRevision,
+			!allVersions && superseded(i),
+			filterAuthor && t.Author != author,
+			len(tags) > 0 && !slices.ContainsFunc(t.Tags, func(tag string) bool { return slices.Contains(tags, tag) }),
+			from > 0 && t.ThoughtNumber < from,
+			to > 0 && t.ThoughtNumber > to:
+			continue
+		}
+		loc := re.FindStringIndex(t.Thought)
+		if loc == nil {
+			continue
+		}
+		total++
+		if len(matches) < limit {
+			matches = append(matches, SearchMatch{
+				ThoughtNumber: t.ThoughtNumber,
+				BranchId:      branchOf(t),
+				IsRevision:    isRevision,
+				Version:       t.Version,
+				Author:        t.Author,
+				Snippet:       snippet(t.Thought, loc[0], loc[1]),
+			})
+		}
+	}
+
+	return s.respond(ctx, request, map[string]any{
+		"matches":   matches,
+		"total":     total,
+		"truncated": total > len(matches),
+	})
+}
@@ -0,0 +1,35 @@
+package thinking
+
+import "fmt"
+
+// checkGap warns when a thought skips numbers on its line: when it follows
+// the latest thought there (or, starting a branch, its branching point) by
+// more than one.
+func (s *SequentialThinkingServer) checkGap(data *ThoughtData) string {
+	if data.MergedBranchId != nil {
+		return ""
+	}
+	line := branchOf(data)
+	previous := 0
+	if line != "" {
+		previous = *data.BranchFromThought
+	}
+	for _, t := range s.thoughtHistory {
+		if branchOf(&t) == line {
+			previous = max(previous, t.ThoughtNumber)
+		}
+	}
+
+	n := data.ThoughtNumber
+	if n <= previous+1 {
+		return ""
+	}
+	missing := fmt.Sprintf("thought %d is", previous+1)
+	if n > previous+2 {
+		missing = fmt.Sprintf("thoughts %d-%d are", previous+1, n-1)
+	}
+	if previous == 0 {
+		return fmt.Sprintf("%s starts at thought %d, so %s missing", describeScope(line), n, missing)
+	}
+	return fmt.Sprintf("thought %d follows thought %d in %s, so %s missing", n, previous, describeScope(line), missing)
+}
@@ -0,0 +1,515 @@
+package thinking
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// SequentialThinkingServer holds the state of a thinking session and serves
+// the tools that operate on it.
+type SequentialThinkingServer struct {
+	mu                    sync.Mutex
+	thoughtHistory        []ThoughtData
+	branches              map[string]*Branch
+	disableThoughtLogging bool
+	finalAnswer           *FinalAnswer
+	hypotheses            []Hypothesis
+	hypothesisSeq         int
+	assumptions           []Assumption
+	assumptionSeq         int
+	questions             []Question
+	questionSeq           int
+	decisions             []DecisionMatrix
+	decisionSeq           int
+	modelApplications     []ModelApplication
+	modelSeq              int
+	debugSessions         []DebugSession
+	debugSeq              int
+	inquiries             []Inquiry
+	inquirySeq            int
+	arguments             []ArgumentNode
+	argumentSeq           int
+	assessments           []KnowledgeAssessment
+	reviews               []Review
+	reviewSeq             int
+	reviewEvery           int
+	disabledTools         map[string]bool
+	sessionID             string
+	generation            int
+	echoRecent            int
+	maxLogWidth           int
+	maxThoughts           int
+	maxTotalIncrease      int
+	maxThoughtLength      int
+	maxHistory            int
+	requireFinalAnswer    bool
+	longThoughts          LengthMode
+	tokenizer             TokenHeuristic
+	theme                 Theme
+	logFormat             LogFormat
+	duplicates            DuplicateMode
+	validation            ValidationMode
+	calls                 []call
+	exporters             []Exporter
+	checkpoints           map[string]snapshot
+	exportDir             string
+	transformers          []ResultTransformer
+	srv                   *server.MCPServer
+	subMu                 sync.Mutex
+	subscribed            map[string]bool
+	watchers              map[chan struct{}]bool
+	held                  *heldChanges
+}
+
+// Option configures a SequentialThinkingServer.
+type Option func(*SequentialThinkingServer)
+
+// Result is a tool result on its way back to the client. Fields is returned
+// as structured content and rendered as the JSON text of the result; error
+// results carry their message in the "error" field.
+type Result struct {
+	Tool    string
+	IsError bool
+	Fields  map[string]any
+}
+
+// ResultTransformer post-processes every tool result before it is returned,
+// so embedders can add, strip, or rewrite fields without forking. It runs
+// while the server state is locked and must not call back into the server.
+// A returned error is reported to the client as a tool error.
+type ResultTransformer func(ctx context.Context, result *Result) error
+
+// WithResultTransformer appends t to the transformers applied to tool
+// results, in registration order.
+func WithResultTransformer(t ResultTransformer) Option {
+	return func(s *SequentialThinkingServer) {
+		s.transformers = append(s.transformers, t)
+	}
+}
+
+// WithExportDir sets the directory export_session writes bundles into,
+// overriding GOTHINK_EXPORT_DIR. An empty dir disables the tool.
+func WithExportDir(dir string) Option {
+	return func(s *SequentialThinkingServer) {
+		s.exportDir = dir
+	}
+}
+
+// NewSequentialThinkingServer returns a server with an empty history.
+func NewSequentialThinkingServer(opts ...Option) *SequentialThinkingServer {
+	s := &SequentialThinkingServer{
+		thoughtHistory:        make([]ThoughtData, 0),
+		branches:              make(map[string]*Branch),
+		checkpoints:           make(map[string]snapshot),
+		subscribed:            make(map[string]bool),
+		sessionID:             newSessionID(),
+		reviewEvery:           reviewEveryFromEnv(),
+		echoRecent:            echoRecentFromEnv(),
+		maxLogWidth:           logWidthFromEnv(),
+		maxThoughts:           maxThoughtsFromEnv(),
+		maxTotalIncrease:      maxTotalIncreaseFromEnv(),
+		maxThoughtLength:      maxThoughtLengthFromEnv(),
+		maxHistory:            maxHistoryFromEnv(),
+		requireFinalAnswer:    requireFinalAnswerFromEnv(),
+		longThoughts:          lengthModeFromEnv(),
+		tokenizer:             tokenHeuristicFromEnv(),
+		theme:                 ThemeFromEnv(os.Stderr),
+		logFormat:             logFormatFromEnv(),
+		duplicates:            duplicateModeFromEnv(),
+		validation:            validationModeFromEnv(),
+		exportDir:             os.Getenv("GOTHINK_EXPORT_DIR"),
+		disableThoughtLogging: strings.ToLower(os.Getenv("DISABLE_THOUGHT_LOGGING")) == "true",
+	}
+	for _, e := range builtinExporters {
+		s.exporters = append(s.exporters, e)
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// respond returns v, a map or a JSON-serializable struct, as the text of a
+// tool result. v is normalized to plain JSON values first, so transformers
+// never see the server's internal types. Open questions, open uncertainties
+// and low-confidence topics ride along with every result.
+func (s *SequentialThinkingServer) respond(ctx context.Context, request mcp.CallToolRequest, v any) (*mcp.CallToolResult, error) {
+	jsonBytes, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(jsonBytes, &fields); err != nil {
+		return nil, err
+	}
+	if open := s.openQuestions(); len(open) > 0 {
+		fields["unansweredQuestions"] = open
+	}
+	if open := s.openUncertainties(); len(open) > 0 {
+		fields["openUncertainties"] = open
+	}
+	if low := s.lowConfidenceTopics(); len(low) > 0 {
+		fields["lowConfidenceTopics"] = low
+	}
+	return s.finish(ctx, &Result{Tool: request.Params.Name, Fields: fields})
+}
+
+// fail returns err as a tool error result.
+func (s *SequentialThinkingServer) fail(ctx context.Context, request mcp.CallToolRequest, err error) (*mcp.CallToolResult, error) {
+	return s.finish(ctx, &Result{
+		Tool:    request.Params.Name,
+		IsError: true,
+		Fields:  errorFields(err),
+	})
+}
+
+func (s *SequentialThinkingServer) finish(ctx context.Context, result *Result) (*mcp.CallToolResult, error) {
+	for _, t := range s.transformers {
+		if err := t(ctx, result); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("result transformer: %v", err)), nil
+		}
+	}
+
+	if result.IsError {
+		if len(result.Fields) == 1 {
+			if msg, ok := result.Fields["error"].(string); ok {
+				return mcp.NewToolResultError(msg), nil
+			}
+		}
+		jsonBytes, _ := json.MarshalIndent(result.Fields, "", "  ")
+		return mcp.NewToolResultError(string(jsonBytes)), nil
+	}
+
+	jsonBytes, _ := json.MarshalIndent(result.Fields, "", "  ")
+	return mcp.NewToolResultStructured(result.Fields, string(jsonBytes)), nil
+}
+
+// branchNames lists the IDs of all branches that weren't abandoned, in
+// sorted order.
+func (s *SequentialThinkingServer) branchNames() []string {
+	branches := make([]string, 0, len(s.branches))
+	for k, b := range s.branches {
+		if b.AbandonReason == nil {
+			branches = append(branches, k)
+		}
+	}
+	sort.Strings(branches)
+	return branches
+}
+
+// record appends an accepted thought to the history and its branch, notifies
+// subscribers, and logs it.
+func (s *SequentialThinkingServer) record(ctx context.Context, data *ThoughtData) {
+	branched := s.store(data)
+	s.announce(ctx, data, branched)
+}
+
+// store appends an accepted thought to the history and its branch, and
+// reports whether it started the branch. Thoughts loaded with a receive time
+// keep it. A main-line thought reopens the chain and drops the final answer.
+func (s *SequentialThinkingServer) store(data *ThoughtData) bool {
+	if data.ReceivedAt == nil {
+		now := time.Now()
+		data.ReceivedAt = &now
+	}
+	if data.Id == "" {
+		data.Id = newULID(*data.ReceivedAt)
+	}
+	if data.Status == "" {
+		data.Status = ThoughtOpen
+	}
+	data.Version = s.nextVersion(data)
+	data.Tokens = s.tokenizer.estimate(data.Thought)
+	if data.RevisesThought != nil && data.RevisesBranchId != nil {
+		if i := s.indexBefore(*data.RevisesBranchId, *data.RevisesThought, len(s.thoughtHistory)); i >= 0 {
+			s.setStatus(s.thoughtHistory[i].Id, ThoughtRetracted)
+		}
+	}
+	s.thoughtHistory = append(s.thoughtHistory, *data)
+
+	if branchOf(data) == "" {
+		s.finalAnswer = nil
+	}
+	if data.IsFinalAnswer != nil && *data.IsFinalAnswer {
+		s.finalAnswer = &FinalAnswer{
+			Answer:        data.Thought,
+			ThoughtNumber: data.ThoughtNumber,
+			ThoughtId:     data.Id,
+			RecordedAt:    data.ReceivedAt.UTC(),
+		}
+	}
+
+	branched := false
+	if data.BranchFromThought != nil && data.BranchId != nil {
+		branchId := *data.BranchId
+		if s.branches[branchId] == nil {
+			s.branches[branchId] = &Branch{
+				ID:                branchId,
+				BranchFromThought: *data.BranchFromThought,
+				CreatedAt:         data.ReceivedAt,
+				Status:            BranchOpen,
+			}
+			branched = true
+		}
+		s.branches[branchId].Thoughts = append(s.branches[branchId].Thoughts, *data)
+	}
+	return branched
+}
+
+// announce notifies subscribers of a stored thought and logs it, along with
+// the tree if the thought started a branch or merged one.
+func (s *SequentialThinkingServer) announce(ctx context.Context, data *ThoughtData, branched bool) {
+	s.resourcesChanged(branchOf(data))
+
+	if !s.disableThoughtLogging {
+		s.logThought(ctx, data)
+		if branched || data.MergedBranchId != nil {
+			s.logTree()
+		}
+	}
+}
+
+// accept checks a validated thought against the session and fills in what
+// the server derives: the revised line and a total that covers the thought.
+// It returns warnings about what it changed or noticed.
+func (s *SequentialThinkingServer) accept(data *ThoughtData) ([]string, error) {
+	if err := s.checkCapacity(1); err != nil {
+		return nil, err
+	}
+	if err := s.checkBounds(data); err != nil {
+		return nil, err
+	}
+	var warnings []string
+	if warning, err := s.checkLength(data); err != nil {
+		return nil, err
+	} else if warning != "" {
+		warnings = append(warnings, warning)
+	}
+	// In lenient mode, a revision that can't be one is recorded as a plain
+	// thought.
+	dropRevision := func(err error) error {
+		if s.validation != ValidationLenient {
+			return err
+		}
+		data.IsRevision, data.RevisesThought, data.RevisesBranchId, data.RevisesThoughtId = nil, nil, nil, nil
+		warnings = append(warnings, fmt.Sprintf("%v; recorded as a plain thought", err))
+		return nil
+	}
+	if err := s.resolveRevisionID(data); err != nil {
+		if err := dropRevision(err); err != nil {
+			return nil, err
+		}
+	}
+	if err := checkRevisionFields(data); err != nil {
+		if err := dropRevision(err); err != nil {
+			return nil, err
+		}
+	}
+	if err := checkBranchFields(data); err != nil {
+		return nil, err
+	}
+	if err := s.checkBranchPoint(data); err != nil {
+		if s.validation != ValidationLenient {
+			return nil, err
+		}
+		warnings = append(warnings, fmt.Sprintf("%v (accepted in lenient mode)", err))
+	}
+	if _, ok := s.abandonReason(branchOf(data)); ok {
+		return nil, invalid("branchId", "branch that wasn't abandoned", *data.BranchId, "branch %q was abandoned", *data.BranchId)
+	}
+	if into, ok := s.mergedInto(branchOf(data)); ok {
+		return nil, invalid("branchId", "branch that wasn't merged", *data.BranchId,
+			"branch %q was merged into %s; go on there or in a new branch", *data.BranchId, describeScope(into))
+	}
+	if err := s.checkFinalAnswer(data); err != nil {
+		return nil, err
+	}
+
+	if warning, err := s.checkDuplicate(data); err != nil {
+		return nil, err
+	} else if warning != "" {
+		warnings = append(warnings, warning)
+	}
+
+	if warning := s.checkGap(data); warning != "" {
+		warnings = append(warnings, warning)
+	}
+
+	if err := s.resolveRevisionTarget(data); err != nil {
+		if err := dropRevision(err); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.resolveParent(data); err != nil {
+		if s.validation != ValidationLenient {
+			return nil, err
+		}
+		data.ParentThought = nil
+		warnings = append(warnings, fmt.Sprintf("%v; recorded after the previous thought of its line", err))
+	}
+	if err := s.resolveDependencies(data); err != nil {
+		if s.validation != ValidationLenient {
+			return nil, err
+		}
+		data.DependsOn, data.DependencyIds = nil, nil
+		warnings = append(warnings, fmt.Sprintf("%v; recorded without dependencies", err))
+	}
+	if err := s.resolveUncertainties(data); err != nil {
+		if s.validation != ValidationLenient {
+			return nil, err
+		}
+		data.ResolvesUncertainties = nil
+		warnings = append(warnings, fmt.Sprintf("%v; recorded without resolving uncertainties", err))
+	}
+	if warning := s.dependentsWarning(data); warning != "" {
+		warnings = append(warnings, warning)
+	}
+
+	if warning := s.capTotal(data); warning != "" {
+		warnings = append(warnings, warning)
+	}
+	if data.ThoughtNumber > data.TotalThoughts {
+		data.TotalThoughts = data.ThoughtNumber
+	}
+	return warnings, nil
+}
+
+// logWarnings logs warnings about an accepted thought.
+func (s *SequentialThinkingServer) logWarnings(warnings []string) {
+	if s.disableThoughtLogging {
+		return
+	}
+	for _, w := range warnings {
+		s.logEvent(s.theme.Alert, "⚠️  Warning:", w)
+	}
+}
+
+func (s *SequentialThinkingServer) processThought(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := s.elicitReferences(ctx, request.GetArguments())
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if previous, err := s.replay(request.GetArguments()); err != nil {
+		return s.fail(ctx, request, err)
+	} else if previous != nil {
+		return s.respond(ctx, request, previous)
+	}
+	args, notes, err := s.screen(args)
+	if err != nil {
+		return s.fail(ctx, request, err)
+	}
+	validatedInput, err := s.validateThoughtData(args)
+	if err != nil {
+		return s.fail(ctx, request, err)
+	}
+	echo, err := s.echoCount(args)
+	if err != nil {
+		return s.fail(ctx, request, err)
+	}
+
+	warnings, err := s.accept(validatedInput)
+	if err != nil {
+		return s.fail(ctx, request, err)
+	}
+	warnings = append(notes, warnings...)
+
+	s.record(ctx, validatedInput)
+	s.logWarnings(warnings)
+	s.reportProgress(ctx, request, validatedInput)
+	if s.reviewDue(ctx) {
+		s.startReview(ctx, validatedInput.ThoughtNumber)
+	}
+
+	result := map[string]any{
+		"thoughtNumber":        validatedInput.ThoughtNumber,
+		"totalThoughts":        validatedInput.TotalThoughts,
+		"nextThoughtNeeded":    validatedInput.NextThoughtNeeded,
+		"branches":             s.branchNames(),
+		"thoughtHistoryLength": len(s.thoughtHistory),
+		"id":                   validatedInput.Id,
+		"receivedAt":           validatedInput.ReceivedAt,
+		"tokens":               validatedInput.Tokens,
+		"sessionTokens":        s.sessionTokens(),
+	}
+	if echo > 0 {
+		result["recentThoughts"] = s.recentThoughts(echo)
+	}
+	if !validatedInput.NextThoughtNeeded {
+		warnings = append(warnings, s.conclusionWarnings()...)
+	}
+	if len(warnings) > 0 {
+		result["warnings"] = warnings
+	}
+	s.remember(request.GetArguments(), result)
+
+	return s.respond(ctx, request, result)
+}
+
+func (s *SequentialThinkingServer) clearHistory(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := map[string]any{
+		"cleared":         true,
+		"thoughtsCleared": len(s.thoughtHistory),
+		"branchesCleared": len(s.branches),
+	}
+
+	s.reset()
+	s.resourcesChanged()
+
+	if !s.disableThoughtLogging {
+		s.logEvent(s.theme.Alert, "🧹 History cleared", "")
+	}
+
+	return s.respond(ctx, request, result)
+}
+
+// newSessionID returns a random ID for a fresh session.
+func newSessionID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// reset empties the session and gives it a new ID. Checkpoints survive.
+func (s *SequentialThinkingServer) reset() {
+	s.sessionID = newSessionID()
+	s.generation++
+	s.thoughtHistory = make([]ThoughtData, 0)
+	s.branches = make(map[string]*Branch)
+	s.finalAnswer = nil
+	s.hypotheses = nil
+	s.hypothesisSeq = 0
+	s.assumptions = nil
+	s.assumptionSeq = 0
+	s.questions = nil
+	s.questionSeq = 0
+	s.decisions = nil
+	s.decisionSeq = 0
+	s.modelApplications = nil
+	s.modelSeq = 0
+	s.debugSessions = nil
+	s.debugSeq = 0
+	s.inquiries = nil
+	s.inquirySeq = 0
+	s.arguments = nil
+	s.argumentSeq = 0
+	s.assessments = nil
+	s.reviews = nil
+	s.reviewSeq = 0
+	s.calls = nil
+}
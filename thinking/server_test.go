@@ -0,0 +1,71 @@
+package thinking
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+type handler func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error)
+
+// newTestServer returns a server that doesn't log, whatever the environment.
+func newTestServer(t *testing.T, opts ...Option) *SequentialThinkingServer {
+	t.Helper()
+	s := NewSequentialThinkingServer(append([]Option{WithValidation(ValidationStandard)}, opts...)...)
+	s.disableThoughtLogging = true
+	return s
+}
+
+// callTool runs a tool handler with args and returns the fields of its result,
+// and whether it is an error.
+func callTool(t *testing.T, h handler, args map[string]any) (map[string]any, bool) {
+	t.Helper()
+	var request mcp.CallToolRequest
+	request.Params.Arguments = args
+	result, err := h(context.Background(), request)
+	if err != nil {
+		t.Fatalf("handler: %v", err)
+	}
+	text := result.Content[0].(mcp.TextContent).Text
+	fields := make(map[string]any)
+	if err := json.Unmarshal([]byte(text), &fields); err != nil {
+		fields["error"] = text
+	}
+	return fields, result.IsError
+}
+
+// mustCall is callTool for a call that must succeed.
+func mustCall(t *testing.T, h handler, args map[string]any) map[string]any {
+	t.Helper()
+	fields, isError := callTool(t, h, args)
+	if isError {
+		t.Fatalf("unexpected error: %v", fields["error"])
+	}
+	return fields
+}
+
+// thought returns sequentialthinking arguments for a thought, with extra
+// arguments added.
+func thought(n, total int, text string, extra map[string]any) map[string]any {
+	args := map[string]any{
+		"thought":           text,
+		"thoughtNumber":     float64(n),
+		"totalThoughts":     float64(total),
+		"nextThoughtNeeded": n < total,
+	}
+	for k, v := range extra {
+		args[k] = v
+	}
+	return args
+}
+
+// numbers lists the numbers of the thoughts in the history, in order.
+func numbers(s *SequentialThinkingServer) []int {
+	ns := make([]int, len(s.thoughtHistory))
+	for i, t := range s.thoughtHistory {
+		ns[i] = t.ThoughtNumber
+	}
+	return ns
+}
@@ -0,0 +1,200 @@
+package thinking
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// SessionFormat identifies session documents.
+	SessionFormat = "gothink-session"
+	// SessionVersion is the version of the session document format written
+	// by this server. Documents with a newer version are rejected.
+	SessionVersion = 1
+)
+
+// SessionDocument is the canonical, versioned JSON form of a full session.
+// Thoughts are listed in the order they were recorded, on every line; the
+// branches are rebuilt from them on import.
+type SessionDocument struct {
+	Format        string                `json:"format"`
+	Version       int                   `json:"version"`
+	SessionID     string                `json:"sessionId,omitempty"`
+	ExportedAt    time.Time             `json:"exportedAt"`
+	Thoughts      []ThoughtData         `json:"thoughts"`
+	Branches      []Branch              `json:"branches"`
+	FinalAnswer   *FinalAnswer          `json:"finalAnswer,omitempty"`
+	Hypotheses    []Hypothesis          `json:"hypotheses,omitempty"`
+	Assumptions   []Assumption          `json:"assumptions,omitempty"`
+	Questions     []Question            `json:"questions,omitempty"`
+	Decisions     []DecisionMatrix      `json:"decisions,omitempty"`
+	MentalModels  []ModelApplication    `json:"mentalModels,omitempty"`
+	DebugSessions []DebugSession        `json:"debugSessions,omitempty"`
+	Inquiries     []Inquiry             `json:"inquiries,omitempty"`
+	Arguments     []ArgumentNode        `json:"arguments,omitempty"`
+	Assessments   []KnowledgeAssessment `json:"assessments,omitempty"`
+	Reviews       []Review              `json:"reviews,omitempty"`
+}
+
+// ExportSession returns the session as a document that ImportSession, here
+// or in another gothink instance, can load.
+func (s *SequentialThinkingServer) ExportSession() *SessionDocument {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.sessionDocument()
+}
+
+func (s *SequentialThinkingServer) sessionDocument() *SessionDocument {
+	snap := s.snapshot()
+	doc := &SessionDocument{
+		Format:        SessionFormat,
+		Version:       SessionVersion,
+		SessionID:     s.sessionID,
+		ExportedAt:    time.Now().UTC(),
+		Thoughts:      snap.thoughtHistory,
+		Branches:      make([]Branch, 0, len(s.branches)),
+		FinalAnswer:   snap.finalAnswer,
+		Hypotheses:    snap.hypotheses,
+		Assumptions:   snap.assumptions,
+		Questions:     snap.questions,
+		Decisions:     snap.decisions,
+		MentalModels:  snap.models,
+		DebugSessions: snap.debugSessions,
+		Inquiries:     snap.inquiries,
+		Arguments:     snap.arguments,
+		Assessments:   snap.assessments,
+		Reviews:       snap.reviews,
+	}
+	for _, id := range s.allBranches() {
+		doc.Branches = append(doc.Branches, *snap.branches[id])
+	}
+	return doc
+}
+
+// ImportSession replaces the session with the one in doc. Thoughts are
+// checked as import_thoughts checks them; if any is rejected, the session is
+// left unchanged.
+func (s *SequentialThinkingServer) ImportSession(doc *SessionDocument) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.replaceSession(context.Background(), doc)
+}
+
+func (s *SequentialThinkingServer) replaceSession(ctx context.Context, doc *SessionDocument) error {
+	switch {
+	case doc.Format != SessionFormat:
+		return invalid("document", fmt.Sprintf("document of format %q", SessionFormat), doc.Format,
+			"expected format %q, got %q", SessionFormat, doc.Format)
+	case doc.Version < 1 || doc.Version > SessionVersion:
+		return fmt.Errorf("unsupported version %d: this server reads versions up to %d", doc.Version, SessionVersion)
+	}
+
+	before, calls := s.snapshot(), s.calls
+	s.reset()
+	logging := s.disableThoughtLogging
+	s.disableThoughtLogging = true
+	defer func() { s.disableThoughtLogging = logging }()
+
+	for i := range doc.Thoughts {
+		data := doc.Thoughts[i]
+		_, err := s.accept(&data)
+		if err == nil {
+			err = s.checkImported(&data)
+		}
+		if err != nil {
+			s.restore(before)
+			s.calls = calls
+			return fmt.Errorf("thoughts[%d]: %w", i, err)
+		}
+		s.record(ctx, &data)
+	}
+
+	snap := s.snapshot()
+	for _, b := range doc.Branches {
+		if s.branches[b.ID] == nil || s.branchOrigin(b.ID) != b.BranchFromThought {
+			s.restore(before)
+			s.calls = calls
+			return invalid("document", "branches that match their thoughts", b.ID, "branch %q does not match its thoughts", b.ID)
+		}
+		branch := snap.branches[b.ID]
+		branch.Description, branch.MergedInto, branch.AbandonReason = b.Description, b.MergedInto, b.AbandonReason
+		if b.CreatedAt != nil {
+			branch.CreatedAt = b.CreatedAt
+		}
+		switch {
+		case b.AbandonReason != nil:
+			branch.Status = BranchAbandoned
+		case b.MergedInto != nil:
+			branch.Status = BranchMerged
+		}
+	}
+	snap.finalAnswer = doc.FinalAnswer
+	snap.hypotheses, snap.hypothesisSeq = doc.Hypotheses, len(doc.Hypotheses)
+	snap.assumptions, snap.assumptionSeq = doc.Assumptions, len(doc.Assumptions)
+	snap.questions, snap.questionSeq = doc.Questions, len(doc.Questions)
+	snap.decisions, snap.decisionSeq = doc.Decisions, len(doc.Decisions)
+	snap.models, snap.modelSeq = doc.MentalModels, len(doc.MentalModels)
+	snap.debugSessions, snap.debugSeq = doc.DebugSessions, len(doc.DebugSessions)
+	snap.inquiries, snap.inquirySeq = doc.Inquiries, len(doc.Inquiries)
+	snap.arguments, snap.argumentSeq = doc.Arguments, len(doc.Arguments)
+	snap.assessments = doc.Assessments
+	snap.reviews, snap.reviewSeq = doc.Reviews, len(doc.Reviews)
+	if doc.SessionID != "" {
+		snap.sessionID = doc.SessionID
+	}
+	s.restore(snap)
+	s.resourcesChanged()
+
+	if !logging {
+		s.logEvent(s.theme.Record, fmt.Sprintf("📥 Loaded session with %d thoughts", len(s.thoughtHistory)), "")
+	}
+	return nil
+}
+
+// ReadSessionFile loads a session document from path. Files without a
+// format are read as history dumps of the reference TypeScript server.
+func ReadSessionFile(path string) (*SessionDocument, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var doc SessionDocument
+	if err := json.Unmarshal(data, &doc); err != nil || doc.Format == "" {
+		ts, tsErr := typeScriptSession(data)
+		if tsErr != nil {
+			return nil, fmt.Errorf("%s: neither a session document nor a TypeScript server dump: %w", path, tsErr)
+		}
+		return ts, nil
+	}
+	return &doc, nil
+}
+
+func (s *SequentialThinkingServer) loadSession(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	document, err := request.RequireString("document")
+	if err != nil || document == "" {
+		return s.fail(ctx, request, invalid("document", "non-empty string", request.GetArguments()["document"], "must be a non-empty string"))
+	}
+	var doc SessionDocument
+	if err := json.Unmarshal([]byte(document), &doc); err != nil {
+		return s.fail(ctx, request, invalid("document", "session document JSON", nil, "%v", err))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.replaceSession(ctx, &doc); err != nil {
+		return s.fail(ctx, request, err)
+	}
+	return s.respond(ctx, request, map[string]any{
+		"sessionId":            s.sessionID,
+		"branches":             s.branchNames(),
+		"thoughtHistoryLength": len(s.thoughtHistory),
+	})
+}
@@ -0,0 +1,116 @@
+package thinking
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestSessionRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		build func(t *testing.T, s *SequentialThinkingServer)
+	}{
+		{
+			name:  "empty",
+			build: func(t *testing.T, s *SequentialThinkingServer) {},
+		},
+		{
+			name: "main line",
+			build: func(t *testing.T, s *SequentialThinkingServer) {
+				mustCall(t, s.processThought, thought(1, 3, "a", map[string]any{"tags": []any{"plan"}, "importance": 0.9}))
+				mustCall(t, s.processThought, thought(2, 3, "b", map[string]any{"uncertainties": []any{"is b right?"}}))
+				mustCall(t, s.processThought, thought(3, 3, "b is right", map[string]any{"resolvesUncertainties": []any{"is b right?"}, "isFinalAnswer": true}))
+			},
+		},
+		{
+			name: "branches",
+			build: func(t *testing.T, s *SequentialThinkingServer) {
+				mustCall(t, s.processThought, thought(1, 3, "a", nil))
+				mustCall(t, s.processThought, thought(2, 3, "b", map[string]any{"branchId": "alt", "branchFromThought": 1.0}))
+				mustCall(t, s.processThought, thought(2, 3, "c", map[string]any{"branchId": "dead", "branchFromThought": 1.0}))
+				mustCall(t, s.describeBranch, map[string]any{"branchId": "alt", "description": "the other way"})
+				mustCall(t, s.abandonBranch, map[string]any{"branchId": "dead", "reason": "went nowhere"})
+				mustCall(t, s.mergeBranches, map[string]any{"branchId": "alt"})
+			},
+		},
+		{
+			name: "companions",
+			build: func(t *testing.T, s *SequentialThinkingServer) {
+				mustCall(t, s.processThought, thought(1, 2, "a", nil))
+				mustCall(t, s.recordHypothesis, map[string]any{"statement": "a holds"})
+				mustCall(t, s.recordAssumption, map[string]any{"statement": "inputs are sane"})
+				mustCall(t, s.raiseQuestion, map[string]any{"question": "why a?"})
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t)
+			tt.build(t, s)
+			want := s.ExportSession()
+
+			jsonBytes, err := json.Marshal(want)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var doc SessionDocument
+			if err := json.Unmarshal(jsonBytes, &doc); err != nil {
+				t.Fatal(err)
+			}
+			loaded := newTestServer(t)
+			if err := loaded.ImportSession(&doc); err != nil {
+				t.Fatalf("ImportSession: %v", err)
+			}
+			got := loaded.ExportSession()
+
+			if g, w := sessionJSON(t, got), sessionJSON(t, want); g != w {
+				t.Errorf("round trip changed the session:\ngot  %s\nwant %s", g, w)
+			}
+		})
+	}
+}
+
+func TestImportSessionRejects(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(doc *SessionDocument)
+	}{
+		{name: "format", modify: func(doc *SessionDocument) { doc.Format = "other" }},
+		{name: "version", modify: func(doc *SessionDocument) { doc.Version = SessionVersion + 1 }},
+		{name: "gap in a line", modify: func(doc *SessionDocument) { doc.Thoughts[1].ThoughtNumber = 1 }},
+		{name: "unknown branch", modify: func(doc *SessionDocument) {
+			doc.Branches = append(doc.Branches, Branch{ID: "ghost", BranchFromThought: 1})
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := newTestServer(t)
+			mustCall(t, source.processThought, thought(1, 2, "a", nil))
+			mustCall(t, source.processThought, thought(2, 2, "b", nil))
+			doc := source.ExportSession()
+			tt.modify(doc)
+
+			s := newTestServer(t)
+			mustCall(t, s.processThought, thought(1, 1, "kept", nil))
+			before := sessionJSON(t, s.ExportSession())
+			if err := s.ImportSession(doc); err == nil {
+				t.Fatalf("ImportSession accepted the document")
+			}
+			if after := sessionJSON(t, s.ExportSession()); after != before {
+				t.Errorf("a rejected document changed the session:\ngot  %s\nwant %s", after, before)
+			}
+		})
+	}
+}
+
+// sessionJSON returns doc as JSON, without the export time.
+func sessionJSON(t *testing.T, doc *SessionDocument) string {
+	t.Helper()
+	doc.ExportedAt = time.Time{}
+	jsonBytes, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(jsonBytes)
+}
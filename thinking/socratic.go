@@ -0,0 +1,85 @@
+package thinking
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// socraticTemplates holds the probing question for each category; %s is
+// replaced by an excerpt of the thought under examination.
+var socraticTemplates = map[string]string{
+	"clarification": "What exactly is meant by %q, and how would it be stated more precisely?",
+	"assumptions":   "What is being taken for granted in %q, and what if it were false?",
+	"evidence":      "What evidence supports %q, and how reliable is it?",
+	"alternatives":  "What other explanation or approach could replace %q?",
+	"implications":  "If %q holds, what follows from it, and is that acceptable?",
+	"viewpoints":    "How would someone who disagrees respond to %q?",
+}
+
+// defaultSocraticCategories are probed when no categories are given.
+var defaultSocraticCategories = []string{"evidence", "alternatives", "implications"}
+
+func socraticCategories() []string {
+	names := make([]string, 0, len(socraticTemplates))
+	for name := range socraticTemplates {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+const socraticExcerptLength = 80
+
+func (s *SequentialThinkingServer) socraticQuestioning(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	n, err := request.RequireInt("thoughtNumber")
+	if err != nil {
+		return s.fail(ctx, request, invalid("thoughtNumber", "integer", request.GetArguments()["thoughtNumber"], "must be a number"))
+	}
+	categories := request.GetStringSlice("categories", defaultSocraticCategories)
+	for _, c := range categories {
+		if _, ok := socraticTemplates[c]; !ok {
+			return s.fail(ctx, request, invalid("categories", "one of "+strings.Join(socraticCategories(), ", "), c,
+				"unknown category %q, expected one of %s", c, strings.Join(socraticCategories(), ", ")))
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, t, err := s.findThought(request.GetString("branchId", ""), n)
+	if err != nil {
+		return s.fail(ctx, request, err)
+	}
+	subject := []rune(strings.Join(strings.Fields(t.Thought), " "))
+	if len(subject) > socraticExcerptLength {
+		subject = append(subject[:socraticExcerptLength-1], '…')
+	}
+
+	raised := make([]Question, 0, len(categories))
+	for i, c := range categories {
+		if slices.Contains(categories[:i], c) {
+			continue
+		}
+		s.questionSeq++
+		q := Question{
+			ID:       fmt.Sprintf("Q%d", s.questionSeq),
+			Question: fmt.Sprintf(socraticTemplates[c], string(subject)),
+			Category: c,
+			RaisedIn: n,
+		}
+		s.questions = append(s.questions, q)
+		raised = append(raised, q)
+	}
+
+	if !s.disableThoughtLogging {
+		s.logEvent(s.theme.Record, fmt.Sprintf("🏛️  Raised %d Socratic questions on thought %d", len(raised), n), "")
+	}
+
+	return s.respond(ctx, request, map[string]any{
+		"questions": raised,
+	})
+}
@@ -0,0 +1,161 @@
+package thinking
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// shiftNumbers moves every thought numbered above n, and every reference to
+// one, up by k to make room for k new thoughts after thought n.
+func (s *SequentialThinkingServer) shiftNumbers(n, k int) {
+	shift := func(v int) int {
+		if v > n {
+			return v + k
+		}
+		return v
+	}
+	for i := range s.thoughtHistory {
+		t := &s.thoughtHistory[i]
+		if t.ThoughtNumber >= n {
+			t.TotalThoughts += k
+		}
+		t.ThoughtNumber = shift(t.ThoughtNumber)
+		if t.RevisesThought != nil {
+			v := shift(*t.RevisesThought)
+			t.RevisesThought = &v
+		}
+		if t.BranchFromThought != nil {
+			v := shift(*t.BranchFromThought)
+			t.BranchFromThought = &v
+		}
+		if t.ParentThought != nil {
+			v := shift(*t.ParentThought)
+			t.ParentThought = &v
+		}
+		if t.DependsOn != nil {
+			dependsOn := make([]int, len(t.DependsOn))
+			for j, v := range t.DependsOn {
+				dependsOn[j] = shift(v)
+			}
+			t.DependsOn = dependsOn
+		}
+		merged := make([]int, len(t.MergedThoughts))
+		for j, v := range t.MergedThoughts {
+			merged[j] = shift(v)
+		}
+		t.MergedThoughts = merged
+	}
+	s.remapReferences(shift)
+}
+
+// splitText cuts text at the given rune offsets into trimmed, non-empty parts.
+func splitText(text string, offsets []int) ([]string, error) {
+	runes := []rune(text)
+	if !slices.IsSorted(offsets) || slices.Contains(offsets, 0) {
+		return nil, invalid("offsets", "increasing offsets above 0", offsets, "must be increasing and greater than 0")
+	}
+	parts := make([]string, 0, len(offsets)+1)
+	start := 0
+	for _, end := range append(slices.Clone(offsets), len(runes)) {
+		if end > len(runes) || end <= start && end != len(runes) {
+			return nil, invalid("offsets", fmt.Sprintf("increasing offsets of at most %d", len(runes)), offsets, "must be increasing and within the thought's %d characters", len(runes))
+		}
+		part := strings.TrimSpace(string(runes[start:end]))
+		if part == "" {
+			return nil, invalid("offsets", "offsets that leave no part empty", end, "splitting at %d leaves an empty part", end)
+		}
+		parts = append(parts, part)
+		start = end
+	}
+	return parts, nil
+}
+
+func (s *SequentialThinkingServer) splitThought(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	n, err := request.RequireInt("thoughtNumber")
+	if err != nil {
+		return s.fail(ctx, request, invalid("thoughtNumber", "integer", request.GetArguments()["thoughtNumber"], "must be a number"))
+	}
+	offsets, err := request.RequireIntSlice("offsets")
+	if err != nil || len(offsets) == 0 {
+		return s.fail(ctx, request, invalid("offsets", "non-empty array of integers", request.GetArguments()["offsets"], "must be a non-empty array of numbers"))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	line, _, err := s.findThought(request.GetString("branchId", ""), n)
+	if err != nil {
+		return s.fail(ctx, request, err)
+	}
+	i := s.indexBefore(line, n, len(s.thoughtHistory))
+	original := s.thoughtHistory[i]
+	parts, err := splitText(original.Thought, offsets)
+	if err != nil {
+		return s.fail(ctx, request, err)
+	}
+
+	k := len(parts) - 1
+	if err := s.checkCapacity(k); err != nil {
+		return s.fail(ctx, request, err)
+	}
+	s.shiftNumbers(n, k)
+	original = s.thoughtHistory[i]
+
+	pieces := make([]ThoughtData, len(parts))
+	for j, part := range parts {
+		piece := original
+		piece.Tags = slices.Clone(original.Tags)
+		piece.ResolvesUncertainties = slices.Clone(original.ResolvesUncertainties)
+		piece.Metadata = maps.Clone(original.Metadata)
+		piece.Thought = part
+		piece.Tokens = s.tokenizer.estimate(part)
+		piece.ThoughtNumber = n + j
+		if j > 0 {
+			piece.Id = newULID(time.Now())
+			piece.IsRevision, piece.RevisesThought, piece.RevisesBranchId, piece.RevisesThoughtId = nil, nil, nil, nil
+			piece.MergedBranchId, piece.MergedThoughts = nil, nil
+			piece.ParentThought, piece.ParentId = nil, ""
+			piece.Uncertainties, piece.Citations = nil, nil
+			piece.DependsOn, piece.DependencyIds = nil, nil
+			piece.Version = 1
+		}
+		if j < k {
+			piece.NextThoughtNeeded = true
+			piece.NeedsMoreThoughts, piece.IsFinalAnswer = nil, nil
+		}
+		pieces[j] = piece
+	}
+	// The final answer concludes the last piece, which ends the chain now.
+	if s.finalAnswer != nil && s.finalAnswer.ThoughtId == original.Id {
+		answer := *s.finalAnswer
+		answer.ThoughtNumber, answer.ThoughtId = pieces[k].ThoughtNumber, pieces[k].Id
+		s.finalAnswer = &answer
+	}
+	s.thoughtHistory = slices.Insert(slices.Delete(s.thoughtHistory, i, i+1), i, pieces...)
+	s.generation++
+	s.rebuildBranches()
+	s.resourcesChanged()
+
+	numbers := make([]int, len(pieces))
+	ids := make([]string, len(pieces))
+	for j := range pieces {
+		numbers[j] = pieces[j].ThoughtNumber
+		ids[j] = pieces[j].Id
+	}
+	result := map[string]any{
+		"thoughtNumbers":       numbers,
+		"thoughtIds":           ids,
+		"shiftedBy":            k,
+		"thoughtHistoryLength": len(s.thoughtHistory),
+	}
+	if line != "" {
+		result["branchId"] = line
+	}
+	return s.respond(ctx, request, result)
+}
@@ -0,0 +1,161 @@
+package thinking
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSplitText(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		offsets []int
+		want    []string
+		wantErr bool
+	}{
+		{name: "two parts", text: "one. two.", offsets: []int{4}, want: []string{"one.", "two."}},
+		{name: "three parts", text: "a b c", offsets: []int{1, 3}, want: []string{"a", "b", "c"}},
+		{name: "counts characters, not bytes", text: "é. ü.", offsets: []int{2}, want: []string{"é.", "ü."}},
+		{name: "zero offset", text: "one. two.", offsets: []int{0}, wantErr: true},
+		{name: "decreasing offsets", text: "one two three", offsets: []int{8, 4}, wantErr: true},
+		{name: "beyond the text", text: "one", offsets: []int{4}, wantErr: true},
+		{name: "empty part", text: "one.   two.", offsets: []int{4, 6}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := splitText(tt.text, tt.offsets)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("splitText(%q, %v) = %q, want an error", tt.text, tt.offsets, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("splitText(%q, %v): %v", tt.text, tt.offsets, err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("splitText(%q, %v) = %q, want %q", tt.text, tt.offsets, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitThought(t *testing.T) {
+	tests := []struct {
+		name        string
+		history     []map[string]any
+		split       map[string]any
+		wantNumbers []int
+		wantTexts   []string
+		wantErr     bool
+	}{
+		{
+			name: "last thought",
+			history: []map[string]any{
+				thought(1, 2, "first", nil),
+				thought(2, 2, "second. third.", nil),
+			},
+			split:       map[string]any{"thoughtNumber": 2.0, "offsets": []any{7.0}},
+			wantNumbers: []int{1, 2, 3},
+			wantTexts:   []string{"first", "second.", "third."},
+		},
+		{
+			name: "later thoughts shift up",
+			history: []map[string]any{
+				thought(1, 3, "a. b. c.", nil),
+				thought(2, 3, "d", nil),
+				thought(3, 3, "e", nil),
+			},
+			split:       map[string]any{"thoughtNumber": 1.0, "offsets": []any{2.0, 5.0}},
+			wantNumbers: []int{1, 2, 3, 4, 5},
+			wantTexts:   []string{"a.", "b.", "c.", "d", "e"},
+		},
+		{
+			name:    "unknown thought",
+			history: []map[string]any{thought(1, 1, "a. b.", nil)},
+			split:   map[string]any{"thoughtNumber": 2.0, "offsets": []any{2.0}},
+			wantErr: true,
+		},
+		{
+			name:    "offset beyond the thought",
+			history: []map[string]any{thought(1, 1, "a. b.", nil)},
+			split:   map[string]any{"thoughtNumber": 1.0, "offsets": []any{9.0}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t)
+			for _, args := range tt.history {
+				mustCall(t, s.processThought, args)
+			}
+			before := slices.Clone(s.thoughtHistory)
+			fields, isError := callTool(t, s.splitThought, tt.split)
+			if tt.wantErr {
+				if !isError {
+					t.Fatalf("split_thought succeeded, want an error: %v", fields)
+				}
+				if !slices.EqualFunc(s.thoughtHistory, before, func(a, b ThoughtData) bool { return a.Id == b.Id && a.Thought == b.Thought }) {
+					t.Errorf("a rejected split changed the history")
+				}
+				return
+			}
+			if isError {
+				t.Fatalf("split_thought: %v", fields["error"])
+			}
+			if got := numbers(s); !slices.Equal(got, tt.wantNumbers) {
+				t.Errorf("numbers = %v, want %v", got, tt.wantNumbers)
+			}
+			texts := make([]string, len(s.thoughtHistory))
+			for i, t := range s.thoughtHistory {
+				texts[i] = t.Thought
+			}
+			if !slices.Equal(texts, tt.wantTexts) {
+				t.Errorf("texts = %q, want %q", texts, tt.wantTexts)
+			}
+		})
+	}
+}
+
+func TestSplitThoughtPieces(t *testing.T) {
+	s := newTestServer(t)
+	mustCall(t, s.processThought, thought(1, 2, "base", nil))
+	mustCall(t, s.processThought, thought(2, 2, "one. two. three.", map[string]any{
+		"tags":          []any{"plan"},
+		"metadata":      map[string]any{"source": "test"},
+		"citations":     []any{"main.go"},
+		"dependsOn":     []any{1.0},
+		"uncertainties": []any{"is it two?"},
+	}))
+	mustCall(t, s.splitThought, map[string]any{"thoughtNumber": 2.0, "offsets": []any{4.0, 9.0}})
+
+	pieces := s.thoughtHistory[1:]
+	if len(pieces) != 3 {
+		t.Fatalf("got %d pieces, want 3", len(pieces))
+	}
+	first := pieces[0]
+	if len(first.Citations) != 1 || len(first.DependsOn) != 1 || len(first.Uncertainties) != 1 {
+		t.Errorf("first piece lost citations, dependencies or uncertainties: %+v", first)
+	}
+	for i, p := range pieces[1:] {
+		if p.Citations != nil || p.DependsOn != nil || p.DependencyIds != nil || p.Uncertainties != nil {
+			t.Errorf("piece %d kept citations, dependencies or uncertainties: %+v", i+2, p)
+		}
+		if !slices.Equal(p.Tags, first.Tags) || p.Metadata["source"] != "test" {
+			t.Errorf("piece %d lost tags or metadata: %+v", i+2, p)
+		}
+		if p.Id == first.Id {
+			t.Errorf("piece %d shares the first piece's ID", i+2)
+		}
+	}
+	if got := s.openUncertainties(); len(got) != 1 {
+		t.Errorf("open uncertainties = %v, want one", got)
+	}
+
+	// The pieces share no slices or maps.
+	pieces[0].Tags[0] = "changed"
+	pieces[0].Metadata["source"] = "changed"
+	if pieces[1].Tags[0] != "plan" || pieces[1].Metadata["source"] != "test" {
+		t.Errorf("pieces share tags or metadata")
+	}
+}
@@ -0,0 +1,72 @@
+package thinking
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Statuses of a thought. Thoughts are recorded open; revising a thought
+// retracts it, and verifying the hypothesis a thought proposed confirms or
+// retracts it.
+const (
+	ThoughtOpen      = "open"
+	ThoughtConfirmed = "confirmed"
+	ThoughtRetracted = "retracted"
+)
+
+var thoughtStatuses = []string{ThoughtOpen, ThoughtConfirmed, ThoughtRetracted}
+
+// latestNumbered returns the latest thought numbered n on any line, or nil.
+func (s *SequentialThinkingServer) latestNumbered(n int) *ThoughtData {
+	for i := len(s.thoughtHistory) - 1; i >= 0; i-- {
+		if s.thoughtHistory[i].ThoughtNumber == n {
+			return &s.thoughtHistory[i]
+		}
+	}
+	return nil
+}
+
+// setStatus gives the thought with the given ID a status, returning the one
+// it had.
+func (s *SequentialThinkingServer) setStatus(id, status string) string {
+	var previous string
+	s.updateThought(id, func(t *ThoughtData) {
+		previous, t.Status = t.Status, status
+	})
+	return previous
+}
+
+func (s *SequentialThinkingServer) setThoughtStatus(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	status, err := request.RequireString("status")
+	if err != nil || !slices.Contains(thoughtStatuses, status) {
+		return s.fail(ctx, request, invalid("status", "one of "+strings.Join(thoughtStatuses, ", "), request.GetArguments()["status"], "must be one of %s", strings.Join(thoughtStatuses, ", ")))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	line, thought, err := s.targetThought(request)
+	if err != nil {
+		return s.fail(ctx, request, err)
+	}
+	previous := s.setStatus(thought.Id, status)
+
+	if !s.disableThoughtLogging {
+		s.logEvent(s.theme.Record, fmt.Sprintf("📌 Thought %d is %s", thought.ThoughtNumber, status), "")
+	}
+
+	result := map[string]any{
+		"thoughtNumber":  thought.ThoughtNumber,
+		"thoughtId":      thought.Id,
+		"status":         status,
+		"previousStatus": previous,
+	}
+	if line != "" {
+		result["branchId"] = line
+	}
+	return s.respond(ctx, request, result)
+}
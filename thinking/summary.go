@@ -0,0 +1,220 @@
+package thinking
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+type ThoughtRef struct {
+	ThoughtNumber int      `json:"thoughtNumber"`
+	BranchId      string   `json:"branchId,omitempty"`
+	Status        string   `json:"status,omitempty"`
+	Importance    *float64 `json:"importance,omitempty"`
+	Text          string   `json:"text"`
+}
+
+// BranchSummary describes one branch of the chain.
+type BranchSummary struct {
+	BranchFromThought int     `json:"branchFromThought"`
+	Thoughts          int     `json:"thoughts"`
+	LatestThought     int     `json:"latestThought"`
+	Description       string  `json:"description,omitempty"`
+	MergedInto        *string `json:"mergedInto,omitempty"`
+}
+
+// ThoughtSummary is a compact digest of the chain, meant to replace the full
+// history in the model's context.
+type ThoughtSummary struct {
+	Thoughts          int          `json:"thoughts"`
+	Revisions         int          `json:"revisions"`
+	LatestThought     int          `json:"latestThought"`
+	TotalThoughts     int          `json:"totalThoughts"`
+	NextThoughtNeeded bool         `json:"nextThoughtNeeded"`
+	KeyDecisions      []ThoughtRef `json:"keyDecisions"`
+	// KeyThoughts are the standing thoughts rated most important.
+	KeyThoughts       []ThoughtRef          `json:"keyThoughts,omitempty"`
+	OpenQuestions     []ThoughtRef          `json:"openQuestions"`
+	CurrentHypothesis *ThoughtRef           `json:"currentHypothesis,omitempty"`
+	Hypotheses        []Hypothesis          `json:"hypotheses"`
+	Assumptions       []Assumption          `json:"assumptions"`
+	Questions         []Question            `json:"questions"`
+	Decisions         []DecisionMatrix      `json:"decisions"`
+	MentalModels      []ModelApplication    `json:"mentalModels"`
+	DebugSessions     []DebugSession        `json:"debugSessions"`
+	Inquiries         []Inquiry             `json:"inquiries"`
+	Arguments         []ArgumentTree        `json:"arguments"`
+	Assessments       []KnowledgeAssessment `json:"assessments"`
+	Reviews           []Review              `json:"reviews"`
+	FinalAnswer       *FinalAnswer          `json:"finalAnswer,omitempty"`
+	ThoughtTypes      map[string]int        `json:"thoughtTypes,omitempty"`
+	// Tokens is the estimated length of all thoughts in tokens.
+	Tokens int `json:"tokens"`
+	// Authors counts the thoughts by each author.
+	Authors map[string]int `json:"authors,omitempty"`
+	// RetractedThoughts were retracted by a revision, a refuted hypothesis
+	// or set_thought_status.
+	RetractedThoughts []ThoughtRef `json:"retractedThoughts,omitempty"`
+	// OpenUncertainties were raised by standing thoughts and not resolved.
+	OpenUncertainties []ThoughtRef `json:"openUncertainties,omitempty"`
+	// SuspectThoughts depend on thoughts revised after them.
+	SuspectThoughts   []ThoughtRef             `json:"suspectThoughts,omitempty"`
+	Confidence        *ConfidenceSummary       `json:"confidence,omitempty"`
+	Pacing            *Pacing                  `json:"pacing,omitempty"`
+	BranchCount       int                      `json:"branchCount"`
+	AbandonedBranches int                      `json:"abandonedBranches"`
+	Branches          map[string]BranchSummary `json:"branches"`
+}
+
+const excerptLength = 160
+
+var (
+	decisionPattern   = regexp.MustCompile(`(?i)\b(decid\w*|decision|conclu\w*|therefore|chose|choose|going with|will use|settled? on)\b`)
+	hypothesisPattern = regexp.MustCompile(`(?i)\bhypothes[ie]s\b`)
+	questionPattern   = regexp.MustCompile(`[^.!?\n]*\?`)
+)
+
+func excerpt(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	runes := []rune(text)
+	if len(runes) <= excerptLength {
+		return text
+	}
+	return string(runes[:excerptLength-1]) + "…"
+}
+
+func refTo(t *ThoughtData, text string) ThoughtRef {
+	return ThoughtRef{ThoughtNumber: t.ThoughtNumber, BranchId: branchOf(t), Status: t.Status, Importance: t.Importance, Text: excerpt(text)}
+}
+
+// liveThoughts returns a predicate telling whether a thought still stands:
+// not revised by a later thought, not retracted and not on an abandoned
+// branch.
+// Revised thoughts are told apart by ID, since a revision may reuse the
+// number of the thought it revises.
+func (s *SequentialThinkingServer) liveThoughts() func(*ThoughtData) bool {
+	revised := make(map[string]bool)
+	for i, t := range s.thoughtHistory {
+		switch {
+		case t.RevisesThoughtId != nil:
+			revised[*t.RevisesThoughtId] = true
+		case t.RevisesThought != nil && t.RevisesBranchId != nil:
+			if j := s.indexBefore(*t.RevisesBranchId, *t.RevisesThought, i); j >= 0 {
+				revised[s.thoughtHistory[j].Id] = true
+			}
+		}
+	}
+	return func(t *ThoughtData) bool {
+		_, abandoned := s.abandonReason(branchOf(t))
+		return !abandoned && t.Status != ThoughtRetracted && !revised[t.Id]
+	}
+}
+
+// summarize derives the summary from the history with keyword heuristics.
+// Thoughts that were later revised or sit on abandoned branches don't
+// contribute decisions or questions.
+func (s *SequentialThinkingServer) summarize() ThoughtSummary {
+	summary := ThoughtSummary{
+		KeyDecisions:      make([]ThoughtRef, 0),
+		OpenQuestions:     make([]ThoughtRef, 0),
+		Hypotheses:        append(make([]Hypothesis, 0, len(s.hypotheses)), s.hypotheses...),
+		Assumptions:       append(make([]Assumption, 0, len(s.assumptions)), s.assumptions...),
+		Questions:         append(make([]Question, 0, len(s.questions)), s.questions...),
+		Decisions:         append(make([]DecisionMatrix, 0, len(s.decisions)), s.decisions...),
+		MentalModels:      append(make([]ModelApplication, 0, len(s.modelApplications)), s.modelApplications...),
+		DebugSessions:     append(make([]DebugSession, 0, len(s.debugSessions)), s.debugSessions...),
+		Inquiries:         append(make([]Inquiry, 0, len(s.inquiries)), s.inquiries...),
+		Arguments:         make([]ArgumentTree, 0),
+		Assessments:       append(make([]KnowledgeAssessment, 0, len(s.assessments)), s.assessments...),
+		Reviews:           append(make([]Review, 0, len(s.reviews)), s.reviews...),
+		FinalAnswer:       s.finalAnswer,
+		BranchCount:       len(s.branches) - s.abandonedBranches(),
+		AbandonedBranches: s.abandonedBranches(),
+		Branches:          make(map[string]BranchSummary, len(s.branches)),
+	}
+
+	for _, n := range s.arguments {
+		if n.Kind == ArgumentClaim {
+			summary.Arguments = append(summary.Arguments, s.argumentTree(n))
+		}
+	}
+
+	live := s.liveThoughts()
+	summary.Thoughts = len(s.thoughtHistory)
+	for i := range s.thoughtHistory {
+		t := &s.thoughtHistory[i]
+		if t.IsRevision != nil && *t.IsRevision {
+			summary.Revisions++
+		}
+		if t.Author != "" {
+			if summary.Authors == nil {
+				summary.Authors = make(map[string]int)
+			}
+			summary.Authors[t.Author]++
+		}
+		if t.Status == ThoughtRetracted {
+			summary.RetractedThoughts = append(summary.RetractedThoughts, refTo(t, t.Thought))
+		}
+		if !live(t) {
+			continue
+		}
+		if t.ThoughtType != nil {
+			if summary.ThoughtTypes == nil {
+				summary.ThoughtTypes = make(map[string]int)
+			}
+			summary.ThoughtTypes[*t.ThoughtType]++
+		}
+		if hasType(t, TypeConclusion) || decisionPattern.MatchString(t.Thought) {
+			summary.KeyDecisions = append(summary.KeyDecisions, refTo(t, t.Thought))
+		}
+		for _, q := range questionPattern.FindAllString(t.Thought, -1) {
+			if q = strings.TrimSpace(q); len(q) > 1 {
+				summary.OpenQuestions = append(summary.OpenQuestions, refTo(t, q))
+			}
+		}
+		if hasType(t, TypeHypothesis) || hypothesisPattern.MatchString(t.Thought) {
+			ref := refTo(t, t.Thought)
+			summary.CurrentHypothesis = &ref
+		}
+	}
+
+	if n := len(s.thoughtHistory); n > 0 {
+		latest := s.thoughtHistory[n-1]
+		summary.LatestThought = latest.ThoughtNumber
+		summary.TotalThoughts = latest.TotalThoughts
+		summary.NextThoughtNeeded = latest.NextThoughtNeeded
+	}
+	summary.Confidence = s.confidenceSummary()
+	summary.Tokens = s.sessionTokens()
+	summary.SuspectThoughts = s.suspectThoughts()
+	summary.OpenUncertainties = s.openUncertainties()
+	summary.KeyThoughts = s.keyThoughts()
+	summary.Pacing = s.pacing()
+
+	for id, b := range s.branches {
+		if b.AbandonReason != nil {
+			continue
+		}
+		branch := BranchSummary{
+			BranchFromThought: b.BranchFromThought,
+			Thoughts:          len(b.Thoughts),
+			LatestThought:     b.Thoughts[len(b.Thoughts)-1].ThoughtNumber,
+			Description:       b.Description,
+		}
+		if into, ok := s.mergedInto(id); ok {
+			branch.MergedInto = &into
+		}
+		summary.Branches[id] = branch
+	}
+
+	return summary
+}
+
+func (s *SequentialThinkingServer) summarizeThoughts(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.respond(ctx, request, s.summarize())
+}
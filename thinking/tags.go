@@ -0,0 +1,100 @@
+package thinking
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// normalizeTags trims tags and drops empty and duplicate ones, keeping order.
+func normalizeTags(tags []string) []string {
+	var out []string
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag != "" && !slices.Contains(out, tag) {
+			out = append(out, tag)
+		}
+	}
+	return out
+}
+
+func (s *SequentialThinkingServer) tagThought(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	tags, err := request.RequireStringSlice("tags")
+	if tags = normalizeTags(tags); err != nil || len(tags) == 0 {
+		return s.fail(ctx, request, invalid("tags", "non-empty array of strings", request.GetArguments()["tags"], "must be a non-empty array of strings"))
+	}
+	remove := request.GetBool("remove", false)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	line, thought, err := s.targetThought(request)
+	if err != nil {
+		return s.fail(ctx, request, err)
+	}
+	n := thought.ThoughtNumber
+
+	var updated []string
+	s.updateThought(thought.Id, func(t *ThoughtData) {
+		if remove {
+			t.Tags = slices.DeleteFunc(slices.Clone(t.Tags), func(tag string) bool { return slices.Contains(tags, tag) })
+		} else {
+			t.Tags = normalizeTags(append(slices.Clone(t.Tags), tags...))
+		}
+		if len(t.Tags) == 0 {
+			t.Tags = nil
+		}
+		updated = t.Tags
+	})
+
+	result := map[string]any{
+		"thoughtNumber": n,
+		"thoughtId":     thought.Id,
+		"tags":          append([]string{}, updated...),
+	}
+	if line != "" {
+		result["branchId"] = line
+	}
+	return s.respond(ctx, request, result)
+}
+
+func (s *SequentialThinkingServer) getTaggedThoughts(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	tags := normalizeTags(request.GetStringSlice("tags", nil))
+	matchAll := request.GetBool("matchAll", false)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	counts := make(map[string]int)
+	thoughts := make([]ThoughtData, 0)
+	for _, t := range s.thoughtHistory {
+		for _, tag := range t.Tags {
+			counts[tag]++
+		}
+		if len(tags) == 0 {
+			continue
+		}
+		matches := slices.ContainsFunc(t.Tags, func(tag string) bool { return slices.Contains(tags, tag) })
+		if matchAll {
+			matches = !slices.ContainsFunc(tags, func(tag string) bool { return !slices.Contains(t.Tags, tag) })
+		}
+		if matches {
+			thoughts = append(thoughts, t)
+		}
+	}
+
+	known := make([]string, 0, len(counts))
+	for tag := range counts {
+		known = append(known, tag)
+	}
+	sort.Strings(known)
+
+	return s.respond(ctx, request, map[string]any{
+		"thoughts":  thoughts,
+		"tags":      known,
+		"tagCounts": counts,
+	})
+}
@@ -0,0 +1,11 @@
+//go:build !windows
+
+package thinking
+
+import "os"
+
+// legacyConsole tells whether f is a Windows console that can't process
+// escape sequences, which it never is here.
+func legacyConsole(f *os.File) bool {
+	return false
+}
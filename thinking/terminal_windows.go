@@ -0,0 +1,26 @@
+//go:build windows
+
+package thinking
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// legacyConsole tells whether f is a console that can't process escape
+// sequences, turning their processing on first. Consoles before Windows 10
+// can't, and neither can they draw emoji or box-drawing characters in their
+// default fonts.
+func legacyConsole(f *os.File) bool {
+	handle := windows.Handle(f.Fd())
+	var mode uint32
+	if err := windows.GetConsoleMode(handle, &mode); err != nil {
+		// Not a console.
+		return false
+	}
+	if mode&windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING != 0 {
+		return false
+	}
+	return windows.SetConsoleMode(handle, mode|windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING) != nil
+}
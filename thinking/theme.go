@@ -0,0 +1,207 @@
+package thinking
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/fatih/color"
+	"github.com/mattn/go-isatty"
+)
+
+// Theme colors the thought log. Nil colors print plain text.
+type Theme struct {
+	Thought  *color.Color
+	Revision *color.Color
+	Branch   *color.Color
+	Merge    *color.Color
+	// Record colors records and session events: hypotheses, checkpoints,
+	// imports and the like.
+	Record *color.Color
+	// Alert colors failures and destructive actions.
+	Alert *color.Color
+	// Success colors the final answer and the thought tree.
+	Success *color.Color
+	// Deleted and Inserted color the words a revision removes and adds.
+	Deleted  *color.Color
+	Inserted *color.Color
+	// Markdown names the glamour style thought text is rendered in, such as
+	// dark, light or notty. Empty leaves the text as written.
+	Markdown string
+	// ASCII draws the log with plain labels and +---+ borders instead of
+	// emoji and box-drawing characters, for terminals and log collectors
+	// that mangle them.
+	ASCII bool
+}
+
+// themes are the built-in themes by name. Each call returns fresh colors,
+// so enabling or disabling one theme's colors doesn't affect another's.
+var themes = map[string]func() Theme{
+	"default": func() Theme {
+		return Theme{
+			Thought:  color.New(color.FgBlue),
+			Revision: color.New(color.FgYellow),
+			Branch:   color.New(color.FgGreen),
+			Merge:    color.New(color.FgMagenta),
+			Record:   color.New(color.FgCyan),
+			Alert:    color.New(color.FgRed),
+			Success:  color.New(color.FgGreen),
+			Deleted:  color.New(color.FgRed, color.CrossedOut),
+			Inserted: color.New(color.FgGreen),
+			Markdown: "dark",
+		}
+	},
+	// dark uses bright colors that stay readable on dark backgrounds.
+	"dark": func() Theme {
+		return Theme{
+			Thought:  color.New(color.FgHiBlue),
+			Revision: color.New(color.FgHiYellow),
+			Branch:   color.New(color.FgHiGreen),
+			Merge:    color.New(color.FgHiMagenta),
+			Record:   color.New(color.FgHiCyan),
+			Alert:    color.New(color.FgHiRed, color.Bold),
+			Success:  color.New(color.FgHiGreen, color.Bold),
+			Deleted:  color.New(color.FgHiRed, color.CrossedOut),
+			Inserted: color.New(color.FgHiGreen),
+			Markdown: "dark",
+		}
+	},
+	// light avoids yellow and cyan, which wash out on light backgrounds.
+	"light": func() Theme {
+		return Theme{
+			Thought:  color.New(color.FgBlue, color.Bold),
+			Revision: color.New(color.FgRed),
+			Branch:   color.New(color.FgGreen, color.Bold),
+			Merge:    color.New(color.FgMagenta, color.Bold),
+			Record:   color.New(color.FgBlack, color.Bold),
+			Alert:    color.New(color.FgRed, color.Bold),
+			Success:  color.New(color.FgGreen, color.Bold),
+			Deleted:  color.New(color.FgRed, color.CrossedOut),
+			Inserted: color.New(color.FgGreen, color.Bold),
+			Markdown: "light",
+		}
+	},
+	// mono tells kinds apart by weight and decoration instead of hue, for
+	// color-blind readers and monochrome terminals.
+	"mono": func() Theme {
+		return Theme{
+			Thought:  color.New(color.Bold),
+			Revision: color.New(color.Bold, color.Underline),
+			Branch:   color.New(color.Bold, color.Italic),
+			Merge:    color.New(color.Bold, color.ReverseVideo),
+			Record:   color.New(color.Faint),
+			Alert:    color.New(color.Bold, color.ReverseVideo),
+			Success:  color.New(color.Bold),
+			Deleted:  color.New(color.CrossedOut),
+			Inserted: color.New(color.Bold, color.Underline),
+			Markdown: "notty",
+		}
+	},
+}
+
+// ThemeNames lists the built-in themes.
+func ThemeNames() []string {
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NamedTheme returns the built-in theme called name.
+func NamedTheme(name string) (Theme, error) {
+	theme, ok := themes[name]
+	if !ok {
+		return Theme{}, fmt.Errorf("unknown theme %q; available themes are %s", name, strings.Join(ThemeNames(), ", "))
+	}
+	return theme(), nil
+}
+
+// ThemeFromEnv returns the theme named by GOTHINK_THEME, or the default one,
+// with colors enabled only when out is a terminal and NO_COLOR is unset.
+// GOTHINK_MARKDOWN=false leaves Markdown in thoughts unrendered, and
+// GOTHINK_ASCII=true turns on ASCII mode.
+func ThemeFromEnv(out *os.File) Theme {
+	theme := themes["default"]()
+	if name := os.Getenv("GOTHINK_THEME"); name != "" {
+		named, err := NamedTheme(name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v; using the default theme\n", err)
+		} else {
+			theme = named
+		}
+	}
+	if os.Getenv("GOTHINK_MARKDOWN") == "false" {
+		theme.Markdown = ""
+	}
+	theme.ASCII = os.Getenv("GOTHINK_ASCII") == "true"
+	theme.adapt(out)
+	return theme
+}
+
+// WithTheme sets the theme of the thought log, overriding GOTHINK_THEME.
+// Colors stay off under NO_COLOR and when stderr isn't a terminal.
+func WithTheme(theme Theme) Option {
+	return func(s *SequentialThinkingServer) {
+		theme.adapt(os.Stderr)
+		s.theme = theme
+	}
+}
+
+// plainHeadline drops the emoji a log headline starts with, for ASCII mode.
+func plainHeadline(headline string) string {
+	return strings.TrimLeftFunc(headline, func(r rune) bool {
+		return r > unicode.MaxASCII || unicode.IsSpace(r)
+	})
+}
+
+// paintf formats text in color c. A nil color leaves the text plain.
+func paintf(c *color.Color, format string, a ...any) string {
+	if c == nil {
+		return fmt.Sprintf(format, a...)
+	}
+	return c.Sprintf(format, a...)
+}
+
+// colorEnabled tells whether output to out should be colored.
+func colorEnabled(out *os.File) bool {
+	if _, ok := os.LookupEnv("NO_COLOR"); ok || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	return isatty.IsTerminal(out.Fd()) || isatty.IsCygwinTerminal(out.Fd())
+}
+
+// adapt fits the theme to out: colors only where they are wanted and shown,
+// and ASCII mode on Windows consoles too old for anything else.
+func (t *Theme) adapt(out *os.File) {
+	legacy := legacyConsole(out)
+	if legacy {
+		t.ASCII = true
+	}
+	t.enable(!legacy && colorEnabled(out))
+}
+
+// enable turns the theme's colors on or off, regardless of whether stdout
+// is a terminal. Without colors, Markdown is rendered in the notty style,
+// and in ASCII mode in the ascii one.
+func (t *Theme) enable(on bool) {
+	switch {
+	case t.Markdown == "":
+	case t.ASCII:
+		t.Markdown = "ascii"
+	case !on:
+		t.Markdown = "notty"
+	}
+	for _, c := range []*color.Color{t.Thought, t.Revision, t.Branch, t.Merge, t.Record, t.Alert, t.Success, t.Deleted, t.Inserted} {
+		switch {
+		case c == nil:
+		case on:
+			c.EnableColor()
+		default:
+			c.DisableColor()
+		}
+	}
+}
@@ -0,0 +1,503 @@
+package thinking
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type ThoughtData struct {
+	// Id is the ULID the server gives the thought when it records it, which
+	// keeps naming it when it is renumbered.
+	Id                string  `json:"id,omitempty"`
+	Thought           string  `json:"thought"`
+	ThoughtNumber     int     `json:"thoughtNumber"`
+	TotalThoughts     int     `json:"totalThoughts"`
+	NextThoughtNeeded bool    `json:"nextThoughtNeeded"`
+	IsRevision        *bool   `json:"isRevision,omitempty"`
+	RevisesThought    *int    `json:"revisesThought,omitempty"`
+	BranchFromThought *int    `json:"branchFromThought,omitempty"`
+	BranchId          *string `json:"branchId,omitempty"`
+	NeedsMoreThoughts *bool   `json:"needsMoreThoughts,omitempty"`
+	RevisesBranchId   *string `json:"revisesBranchId,omitempty"`
+	RevisesThoughtId  *string `json:"revisesThoughtId,omitempty"`
+	// ParentThought is the thought this one follows from, when it isn't the
+	// previous thought on its line, and ParentId that thought's ID.
+	ParentThought *int   `json:"parentThought,omitempty"`
+	ParentId      string `json:"parentId,omitempty"`
+	// DependsOn lists the earlier thoughts this one relies on, and
+	// DependencyIds their IDs.
+	DependsOn      []int    `json:"dependsOn,omitempty"`
+	DependencyIds  []string `json:"dependencyIds,omitempty"`
+	MergedBranchId *string  `json:"mergedBranchId,omitempty"`
+	MergedThoughts []int    `json:"mergedThoughts,omitempty"`
+	Tags           []string `json:"tags,omitempty"`
+	Confidence     *float64 `json:"confidence,omitempty"`
+	Importance     *float64 `json:"importance,omitempty"`
+	ThoughtType    *string  `json:"thoughtType,omitempty"`
+	Status         string   `json:"status,omitempty"`
+	// IsFinalAnswer marks the thought concluding the main line as the
+	// session's final answer.
+	IsFinalAnswer *bool `json:"isFinalAnswer,omitempty"`
+	// Author names the agent or model that wrote the thought, for sessions
+	// shared by several agents.
+	Author string `json:"author,omitempty"`
+	// Citations are URLs, file paths or resource URIs the thought gives as
+	// evidence.
+	Citations []string `json:"citations,omitempty"`
+	// Uncertainties are what the thought is unsure of, outstanding until a
+	// later thought lists them in ResolvesUncertainties.
+	Uncertainties         []string `json:"uncertainties,omitempty"`
+	ResolvesUncertainties []string `json:"resolvesUncertainties,omitempty"`
+	// Tokens is the estimated length of the thought in tokens.
+	Tokens int `json:"tokens,omitempty"`
+	// Version counts the thought's place in its revision chain: 1 for an
+	// original thought, 2 for the first revision of it, and so on.
+	Version int `json:"version,omitempty"`
+	// Metadata holds the client's own annotations of the thought, such as
+	// the model that wrote it; the server stores and exports it untouched.
+	Metadata map[string]any `json:"metadata,omitempty"`
+	// ReceivedAt is when the server recorded the thought, and CreatedAt
+	// when the client says it wrote it, if it did.
+	ReceivedAt *time.Time `json:"receivedAt,omitempty"`
+	CreatedAt  *time.Time `json:"createdAt,omitempty"`
+}
+
+// defaultMaxThoughts caps thought numbers and totals unless
+// GOTHINK_MAX_THOUGHTS or WithMaxThoughts sets another cap.
+const defaultMaxThoughts = 1000
+
+// WithMaxThoughts caps thoughtNumber and totalThoughts, overriding
+// GOTHINK_MAX_THOUGHTS. Thoughts numbered or estimated above it are
+// rejected.
+func WithMaxThoughts(n int) Option {
+	return func(s *SequentialThinkingServer) {
+		s.maxThoughts = n
+	}
+}
+
+func maxThoughtsFromEnv() int {
+	if n, err := strconv.Atoi(os.Getenv("GOTHINK_MAX_THOUGHTS")); err == nil && n > 0 {
+		return n
+	}
+	return defaultMaxThoughts
+}
+
+// WithMaxTotalIncrease caps how far a thought may raise totalThoughts above
+// the previous thought's estimate, overriding GOTHINK_MAX_TOTAL_INCREASE.
+// Larger raises are cut to the cap with a warning; 0 leaves them uncapped.
+func WithMaxTotalIncrease(n int) Option {
+	return func(s *SequentialThinkingServer) {
+		s.maxTotalIncrease = n
+	}
+}
+
+func maxTotalIncreaseFromEnv() int {
+	n, _ := strconv.Atoi(os.Getenv("GOTHINK_MAX_TOTAL_INCREASE"))
+	return max(n, 0)
+}
+
+// capTotal cuts a raise of totalThoughts larger than the configured step
+// down to it, so that an agent can't inflate its estimate without bound,
+// and says so.
+func (s *SequentialThinkingServer) capTotal(data *ThoughtData) string {
+	if s.maxTotalIncrease <= 0 || len(s.thoughtHistory) == 0 {
+		return ""
+	}
+	previous := s.thoughtHistory[len(s.thoughtHistory)-1].TotalThoughts
+	if data.TotalThoughts <= previous+s.maxTotalIncrease {
+		return ""
+	}
+	requested := data.TotalThoughts
+	data.TotalThoughts = previous + s.maxTotalIncrease
+	return fmt.Sprintf("totalThoughts can rise by at most %d per thought, so %d was cut to %d; conclude or narrow the problem rather than extending the estimate",
+		s.maxTotalIncrease, requested, data.TotalThoughts)
+}
+
+// checkBounds rejects thought numbers and totals below 1 or above the cap.
+func (s *SequentialThinkingServer) checkBounds(data *ThoughtData) error {
+	expected := fmt.Sprintf("integer from 1 to %d", s.maxThoughts)
+	switch {
+	case data.ThoughtNumber < 1:
+		return invalid("thoughtNumber", expected, data.ThoughtNumber, "must be at least 1, got %d", data.ThoughtNumber)
+	case data.TotalThoughts < 1:
+		return invalid("totalThoughts", expected, data.TotalThoughts, "must be at least 1, got %d", data.TotalThoughts)
+	case data.ThoughtNumber > s.maxThoughts:
+		return invalid("thoughtNumber", expected, data.ThoughtNumber, "must be at most %d, got %d", s.maxThoughts, data.ThoughtNumber)
+	case data.TotalThoughts > s.maxThoughts:
+		return invalid("totalThoughts", expected, data.TotalThoughts, "must be at most %d, got %d", s.maxThoughts, data.TotalThoughts)
+	}
+	return nil
+}
+
+// number reads a numeric argument: a JSON number as decoded by
+// encoding/json (float64 or json.Number), a Go integer or float from a typed
+// transport, or a string holding a number, such as "3".
+func number(val any) (float64, bool) {
+	switch v := val.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case json.Number:
+		num, err := v.Float64()
+		return num, err == nil
+	case string:
+		num, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return num, err == nil && !math.IsInf(num, 0) && !math.IsNaN(num)
+	}
+	return 0, false
+}
+
+// validateThoughtData checks the arguments of a thought against the
+// sequentialthinking input schema and reads them.
+func (s *SequentialThinkingServer) validateThoughtData(args map[string]any) (*ThoughtData, error) {
+	if err := checkArguments(sequentialThinkingTool.InputSchema, args); err != nil {
+		return nil, err
+	}
+	data := &ThoughtData{
+		Thought:           args["thought"].(string),
+		NextThoughtNeeded: args["nextThoughtNeeded"].(bool),
+	}
+	num, _ := number(args["thoughtNumber"])
+	data.ThoughtNumber = int(num)
+	num, _ = number(args["totalThoughts"])
+	data.TotalThoughts = int(num)
+
+	if b, ok := args["isRevision"].(bool); ok {
+		data.IsRevision = &b
+	}
+	if num, ok := number(args["revisesThought"]); ok {
+		thought := int(num)
+		data.RevisesThought = &thought
+	}
+	if num, ok := number(args["branchFromThought"]); ok {
+		thought := int(num)
+		data.BranchFromThought = &thought
+	}
+	if id, ok := args["branchId"].(string); ok {
+		data.BranchId = &id
+	}
+	if b, ok := args["needsMoreThoughts"].(bool); ok {
+		data.NeedsMoreThoughts = &b
+	}
+	if b, ok := args["isFinalAnswer"].(bool); ok {
+		data.IsFinalAnswer = &b
+	}
+	if id, ok := args["revisesBranchId"].(string); ok {
+		data.RevisesBranchId = &id
+	}
+	if id, ok := args["revisesThoughtId"].(string); ok {
+		data.RevisesThoughtId = &id
+	}
+	if num, ok := number(args["parentThought"]); ok {
+		thought := int(num)
+		data.ParentThought = &thought
+	}
+	if list, ok := args["dependsOn"].([]any); ok {
+		for _, item := range list {
+			num, _ := number(item)
+			if !slices.Contains(data.DependsOn, int(num)) {
+				data.DependsOn = append(data.DependsOn, int(num))
+			}
+		}
+	}
+	if num, ok := number(args["confidence"]); ok {
+		data.Confidence = &num
+	}
+	if num, ok := number(args["importance"]); ok {
+		data.Importance = &num
+	}
+	if kind, ok := args["thoughtType"].(string); ok {
+		data.ThoughtType = &kind
+	}
+	if author, ok := args["author"].(string); ok {
+		data.Author = strings.TrimSpace(author)
+	}
+	if metadata, ok := args["metadata"].(map[string]any); ok && len(metadata) > 0 {
+		data.Metadata = metadata
+	}
+	data.Citations = stringSet(args["citations"])
+	data.Uncertainties = stringSet(args["uncertainties"])
+	data.ResolvesUncertainties = stringSet(args["resolvesUncertainties"])
+	if list, ok := args["tags"].([]any); ok {
+		tags := make([]string, len(list))
+		for i, tag := range list {
+			tags[i] = tag.(string)
+		}
+		data.Tags = normalizeTags(tags)
+	}
+	if text, ok := args["createdAt"].(string); ok {
+		at, err := time.Parse(time.RFC3339, text)
+		if err != nil {
+			return nil, invalid("createdAt", "RFC 3339 timestamp", text,
+				"must be an RFC 3339 timestamp such as 2025-06-01T12:00:00Z")
+		}
+		data.CreatedAt = &at
+	}
+	return data, nil
+}
+
+// checkBranchFields checks that a thought on a branch names both the branch
+// and the thought it forks from, and that it comes after that thought: a
+// branch can't fork from itself or its own descendants.
+func checkBranchFields(data *ThoughtData) error {
+	switch {
+	case data.BranchFromThought != nil && data.BranchId == nil:
+		return invalid("branchId", "non-empty string", nil, "required when branchFromThought is set; name the branch this thought starts or continues")
+	case data.BranchId != nil && data.BranchFromThought == nil:
+		return invalid("branchFromThought", "integer", nil, "required when branchId is set; give the main-line thought branch %q forks from", *data.BranchId)
+	case data.BranchFromThought != nil && *data.BranchFromThought < 1:
+		return invalid("branchFromThought", "integer of at least 1", *data.BranchFromThought, "must be at least 1, got %d", *data.BranchFromThought)
+	case data.BranchFromThought != nil && *data.BranchFromThought >= data.ThoughtNumber:
+		return invalid("branchFromThought", fmt.Sprintf("integer below %d", data.ThoughtNumber), *data.BranchFromThought,
+			"thought %d cannot branch from thought %d, as the branch would fork from itself or a later thought; number the thought after the one it branches from",
+			data.ThoughtNumber, *data.BranchFromThought)
+	}
+	return nil
+}
+
+// checkBranchPoint checks that the thought a branch forks from was recorded:
+// on the main line, or, for a branch that already exists, on the branch
+// itself.
+func (s *SequentialThinkingServer) checkBranchPoint(data *ThoughtData) error {
+	id := branchOf(data)
+	if id == "" {
+		return nil
+	}
+	from := *data.BranchFromThought
+	if s.indexBefore("", from, len(s.thoughtHistory)) >= 0 || len(s.thoughtsInScope(id, from)) > 0 {
+		return nil
+	}
+	var numbers []int
+	for _, t := range s.thoughtHistory {
+		if branchOf(&t) == "" && !slices.Contains(numbers, t.ThoughtNumber) {
+			numbers = append(numbers, t.ThoughtNumber)
+		}
+	}
+	if len(numbers) == 0 {
+		return invalid("branchFromThought", "recorded main-line thought number", from,
+			"thought %d does not exist; the main line has no thoughts to branch from yet", from)
+	}
+	slices.Sort(numbers)
+	return invalid("branchFromThought", "one of "+joinInts(numbers), from,
+		"thought %d does not exist in the main line; branch from one of thoughts %s", from, joinInts(numbers))
+}
+
+// checkRevisionFields checks that the revision parameters of a thought agree:
+// a revision names the earlier thought it revises, and only revisions do.
+func checkRevisionFields(data *ThoughtData) error {
+	isRevision := data.IsRevision != nil && *data.IsRevision
+	switch {
+	case isRevision && data.RevisesThought == nil:
+		return invalid("revisesThought", "integer", nil, "required when isRevision is true")
+	case data.RevisesThought != nil && !isRevision:
+		var received any
+		if data.IsRevision != nil {
+			received = false
+		}
+		return invalid("isRevision", "true", received, "must be true when revisesThought is set")
+	case data.RevisesBranchId != nil && data.RevisesThought == nil:
+		return invalid("revisesBranchId", "omitted unless revisesThought is set", *data.RevisesBranchId,
+			"only applies together with revisesThought")
+	case data.RevisesThought != nil && *data.RevisesThought < 1:
+		return invalid("revisesThought", "integer of at least 1", *data.RevisesThought,
+			"must be at least 1, got %d", *data.RevisesThought)
+	case data.RevisesThought != nil && *data.RevisesThought > data.ThoughtNumber:
+		return invalid("revisesThought", fmt.Sprintf("integer of at most %d", data.ThoughtNumber), *data.RevisesThought,
+			"thought %d cannot revise the later thought %d", data.ThoughtNumber, *data.RevisesThought)
+	}
+	return nil
+}
+
+// branchOf returns the branch a thought belongs to, or "" for the main line.
+func branchOf(data *ThoughtData) string {
+	if data.BranchFromThought != nil && data.BranchId != nil {
+		return *data.BranchId
+	}
+	return ""
+}
+
+func describeScope(branchId string) string {
+	if branchId == "" {
+		return "the main line"
+	}
+	return fmt.Sprintf("branch %q", branchId)
+}
+
+// thoughtsInScope returns the recorded thoughts numbered n on the given line.
+func (s *SequentialThinkingServer) thoughtsInScope(branchId string, n int) []ThoughtData {
+	var matches []ThoughtData
+	if branchId == "" {
+		for _, t := range s.thoughtHistory {
+			if t.ThoughtNumber == n && branchOf(&t) == "" {
+				matches = append(matches, t)
+			}
+		}
+		return matches
+	}
+	if s.branches[branchId] == nil {
+		return matches
+	}
+	for _, t := range s.branches[branchId].Thoughts {
+		if t.ThoughtNumber == n {
+			matches = append(matches, t)
+		}
+	}
+	return matches
+}
+
+// branchOrigin returns the thought number a branch was forked from, or 0 for
+// the main line and unknown branches.
+func (s *SequentialThinkingServer) branchOrigin(branchId string) int {
+	if b := s.branches[branchId]; b != nil {
+		return b.BranchFromThought
+	}
+	return 0
+}
+
+// lookup finds the thoughts numbered n as seen from a line: a branch sees its
+// own thoughts plus the main line up to its branching point (origin). It also
+// returns the line the matches were found on.
+func (s *SequentialThinkingServer) lookup(branchId string, origin, n int) (string, []ThoughtData) {
+	matches := s.thoughtsInScope(branchId, n)
+	if len(matches) == 0 && branchId != "" && n <= origin {
+		return "", s.thoughtsInScope("", n)
+	}
+	return branchId, matches
+}
+
+// updateThought applies fn to the thought with the given ID in the history,
+// then refreshes the separate copy kept in its branch.
+func (s *SequentialThinkingServer) updateThought(id string, fn func(*ThoughtData)) {
+	t := s.thoughtByID(id)
+	if t == nil {
+		return
+	}
+	fn(t)
+	branchId := branchOf(t)
+	if b := s.branches[branchId]; b != nil {
+		for j := range b.Thoughts {
+			if b.Thoughts[j].Id == id {
+				b.Thoughts[j] = *t
+			}
+		}
+	}
+	s.resourcesChanged(branchId)
+}
+
+// revisionsOf returns the thoughts that revise thought n on the given line,
+// oldest first.
+func (s *SequentialThinkingServer) revisionsOf(branchId string, n int) []ThoughtData {
+	revisions := make([]ThoughtData, 0)
+	for _, t := range s.thoughtHistory {
+		if t.RevisesThought != nil && *t.RevisesThought == n &&
+			t.RevisesBranchId != nil && *t.RevisesBranchId == branchId {
+			revisions = append(revisions, t)
+		}
+	}
+	return revisions
+}
+
+// resolveRevisionTarget pins revisesThought to exactly one recorded thought,
+// failing when there is none.
+// The lookup happens on the revising thought's own line unless revisesBranchId
+// overrides it (an empty string selects the main line). Inside a branch,
+// thoughts up to the branching point are shared with the main line.
+// On success RevisesBranchId is set to the line the target was found on.
+func (s *SequentialThinkingServer) resolveRevisionTarget(data *ThoughtData) error {
+	if data.RevisesThought == nil || data.RevisesThoughtId != nil {
+		return nil
+	}
+	target := *data.RevisesThought
+
+	scope := branchOf(data)
+	explicit := data.RevisesBranchId != nil
+	if explicit {
+		scope = *data.RevisesBranchId
+		if scope != "" && s.branches[scope] == nil && scope != branchOf(data) {
+			return invalid("revisesBranchId", "recorded branch ID", scope, "unknown branch %q", scope)
+		}
+	}
+
+	origin := s.branchOrigin(scope)
+	if scope != "" && scope == branchOf(data) {
+		origin = *data.BranchFromThought
+	}
+	scope, matches := s.lookup(scope, origin, target)
+
+	switch {
+	case len(matches) > 1:
+		return invalid("revisesThought", "unambiguous thought number", target,
+			"thought %d is ambiguous, %s has %d thoughts with that number", target, describeScope(scope), len(matches))
+	case len(matches) == 0:
+		if explicit {
+			return invalid("revisesThought", "recorded thought number", target,
+				"thought %d does not exist in %s", target, describeScope(scope))
+		}
+		var elsewhere []string
+		if scope != "" && len(s.thoughtsInScope("", target)) > 0 {
+			elsewhere = append(elsewhere, describeScope(""))
+		}
+		for id := range s.branches {
+			if id != scope && len(s.thoughtsInScope(id, target)) > 0 {
+				elsewhere = append(elsewhere, describeScope(id))
+			}
+		}
+		if len(elsewhere) > 0 {
+			sort.Strings(elsewhere)
+			return invalid("revisesThought", "recorded thought number", target,
+				"thought %d is not in %s but exists in %s; set revisesBranchId to choose one",
+				target, describeScope(branchOf(data)), strings.Join(elsewhere, ", "))
+		}
+		return invalid("revisesThought", "recorded thought number", target,
+			"thought %d does not exist in %s", target, describeScope(scope))
+	}
+
+	data.RevisesBranchId = &scope
+	return nil
+}
+
+// metadataJSON encodes the metadata of a thought on one line, for exports
+// that hold it in a single field, or returns "" if it has none.
+func metadataJSON(t *ThoughtData) string {
+	if t.Metadata == nil {
+		return ""
+	}
+	encoded, err := json.Marshal(t.Metadata)
+	if err != nil {
+		return ""
+	}
+	return string(encoded)
+}
+
+// stringSet reads a string array argument, trimmed and without duplicates,
+// or nil if it wasn't given.
+func stringSet(v any) []string {
+	list, _ := v.([]any)
+	var set []string
+	for _, item := range list {
+		if text := strings.TrimSpace(item.(string)); text != "" && !slices.Contains(set, text) {
+			set = append(set, text)
+		}
+	}
+	return set
+}
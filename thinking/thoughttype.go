@@ -0,0 +1,55 @@
+package thinking
+
+import "fmt"
+
+// Thought types, declared with the optional thoughtType parameter.
+const (
+	TypeAnalysis     = "analysis"
+	TypeHypothesis   = "hypothesis"
+	TypeVerification = "verification"
+	TypeObservation  = "observation"
+	TypeAction       = "action"
+	TypeConclusion   = "conclusion"
+)
+
+var thoughtTypes = []string{TypeAnalysis, TypeHypothesis, TypeVerification, TypeObservation, TypeAction, TypeConclusion}
+
+// hasType reports whether a thought was declared of type kind.
+func hasType(t *ThoughtData, kind string) bool {
+	return t.ThoughtType != nil && *t.ThoughtType == kind
+}
+
+// thoughtTypeOf returns the declared type of a thought, or "" if it has none.
+func thoughtTypeOf(t *ThoughtData) string {
+	if t.ThoughtType == nil {
+		return ""
+	}
+	return *t.ThoughtType
+}
+
+// unverifiedHypothesisWarning flags, when the chain concludes, standing
+// hypothesis thoughts that no later verification thought on the same line
+// (or on a branch forked after it) follows, or returns "" if there are none.
+func (s *SequentialThinkingServer) unverifiedHypothesisWarning() string {
+	live := s.liveThoughts()
+	var unverified []int
+	for i := range s.thoughtHistory {
+		t := &s.thoughtHistory[i]
+		if !hasType(t, TypeHypothesis) || !live(t) {
+			continue
+		}
+		verified := false
+		for j := i + 1; j < len(s.thoughtHistory) && !verified; j++ {
+			v := &s.thoughtHistory[j]
+			verified = hasType(v, TypeVerification) && (branchOf(v) == branchOf(t) ||
+				branchOf(t) == "" && *v.BranchFromThought >= t.ThoughtNumber)
+		}
+		if !verified {
+			unverified = append(unverified, t.ThoughtNumber)
+		}
+	}
+	if len(unverified) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("concluding with unverified hypothesis thoughts: %s; no verification thought follows them", joinInts(unverified))
+}
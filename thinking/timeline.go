@@ -0,0 +1,129 @@
+package thinking
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	// timelineWidth is the width of timeline exports.
+	timelineWidth = 100
+	// timelineBar is the most columns of the gap bar in timeline exports.
+	timelineBar = 20
+)
+
+// gapBefore returns how long after the previous thought in the history the
+// thought at index i was recorded, or false when either time is unknown.
+func (s *SequentialThinkingServer) gapBefore(i int) (time.Duration, bool) {
+	if i == 0 || s.thoughtHistory[i].ReceivedAt == nil || s.thoughtHistory[i-1].ReceivedAt == nil {
+		return 0, false
+	}
+	return s.thoughtHistory[i].ReceivedAt.Sub(*s.thoughtHistory[i-1].ReceivedAt), true
+}
+
+// Pacing is how long the agent took between consecutive thoughts, for
+// finding where it spends its thinking time. Durations are in seconds.
+type Pacing struct {
+	Gaps              int         `json:"gaps"`
+	ElapsedSeconds    float64     `json:"elapsedSeconds"`
+	AverageGapSeconds float64     `json:"averageGapSeconds"`
+	LongestGapSeconds float64     `json:"longestGapSeconds"`
+	SlowestThought    *ThoughtRef `json:"slowestThought,omitempty"`
+}
+
+// pacing measures the gaps between consecutive thoughts, or returns nil if
+// no gap is known.
+func (s *SequentialThinkingServer) pacing() *Pacing {
+	var total, longest time.Duration
+	gaps, slowest := 0, -1
+	for i := range s.thoughtHistory {
+		gap, ok := s.gapBefore(i)
+		if !ok {
+			continue
+		}
+		gaps++
+		total += gap
+		if slowest < 0 || gap > longest {
+			longest, slowest = gap, i
+		}
+	}
+	if gaps == 0 {
+		return nil
+	}
+	t := &s.thoughtHistory[slowest]
+	ref := refTo(t, t.Thought)
+	return &Pacing{
+		Gaps:              gaps,
+		ElapsedSeconds:    total.Seconds(),
+		AverageGapSeconds: (total / time.Duration(gaps)).Seconds(),
+		LongestGapSeconds: longest.Seconds(),
+		SlowestThought:    &ref,
+	}
+}
+
+// formatGap rounds a gap for display: to the millisecond under a second,
+// to a tenth of a second under a minute, and to the second above.
+func formatGap(d time.Duration) string {
+	switch {
+	case d < time.Second:
+		return d.Round(time.Millisecond).String()
+	case d < time.Minute:
+		return d.Round(100 * time.Millisecond).String()
+	default:
+		return d.Round(time.Second).String()
+	}
+}
+
+// renderTimeline lists the thoughts in the order they were recorded, with
+// the time each arrived, the gap since the previous one and a bar scaled to
+// the longest gap, so slow steps stand out.
+func (s *SequentialThinkingServer) renderTimeline() string {
+	var b strings.Builder
+	var longest, total time.Duration
+	gaps := 0
+	for i := range s.thoughtHistory {
+		if gap, ok := s.gapBefore(i); ok {
+			longest = max(longest, gap)
+			total += gap
+			gaps++
+		}
+	}
+	fmt.Fprintf(&b, "Timeline: %d thoughts", len(s.thoughtHistory))
+	if gaps > 0 {
+		fmt.Fprintf(&b, " over %s, %s apart on average", formatGap(total), formatGap(total/time.Duration(gaps)))
+	}
+	b.WriteString("\n\n")
+
+	for i := range s.thoughtHistory {
+		t := &s.thoughtHistory[i]
+		at := "--:--:--.---"
+		if t.ReceivedAt != nil {
+			at = t.ReceivedAt.UTC().Format("15:04:05.000")
+		}
+		gapText, bar := "", ""
+		if gap, ok := s.gapBefore(i); ok {
+			gapText = "+" + formatGap(gap)
+			if longest > 0 {
+				bar = strings.Repeat("█", int(int64(gap)*timelineBar/int64(longest)))
+			}
+		}
+		fmt.Fprintf(&b, "%s %9s %-*s %s\n", at, gapText, timelineBar, bar,
+			formatThoughtLine(t, nil, timelineWidth-timelineBar-24, Theme{}))
+	}
+	return b.String()
+}
+
+// writeTimelineLine logs a thought with the time it arrived and the gap
+// since the previous one.
+func (s *SequentialThinkingServer) writeTimelineLine(data *ThoughtData, diff []DiffOp) {
+	at := data.ReceivedAt.Format("15:04:05.000")
+	gapText := ""
+	if gap, ok := s.gapBefore(len(s.thoughtHistory) - 1); ok {
+		gapText = "+" + formatGap(gap)
+	}
+	head := fmt.Sprintf("%s %9s ", at, gapText)
+	fmt.Fprintf(os.Stderr, "%s%s\n", paintf(s.theme.Record, "%s", head),
+		formatThoughtLine(data, diff, s.logWidth()-len(head), s.theme))
+}
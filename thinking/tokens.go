@@ -0,0 +1,58 @@
+package thinking
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// TokenHeuristic is how thoughts are estimated in tokens, without running a
+// model's tokenizer.
+type TokenHeuristic string
+
+const (
+	// TokensByCharacters counts a token per four characters, the usual rule
+	// of thumb for English text.
+	TokensByCharacters TokenHeuristic = "chars"
+	// TokensByWords counts four tokens per three words, which holds up
+	// better for text with long words or code.
+	TokensByWords TokenHeuristic = "words"
+)
+
+// WithTokenHeuristic sets how thoughts are estimated in tokens, overriding
+// GOTHINK_TOKENIZER.
+func WithTokenHeuristic(h TokenHeuristic) Option {
+	return func(s *SequentialThinkingServer) {
+		s.tokenizer = h
+	}
+}
+
+func tokenHeuristicFromEnv() TokenHeuristic {
+	switch name := os.Getenv("GOTHINK_TOKENIZER"); name {
+	case "", string(TokensByCharacters):
+		return TokensByCharacters
+	case string(TokensByWords):
+		return TokensByWords
+	default:
+		fmt.Fprintf(os.Stderr, "unknown tokenizer %q; using %s\n", name, TokensByCharacters)
+		return TokensByCharacters
+	}
+}
+
+// estimate returns the estimated number of tokens in text, rounded up.
+func (h TokenHeuristic) estimate(text string) int {
+	if h == TokensByWords {
+		return (len(strings.Fields(text))*4 + 2) / 3
+	}
+	return (utf8.RuneCountInString(text) + 3) / 4
+}
+
+// sessionTokens returns the estimated tokens of every recorded thought.
+func (s *SequentialThinkingServer) sessionTokens() int {
+	total := 0
+	for i := range s.thoughtHistory {
+		total += s.thoughtHistory[i].Tokens
+	}
+	return total
+}
@@ -0,0 +1,945 @@
+package thinking
+
+import (
+	"encoding/json"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Register adds the enabled thinking tools, the live history resources and
+// the workflow prompts to srv, all backed by this server's state.
+func (s *SequentialThinkingServer) Register(srv *server.MCPServer) {
+	s.registerResources(srv)
+	s.registerPrompts(srv)
+	if s.reviewEvery > 0 {
+		srv.EnableSampling()
+	}
+	srv.AddTools(s.enabledTools()...)
+}
+
+// tools lists every tool the server provides, in registration order.
+func (s *SequentialThinkingServer) tools() []server.ServerTool {
+	return []server.ServerTool{
+		{Tool: sequentialThinkingTool, Handler: s.processThought},
+		{Tool: thinkBatchTool, Handler: s.thinkBatch},
+		{Tool: clearHistoryTool, Handler: s.clearHistory},
+		{Tool: summarizeThoughtsTool, Handler: s.summarizeThoughts},
+		{Tool: checkpointTool, Handler: s.checkpoint},
+		{Tool: restoreCheckpointTool, Handler: s.restoreCheckpoint},
+		{Tool: exportSessionTool, Handler: s.exportSession},
+		{Tool: mergeBranchesTool, Handler: s.mergeBranches},
+		{Tool: abandonBranchTool, Handler: s.abandonBranch},
+		{Tool: getBranchTool, Handler: s.getBranch},
+		{Tool: listBranchesTool, Handler: s.listBranches},
+		{Tool: describeBranchTool, Handler: s.describeBranch},
+		{Tool: searchThoughtsTool, Handler: s.searchThoughts},
+		{Tool: getCitationsTool, Handler: s.getCitations},
+		{Tool: getThoughtTool, Handler: s.getThought},
+		{Tool: tagThoughtTool, Handler: s.tagThought},
+		{Tool: getTaggedThoughtsTool, Handler: s.getTaggedThoughts},
+		{Tool: setThoughtStatusTool, Handler: s.setThoughtStatus},
+		{Tool: finalizeAnswerTool, Handler: s.finalizeAnswer},
+		{Tool: getFinalAnswerTool, Handler: s.getFinalAnswer},
+		{Tool: recordHypothesisTool, Handler: s.recordHypothesis},
+		{Tool: verifyHypothesisTool, Handler: s.verifyHypothesis},
+		{Tool: recordAssumptionTool, Handler: s.recordAssumption},
+		{Tool: updateAssumptionTool, Handler: s.updateAssumption},
+		{Tool: raiseQuestionTool, Handler: s.raiseQuestion},
+		{Tool: answerQuestionTool, Handler: s.answerQuestion},
+		{Tool: critiqueChainTool, Handler: s.critiqueChain},
+		{Tool: extractPlanTool, Handler: s.extractPlan},
+		{Tool: queryThoughtGraphTool, Handler: s.queryThoughtGraph},
+		{Tool: repairSequenceTool, Handler: s.repairSequence},
+		{Tool: splitThoughtTool, Handler: s.splitThought},
+		{Tool: importThoughtsTool, Handler: s.importThoughts},
+		{Tool: decisionMatrixTool, Handler: s.decisionMatrix},
+		{Tool: applyMentalModelTool, Handler: s.applyMentalModel},
+		{Tool: debuggingApproachTool, Handler: s.debuggingApproach},
+		{Tool: socraticQuestioningTool, Handler: s.socraticQuestioning},
+		{Tool: scientificMethodTool, Handler: s.scientificMethod},
+		{Tool: recordArgumentTool, Handler: s.recordArgument},
+		{Tool: assessKnowledgeTool, Handler: s.assessKnowledge},
+		{Tool: renderSessionTool(s.renderFormats()), Handler: s.renderSession},
+		{Tool: loadSessionTool, Handler: s.loadSession},
+	}
+}
+
+var sequentialThinkingTool = mcp.NewTool("sequentialthinking",
+	mcp.WithDescription(`A detailed tool for dynamic and reflective problem-solving through thoughts.
+This tool helps analyze problems through a flexible thinking process that can adapt and evolve.
+Each thought can build on, question, or revise previous insights as understanding deepens.
+
+When to use this tool:
+- Breaking down complex problems into steps
+- Planning and design with room for revision
+- Analysis that might need course correction
+- Problems where the full scope might not be clear initially
+- Problems that require a multi-step solution
+- Tasks that need to maintain context over multiple steps
+- Situations where irrelevant information needs to be filtered out
+
+Key features:
+- You can adjust total_thoughts up or down as you progress
+- You can question or revise previous thoughts
+- You can add more thoughts even after reaching what seemed like the end
+- You can express uncertainty and explore alternative approaches
+- Not every thought needs to build linearly - you can branch or backtrack
+- Generates a solution hypothesis
+- Verifies the hypothesis based on the Chain of Thought steps
+- Repeats the process until satisfied
+- Provides a correct answer
+
+Parameters explained:
+- thought: Your current thinking step, which can include:
+* Regular analytical steps
+* Revisions of previous thoughts
+* Questions about previous decisions
+* Realizations about needing more analysis
+* Changes in approach
+* Hypothesis generation
+* Hypothesis verification
+- next_thought_needed: True if you need more thinking, even if at what seemed like the end
+- thought_number: Current number in sequence (can go beyond initial total if needed)
+- total_thoughts: Current estimate of thoughts needed (can be adjusted up/down)
+- is_revision: A boolean indicating if this thought revises previous thinking
+- revises_thought: If is_revision is true, which thought number is being reconsidered (looked up in the current branch by default)
+- revises_branch_id: Branch holding the revised thought, when it is not the current one (empty string for the main line)
+- revises_thought_id: The id of the revised thought, as returned when it was recorded, instead of its number and branch
+- branch_from_thought: If branching, which thought number is the branching point
+- branch_id: Identifier for the current branch (if any)
+- needs_more_thoughts: If reaching end but realizing more thoughts needed
+- is_final_answer: Set on the thought that states the final answer; it is stored apart from the other thoughts and must conclude the main line
+- depends_on: Earlier thought numbers this thought relies on; revising one of them flags this thought as suspect
+- parent_thought: The earlier thought this one follows from, when it isn't the previous one on its line (e.g. going back to an earlier step)
+- thought_type: What kind of step this is (analysis, hypothesis, verification, observation, action or conclusion); hypotheses left without a later verification are flagged when the chain concludes
+- confidence: How sure you are of this thought, from 0 to 1; conclusions resting on thoughts below 0.5 are flagged
+- importance: How much this thought matters to the rest of the chain, from 0 to 1; echo_recent resurfaces the most important earlier thoughts first
+- uncertainties: What you are unsure of in this thought; they stay in every result until a later thought names them in resolves_uncertainties
+
+You should:
+1. Start with an initial estimate of needed thoughts, but be ready to adjust
+2. Feel free to question or revise previous thoughts
+3. Don't hesitate to add more thoughts if needed, even at the "end"
+4. Express uncertainty when present
+5. Mark thoughts that revise previous thinking or branch into new paths
+6. Ignore information that is irrelevant to the current step
+7. Generate a solution hypothesis when appropriate
+8. Verify the hypothesis based on the Chain of Thought steps
+9. Repeat the process until satisfied with the solution
+10. Provide a single, ideally correct answer as the final output
+11. Only set next_thought_needed to false when truly done and a satisfactory answer is reached`),
+	mcp.WithString("thought",
+		mcp.Required(),
+		mcp.MinLength(1),
+		mcp.Description("Your current thinking step"),
+	),
+	mcp.WithBoolean("nextThoughtNeeded",
+		mcp.Required(),
+		mcp.Description("Whether another thought step is needed"),
+	),
+	mcp.WithNumber("thoughtNumber",
+		mcp.Required(),
+		wholeNumber(),
+		mcp.Min(1),
+		mcp.Description("Current thought number"),
+	),
+	mcp.WithNumber("totalThoughts",
+		mcp.Required(),
+		wholeNumber(),
+		mcp.Min(1),
+		mcp.Description("Estimated total thoughts needed"),
+	),
+	mcp.WithBoolean("isRevision",
+		mcp.Description("Whether this revises previous thinking"),
+	),
+	mcp.WithNumber("revisesThought",
+		wholeNumber(),
+		mcp.Min(1),
+		mcp.Description("Which thought is being reconsidered"),
+	),
+	mcp.WithNumber("branchFromThought",
+		wholeNumber(),
+		mcp.Min(1),
+		mcp.Description("Branching point thought number"),
+	),
+	mcp.WithString("branchId",
+		mcp.MinLength(1),
+		mcp.Description("Branch identifier"),
+	),
+	mcp.WithBoolean("needsMoreThoughts",
+		mcp.Description("If more thoughts are needed"),
+	),
+	mcp.WithBoolean("isFinalAnswer",
+		mcp.Description("Whether this thought is the final answer; it must end the main line (nextThoughtNeeded false)"),
+	),
+	mcp.WithString("revisesBranchId",
+		mcp.Description("Branch containing the revised thought (defaults to the current branch, empty for the main line)"),
+	),
+	mcp.WithNumber("parentThought",
+		wholeNumber(),
+		mcp.Min(1),
+		mcp.Description("Earlier thought this one follows from (defaults to the previous thought on its line)"),
+	),
+	mcp.WithArray("dependsOn",
+		mcp.Items(map[string]any{"type": "integer", "minimum": float64(1)}),
+		mcp.Description("Earlier thoughts this one relies on"),
+	),
+	mcp.WithString("revisesThoughtId",
+		mcp.MinLength(1),
+		mcp.Description("ID of the revised thought; implies isRevision and stands for revisesThought and revisesBranchId"),
+	),
+	mcp.WithNumber("echoRecent",
+		wholeNumber(),
+		mcp.Min(0),
+		mcp.Description("Number of earlier thoughts to repeat verbatim in the result (defaults to the server setting, usually 0)"),
+	),
+	mcp.WithNumber("confidence",
+		mcp.Min(0),
+		mcp.Max(1),
+		mcp.Description("How sure you are of this thought, from 0 (guess) to 1 (certain)"),
+	),
+	mcp.WithNumber("importance",
+		mcp.Min(0),
+		mcp.Max(1),
+		mcp.Description("How much this thought matters to the rest of the chain, from 0 to 1 (0.5 if omitted); important thoughts are echoed and summarized first"),
+	),
+	mcp.WithString("thoughtType",
+		mcp.Enum(thoughtTypes...),
+		mcp.Description("What kind of step this thought is"),
+	),
+	mcp.WithArray("tags",
+		mcp.WithStringItems(),
+		mcp.Description("Free-form tags for the thought, as with tag_thought"),
+	),
+	mcp.WithString("author",
+		mcp.MinLength(1),
+		mcp.Description("Agent name or model ID of whoever wrote the thought, when several agents share the session"),
+	),
+	mcp.WithArray("citations",
+		mcp.Items(map[string]any{"type": "string", "minLength": 1}),
+		mcp.Description("URLs, file paths or resource URIs the thought rests on, as evidence"),
+	),
+	mcp.WithArray("uncertainties",
+		mcp.Items(map[string]any{"type": "string", "minLength": 1}),
+		mcp.Description("What this thought is unsure of; listed in every result until a later thought resolves it"),
+	),
+	mcp.WithArray("resolvesUncertainties",
+		mcp.Items(map[string]any{"type": "string", "minLength": 1}),
+		mcp.Description("Open uncertainties this thought settles, as listed in openUncertainties"),
+	),
+	mcp.WithObject("metadata",
+		mcp.AdditionalProperties(true),
+		mcp.Description("Client annotations of the thought (model, temperature, task ID...), stored and exported as given"),
+	),
+	mcp.WithString("createdAt",
+		mcp.Description("When you wrote this thought, as an RFC 3339 timestamp (the server records when it received it either way)"),
+	),
+	mcp.WithString("idempotencyKey",
+		mcp.Description("Unique ID of this call; retrying with the same ID returns the first call's result instead of recording the thought again"),
+	),
+	mcp.WithRawOutputSchema(thoughtResultSchema),
+)
+
+// thoughtResultSchema describes the structured result of sequentialthinking.
+// Fields attached to every result (open questions and uncertainties,
+// low-confidence topics) and fields added by result transformers are allowed
+// on top.
+var thoughtResultSchema = json.RawMessage(`{
+	"type": "object",
+	"properties": {
+		"thoughtNumber": {"type": "integer", "description": "Number of the recorded thought"},
+		"totalThoughts": {"type": "integer", "description": "Current estimate of the total, at least thoughtNumber"},
+		"nextThoughtNeeded": {"type": "boolean"},
+		"branches": {"type": "array", "items": {"type": "string"}, "description": "IDs of the branches that weren't abandoned"},
+		"thoughtHistoryLength": {"type": "integer", "description": "Number of thoughts recorded on all lines"},
+		"warnings": {"type": "array", "items": {"type": "string"}, "description": "Things the server changed or noticed about the thought, and loose ends left when the chain concludes"},
+		"id": {"type": "string", "description": "ULID of the recorded thought, which keeps naming it when thoughts are renumbered"},
+		"receivedAt": {"type": "string", "format": "date-time", "description": "When the server recorded the thought"},
+		"tokens": {"type": "integer", "description": "Estimated length of the thought in tokens"},
+		"sessionTokens": {"type": "integer", "description": "Estimated tokens of all recorded thoughts, to tell when to summarize before the context fills up"},
+		"replayed": {"type": "boolean", "description": "True when the call retried an earlier one, whose result this is; nothing new was recorded"},
+		"recentThoughts": {
+			"type": "array",
+			"description": "The most important thoughts recorded before this one (the latest among equals), oldest first, when echoRecent is set",
+			"items": {
+				"type": "object",
+				"properties": {
+					"thoughtNumber": {"type": "integer"},
+					"branchId": {"type": "string"},
+					"importance": {"type": "number"},
+					"text": {"type": "string"}
+				}
+			}
+		}
+	},
+	"required": ["thoughtNumber", "totalThoughts", "nextThoughtNeeded", "thoughtHistoryLength"]
+}`)
+
+var clearHistoryTool = mcp.NewTool("clear_history",
+	mcp.WithDescription(`Wipe the recorded thought history, all branches, and the hypotheses and final answer of the current session.
+Use this to deliberately restart the chain of thought; thought numbering starts over at 1 afterwards.`),
+)
+
+var summarizeThoughtsTool = mcp.NewTool("summarize_thoughts",
+	mcp.WithDescription(`Produce a compact structured summary of the chain of thought so far:
+key decisions, open questions, the current hypothesis, and per-branch status.
+Thoughts that were later revised are left out. Use it to compress context during long sessions.`),
+)
+
+var checkpointTool = mcp.NewTool("checkpoint",
+	mcp.WithDescription(`Label the current state of the session (thought history and branches) so it can be restored later.
+Take a checkpoint before trying a risky line of reasoning. Reusing a label replaces that checkpoint.`),
+	mcp.WithString("label",
+		mcp.Required(),
+		mcp.Description("Name of the checkpoint"),
+	),
+)
+
+var restoreCheckpointTool = mcp.NewTool("restore_checkpoint",
+	mcp.WithDescription(`Roll the session back to a labeled checkpoint, discarding every thought and branch recorded after it.
+Checkpoints themselves are kept, so the same checkpoint can be restored again.`),
+	mcp.WithString("label",
+		mcp.Required(),
+		mcp.Description("Name of the checkpoint to restore"),
+	),
+)
+
+var exportSessionTool = mcp.NewTool("export_session",
+	mcp.WithDescription(`Export the session as a bundle of Markdown documents (index, main line, one document per branch, final answer).
+The bundle is written to the server's export directory and can later be served read-only as MCP resources,
+making a completed reasoning trace available as context for future sessions.
+The obsidian format writes a note per thought instead, with YAML frontmatter and wikilinks, for an Obsidian vault.`),
+	mcp.WithString("name",
+		mcp.Description("Bundle directory name (defaults to a timestamp)"),
+	),
+	mcp.WithString("format",
+		mcp.Enum("bundle", "obsidian"),
+		mcp.Description("Layout of the export (defaults to bundle)"),
+	),
+)
+
+var mergeBranchesTool = mcp.NewTool("merge_branches",
+	mcp.WithDescription(`Resolve a branch by merging it back into the main line or into another branch.
+Records a merge thought on the target line that references the merged branch's conclusions
+(thoughts reading like decisions, plus the branch tip). A branch can only be merged once, and
+takes no more thoughts or merges after that.`),
+	mcp.WithString("branchId",
+		mcp.Required(),
+		mcp.Description("Branch to merge"),
+	),
+	mcp.WithString("into",
+		mcp.Description("Branch to merge into (defaults to the main line)"),
+	),
+	mcp.WithString("thought",
+		mcp.Description("Text of the merge thought, e.g. what the branch concluded and why it is adopted"),
+	),
+	mcp.WithBoolean("nextThoughtNeeded",
+		mcp.Description("Whether another thought step is needed after the merge (defaults to true)"),
+	),
+)
+
+var abandonBranchTool = mcp.NewTool("abandon_branch",
+	mcp.WithDescription(`Mark a branch as a dead end. Abandoned branches are dropped from the branches list in results,
+from summaries and from exports, and accept no further thoughts. Its thoughts stay in the history.`),
+	mcp.WithString("branchId",
+		mcp.Required(),
+		mcp.Description("Branch to abandon"),
+	),
+	mcp.WithString("reason",
+		mcp.Description("Why the branch is being abandoned"),
+	),
+)
+
+var getBranchTool = mcp.NewTool("get_branch",
+	mcp.WithDescription(`Fetch every thought of a branch in order, together with its branching point, creation time, description and status (open, merged or abandoned).
+Use it to review an alternative line of reasoning before deciding between branches.`),
+	mcp.WithString("branchId",
+		mcp.Required(),
+		mcp.Description("Branch to fetch"),
+	),
+)
+
+var listBranchesTool = mcp.NewTool("list_branches",
+	mcp.WithDescription(`List every branch, abandoned ones included, with its branching point, creation time, description, status
+and number of thoughts, without the thoughts themselves.`),
+	mcp.WithString("status",
+		mcp.Enum(branchStatuses...),
+		mcp.Description("Only list branches with this status"),
+	),
+)
+
+var describeBranchTool = mcp.NewTool("describe_branch",
+	mcp.WithDescription(`Set the description of a branch: what alternative it explores. Descriptions are shown by the branch
+tools and in summaries and exports.`),
+	mcp.WithString("branchId",
+		mcp.Required(),
+		mcp.Description("Branch to describe"),
+	),
+	mcp.WithString("description",
+		mcp.Required(),
+		mcp.Description("What the branch explores; empty to clear it"),
+	),
+)
+
+var searchThoughtsTool = mcp.NewTool("search_thoughts",
+	mcp.WithDescription(`Search the recorded thoughts by substring or regular expression.
+Returns the matching thought numbers with a snippet around the first match, in history order.
+Filters can restrict the search to one branch, to revisions, to tagged thoughts, or to a range of thought numbers.`),
+	mcp.WithString("query",
+		mcp.Required(),
+		mcp.Description("Text to look for, or a regular expression if regex is true"),
+	),
+	mcp.WithBoolean("regex",
+		mcp.Description("Treat query as a Go regular expression"),
+	),
+	mcp.WithBoolean("caseSensitive",
+		mcp.Description("Match case exactly (default is case-insensitive)"),
+	),
+	mcp.WithString("branchId",
+		mcp.Description("Only search this branch (empty string for the main line)"),
+	),
+	mcp.WithBoolean("revisionsOnly",
+		mcp.Description("Only search thoughts that revise earlier ones"),
+	),
+	mcp.WithString("author",
+		mcp.Description("Only search thoughts by this author"),
+	),
+	mcp.WithBoolean("allVersions",
+		mcp.Description("Also search thoughts superseded by a later revision (default is the latest version of each)"),
+	),
+	mcp.WithArray("tags",
+		mcp.WithStringItems(),
+		mcp.Description("Only search thoughts carrying any of these tags"),
+	),
+	mcp.WithNumber("fromThought",
+		mcp.Description("Lowest thought number to search"),
+	),
+	mcp.WithNumber("toThought",
+		mcp.Description("Highest thought number to search"),
+	),
+	mcp.WithNumber("limit",
+		mcp.Description("Maximum number of matches to return (default 20)"),
+	),
+)
+
+var getCitationsTool = mcp.NewTool("get_citations",
+	mcp.WithDescription(`List the URLs, file paths and resource URIs that thoughts cited as evidence, each with the thoughts citing it, in order of first citation.`),
+	mcp.WithString("kind",
+		mcp.Enum(citationKinds...),
+		mcp.Description("Only list citations of this kind"),
+	),
+	mcp.WithString("query",
+		mcp.Description("Only list citations containing this text (case-insensitive)"),
+	),
+)
+
+var getThoughtTool = mcp.NewTool("get_thought",
+	mcp.WithDescription(`Fetch a single recorded thought by number or ID, together with every thought that revised it.
+A revised thought comes back in its latest version, with its whole revision chain (v1, v2, ...) under versions; pass version to get an earlier one.
+Use it to quote or revise a thought precisely without replaying the whole history.
+Inside a branch, numbers up to the branching point resolve to the main line.`),
+	mcp.WithNumber("thoughtNumber",
+		mcp.Description("Number of the thought to fetch (required unless thoughtId is set)"),
+	),
+	mcp.WithString("thoughtId",
+		mcp.Description("ID of the thought to fetch, instead of its number and branch"),
+	),
+	mcp.WithNumber("version",
+		wholeNumber(),
+		mcp.Min(1),
+		mcp.Description("Version of the thought to return, 1 being the original (defaults to the latest)"),
+	),
+	mcp.WithString("branchId",
+		mcp.Description("Branch to look the thought up in (defaults to the main line)"),
+	),
+)
+
+var tagThoughtTool = mcp.NewTool("tag_thought",
+	mcp.WithDescription(`Attach free-form tags (e.g. "assumption", "risk", "todo") to a recorded thought, or remove them.
+Tagged thoughts can later be retrieved with get_tagged_thoughts for structured retrospectives.`),
+	mcp.WithNumber("thoughtNumber",
+		mcp.Description("Number of the thought to tag (required unless thoughtId is set)"),
+	),
+	mcp.WithString("thoughtId",
+		mcp.Description("ID of the thought to tag, instead of its number and branch"),
+	),
+	mcp.WithArray("tags",
+		mcp.Required(),
+		mcp.WithStringItems(),
+		mcp.Description("Tags to add (or remove)"),
+	),
+	mcp.WithString("branchId",
+		mcp.Description("Branch holding the thought (defaults to the main line)"),
+	),
+	mcp.WithBoolean("remove",
+		mcp.Description("Remove the given tags instead of adding them"),
+	),
+)
+
+var getTaggedThoughtsTool = mcp.NewTool("get_tagged_thoughts",
+	mcp.WithDescription(`Retrieve the thoughts carrying any of the given tags (or all of them with matchAll), in history order.
+Also lists every tag in use with its number of thoughts; call it without tags to just list them.`),
+	mcp.WithArray("tags",
+		mcp.WithStringItems(),
+		mcp.Description("Tags to look for"),
+	),
+	mcp.WithBoolean("matchAll",
+		mcp.Description("Only return thoughts carrying every given tag"),
+	),
+)
+
+var setThoughtStatusTool = mcp.NewTool("set_thought_status",
+	mcp.WithDescription(`Mark a recorded thought open, confirmed or retracted.
+Thoughts start open; revising a thought retracts it, and verifying the hypothesis a thought proposed confirms or retracts it.
+Retracted thoughts no longer count as decisions, hypotheses or open questions in summaries.`),
+	mcp.WithString("status",
+		mcp.Required(),
+		mcp.Enum(thoughtStatuses...),
+		mcp.Description("New status of the thought"),
+	),
+	mcp.WithNumber("thoughtNumber",
+		mcp.Description("Number of the thought (required unless thoughtId is set)"),
+	),
+	mcp.WithString("thoughtId",
+		mcp.Description("ID of the thought, instead of its number and branch"),
+	),
+	mcp.WithString("branchId",
+		mcp.Description("Branch holding the thought (defaults to the main line)"),
+	),
+)
+
+var finalizeAnswerTool = mcp.NewTool("finalize_answer",
+	mcp.WithDescription(`Record the final answer of the session once the chain is concluded
+(the latest main-line thought has nextThoughtNeeded set to false), or replace
+the one given by a thought with isFinalAnswer set.
+The answer is stored separately from the thoughts and included in summaries and exports.
+Recording another main-line thought reopens the chain and discards the answer.`),
+	mcp.WithString("answer",
+		mcp.Required(),
+		mcp.Description("The final answer or accepted hypothesis"),
+	),
+)
+
+var getFinalAnswerTool = mcp.NewTool("get_final_answer",
+	mcp.WithDescription(`Return the final answer of the session, recorded by finalize_answer or by a
+thought with isFinalAnswer set, and the thought it concludes.`),
+)
+
+var recordHypothesisTool = mcp.NewTool("record_hypothesis",
+	mcp.WithDescription(`Record a solution hypothesis so it can be tracked and verified explicitly.
+The hypothesis gets an ID (H1, H2, ...) and starts out open; use verify_hypothesis to attach evidence and settle it.`),
+	mcp.WithString("statement",
+		mcp.Required(),
+		mcp.Description("The hypothesis"),
+	),
+	mcp.WithNumber("thoughtNumber",
+		mcp.Description("Thought that proposed the hypothesis (defaults to the latest thought)"),
+	),
+)
+
+var verifyHypothesisTool = mcp.NewTool("verify_hypothesis",
+	mcp.WithDescription(`Attach evidence to a recorded hypothesis and update its status.
+List the thoughts supporting or refuting it, and set status to confirmed or refuted once the evidence is conclusive.`),
+	mcp.WithString("hypothesisId",
+		mcp.Required(),
+		mcp.Description("ID of the hypothesis, e.g. H1"),
+	),
+	mcp.WithString("status",
+		mcp.Enum(HypothesisOpen, HypothesisConfirmed, HypothesisRefuted),
+		mcp.Description("New status of the hypothesis"),
+	),
+	mcp.WithArray("supportingThoughts",
+		mcp.WithNumberItems(),
+		mcp.Description("Thought numbers supporting the hypothesis"),
+	),
+	mcp.WithArray("refutingThoughts",
+		mcp.WithNumberItems(),
+		mcp.Description("Thought numbers refuting the hypothesis"),
+	),
+	mcp.WithString("note",
+		mcp.Description("Short note on the verification"),
+	),
+)
+
+var recordAssumptionTool = mcp.NewTool("record_assumption",
+	mcp.WithDescription(`Register an explicit assumption the reasoning relies on. It gets an ID (A1, A2, ...) and starts out unverified.
+Concluding the chain or finalizing the answer while assumptions are unverified produces a warning.`),
+	mcp.WithString("statement",
+		mcp.Required(),
+		mcp.Description("The assumption"),
+	),
+	mcp.WithNumber("thoughtNumber",
+		mcp.Description("Thought that introduced the assumption"),
+	),
+)
+
+var updateAssumptionTool = mcp.NewTool("update_assumption",
+	mcp.WithDescription(`Mark a registered assumption as confirmed or invalidated (or back to unverified).
+An invalidated assumption usually means the thoughts built on it need revising.`),
+	mcp.WithString("assumptionId",
+		mcp.Required(),
+		mcp.Description("ID of the assumption, e.g. A1"),
+	),
+	mcp.WithString("status",
+		mcp.Required(),
+		mcp.Enum(AssumptionUnverified, AssumptionConfirmed, AssumptionInvalidated),
+		mcp.Description("New status of the assumption"),
+	),
+	mcp.WithString("note",
+		mcp.Description("How the assumption was validated"),
+	),
+)
+
+var raiseQuestionTool = mcp.NewTool("raise_question",
+	mcp.WithDescription(`Record an unresolved question (Q1, Q2, ...) so it isn't forgotten.
+Every tool result lists the unanswered questions until they are closed with answer_question.`),
+	mcp.WithString("question",
+		mcp.Required(),
+		mcp.Description("The open question"),
+	),
+	mcp.WithNumber("thoughtNumber",
+		mcp.Description("Thought that raised the question"),
+	),
+)
+
+var answerQuestionTool = mcp.NewTool("answer_question",
+	mcp.WithDescription(`Close an open question with its answer.`),
+	mcp.WithString("questionId",
+		mcp.Required(),
+		mcp.Description("ID of the question, e.g. Q1"),
+	),
+	mcp.WithString("answer",
+		mcp.Required(),
+		mcp.Description("The answer"),
+	),
+	mcp.WithNumber("thoughtNumber",
+		mcp.Description("Thought that answered the question"),
+	),
+)
+
+var critiqueChainTool = mcp.NewTool("critique_chain",
+	mcp.WithDescription(`Audit the chain of thought with server-side heuristics and return a structured list of weaknesses to address:
+missing verification, open hypotheses, unverified assumptions, unanswered questions, branches never merged or abandoned,
+large gaps in thought numbering, and very short thoughts. Each weakness has a kind, a severity and the thoughts involved.`),
+)
+
+var extractPlanTool = mcp.NewTool("extract_plan",
+	mcp.WithDescription(`Turn the chain of thought into a numbered, machine-readable action plan.
+Steps come from thoughts tagged "action", "next-step" or "todo" (see tag_thought), in history order;
+revised thoughts and abandoned branches are skipped.`),
+)
+
+var queryThoughtGraphTool = mcp.NewTool("query_thought_graph",
+	mcp.WithDescription(`Query the structure of the reasoning: the ancestors or descendants of a thought, or the path between two thoughts.
+Edges connect consecutive thoughts on a line (sequence), a branch to its branching point (branch),
+a revision to the revised thought (revision), and a merge to the merged branch's tip (merge).
+Each returned node says which kind of edge led to it.`),
+	mcp.WithNumber("thoughtNumber",
+		mcp.Required(),
+		mcp.Description("Thought to start from"),
+	),
+	mcp.WithString("branchId",
+		mcp.Description("Branch holding the thought (defaults to the main line)"),
+	),
+	mcp.WithString("query",
+		mcp.Enum("ancestors", "descendants", "path"),
+		mcp.Description("What to compute (defaults to ancestors)"),
+	),
+	mcp.WithNumber("toThoughtNumber",
+		mcp.Description("Other end of the path, for path queries"),
+	),
+	mcp.WithString("toBranchId",
+		mcp.Description("Branch holding the other end of the path (defaults to the main line)"),
+	),
+)
+
+var repairSequenceTool = mcp.NewTool("repair_sequence",
+	mcp.WithDescription(`Renumber the history consistently when thought numbers were skipped or duplicated.
+Thoughts are numbered 1..N in the order they were recorded, and every reference to them (revisions, branching points,
+merges, hypotheses, assumptions, questions, the final answer) is updated. Returns the remapping.
+Use dryRun to preview the remapping without changing anything.`),
+	mcp.WithBoolean("dryRun",
+		mcp.Description("Only report the remapping"),
+	),
+)
+
+var splitThoughtTool = mcp.NewTool("split_thought",
+	mcp.WithDescription(`Split an overly long recorded thought into several sequential thoughts at the given character offsets.
+The first part keeps the thought's number; the other parts take the following numbers, and every later thought
+(and every reference to one) is shifted up to make room. Citations, dependencies and uncertainties stay with the first
+part; every part keeps the tags and metadata.`),
+	mcp.WithNumber("thoughtNumber",
+		mcp.Required(),
+		mcp.Description("Thought to split"),
+	),
+	mcp.WithArray("offsets",
+		mcp.Required(),
+		mcp.WithNumberItems(),
+		mcp.Description("Increasing character offsets to split the text at"),
+	),
+	mcp.WithString("branchId",
+		mcp.Description("Branch holding the thought (defaults to the main line)"),
+	),
+)
+
+var importThoughtsTool = mcp.NewTool("import_thoughts",
+	mcp.WithDescription(`Load a batch of prior thoughts in one call, e.g. reasoning from another agent or a saved session.
+Each item takes the same fields as sequentialthinking. Numbers must rise along each line, branches must fork
+from a recorded main-line thought, and revisions must target a recorded thought.
+The import is all-or-nothing: if any thought is rejected, nothing is recorded.
+History dumps of the reference TypeScript server can be passed as they are in dump; snake_case field names are accepted too.`),
+	mcp.WithArray("thoughts",
+		// Items are validated against the sequentialthinking schema.
+		mcp.Items(map[string]any{
+			"type":       "object",
+			"properties": sequentialThinkingTool.InputSchema.Properties,
+			"required":   sequentialThinkingTool.InputSchema.Required,
+		}),
+		mcp.Description("Thoughts to record, in order"),
+	),
+	mcp.WithString("dump",
+		mcp.Description("A TypeScript server history dump to import instead: its thoughtHistory array, an object holding it, or one thought per line"),
+	),
+	mcp.WithBoolean("replace",
+		mcp.Description("Clear the session before importing"),
+	),
+)
+
+var thinkBatchTool = mcp.NewTool("think_batch",
+	mcp.WithDescription(`Record several consecutive thoughts in one call, saving round trips when multiple steps are ready at once.
+Each item takes the same fields as sequentialthinking and is processed exactly as if it had been sent on its own,
+in order: an item retried with its idempotencyKey is replayed rather than recorded again, and an item's echoRecent
+adds recentThoughts to its entry in the result. If any thought is rejected, none of the batch is recorded.`),
+	mcp.WithArray("thoughts",
+		mcp.Required(),
+		mcp.Items(map[string]any{"type": "object"}),
+		mcp.Description("Thoughts to record, in order"),
+	),
+)
+
+var decisionMatrixTool = mcp.NewTool("decision_matrix",
+	mcp.WithDescription(`Create or update a decision matrix scoring options against weighted criteria.
+Omit decisionId to start a new matrix (it gets an ID: D1, D2, ...); pass it to add options, criteria or scores.
+Each option's score is the weighted sum of its criterion scores; the ranking lists the best option first.
+Matrices are kept with the session and included in summaries.`),
+	mcp.WithString("decisionId",
+		mcp.Description("ID of the matrix to update, e.g. D1"),
+	),
+	mcp.WithString("question",
+		mcp.Description("What is being decided (required for a new matrix)"),
+	),
+	mcp.WithArray("options",
+		mcp.WithStringItems(),
+		mcp.Description("Options to add"),
+	),
+	mcp.WithArray("criteria",
+		mcp.Items(map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"name":   map[string]any{"type": "string"},
+				"weight": map[string]any{"type": "number", "minimum": 0, "description": "Defaults to 1"},
+			},
+			"required": []string{"name"},
+		}),
+		mcp.Description("Criteria to add or reweight"),
+	),
+	mcp.WithArray("scores",
+		mcp.Items(map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"option":    map[string]any{"type": "string"},
+				"criterion": map[string]any{"type": "string"},
+				"score":     map[string]any{"type": "number"},
+			},
+			"required": []string{"option", "criterion", "score"},
+		}),
+		mcp.Description("Scores to set for option/criterion cells"),
+	),
+	mcp.WithNumber("thoughtNumber",
+		mcp.Description("Thought the decision belongs to (defaults to the latest thought)"),
+	),
+)
+
+var applyMentalModelTool = mcp.NewTool("apply_mental_model",
+	mcp.WithDescription(`Apply a named mental model to the problem and record which thoughts it was applied to.
+Returns the model's description and steps to work through in the following thoughts.
+Available models: first_principles, inversion, occams_razor, second_order_thinking, opportunity_cost, pareto, rubber_duck.`),
+	mcp.WithString("model",
+		mcp.Required(),
+		mcp.Enum(modelNames()...),
+		mcp.Description("Mental model to apply"),
+	),
+	mcp.WithArray("thoughtNumbers",
+		mcp.WithNumberItems(),
+		mcp.Description("Thoughts the model is applied to (defaults to the latest thought)"),
+	),
+	mcp.WithString("problem",
+		mcp.Description("The problem the model is applied to"),
+	),
+	mcp.WithString("conclusion",
+		mcp.Description("What applying the model showed"),
+	),
+)
+
+var debuggingApproachTool = mcp.NewTool("debuggingapproach",
+	mcp.WithDescription(`Track a structured debugging effort: the chosen approach, the steps taken and what they found.
+Omit sessionId to start a session for an issue (it gets an ID: DBG1, DBG2, ...); pass it to log further steps
+and findings, switch approach, or record the resolution. Steps and findings are linked to a thought number.
+Approaches: binary_search, divide_and_conquer, cause_elimination, backtracking, program_slicing.`),
+	mcp.WithString("sessionId",
+		mcp.Description("ID of the debugging session to update, e.g. DBG1"),
+	),
+	mcp.WithString("issue",
+		mcp.Description("The issue being debugged (required for a new session)"),
+	),
+	mcp.WithString("approach",
+		mcp.Enum(approachNames()...),
+		mcp.Description("Debugging approach (required for a new session)"),
+	),
+	mcp.WithArray("steps",
+		mcp.WithStringItems(),
+		mcp.Description("Steps taken"),
+	),
+	mcp.WithArray("findings",
+		mcp.WithStringItems(),
+		mcp.Description("What the steps revealed"),
+	),
+	mcp.WithString("resolution",
+		mcp.Description("How the issue was resolved"),
+	),
+	mcp.WithNumber("thoughtNumber",
+		mcp.Description("Thought the steps and findings come from (defaults to the latest thought)"),
+	),
+)
+
+var socraticQuestioningTool = mcp.NewTool("socratic_questioning",
+	mcp.WithDescription(`Probe a recorded thought with Socratic questions, one per category.
+The questions are recorded like raise_question ones, so they stay attached to every result until answered
+with answer_question. Categories: clarification, assumptions, evidence, alternatives, implications, viewpoints
+(defaults to evidence, alternatives and implications).`),
+	mcp.WithNumber("thoughtNumber",
+		mcp.Required(),
+		mcp.Description("Thought to examine"),
+	),
+	mcp.WithString("branchId",
+		mcp.Description("Branch holding the thought (defaults to the main line)"),
+	),
+	mcp.WithArray("categories",
+		mcp.WithStringItems(mcp.Enum(socraticCategories()...)),
+		mcp.Description("Kinds of questions to ask"),
+	),
+)
+
+var scientificMethodTool = mcp.NewTool("scientific_method",
+	mcp.WithDescription(`Work through a scientific-method inquiry one stage at a time:
+observation → hypothesis → experiment → result → conclusion.
+Omit inquiryId to start a new inquiry (it must begin with an observation and gets an ID: S1, S2, ...).
+A stage may be repeated, a result may lead back to a new hypothesis, and a conclusion closes the inquiry;
+stages out of order are rejected. Each result lists the stages allowed next.`),
+	mcp.WithString("stage",
+		mcp.Required(),
+		mcp.Enum(inquiryStages...),
+		mcp.Description("Stage being recorded"),
+	),
+	mcp.WithString("content",
+		mcp.Required(),
+		mcp.Description("What was observed, hypothesized, tried, found or concluded"),
+	),
+	mcp.WithString("inquiryId",
+		mcp.Description("ID of the inquiry to continue, e.g. S1"),
+	),
+	mcp.WithNumber("thoughtNumber",
+		mcp.Description("Thought the stage belongs to (defaults to the latest thought)"),
+	),
+)
+
+var recordArgumentTool = mcp.NewTool("record_argument",
+	mcp.WithDescription(`Build a structured pro/con debate out of typed nodes attached to thoughts.
+A claim stands on its own; evidence supports a claim or rebuttal; a counterargument attacks a claim, evidence or
+rebuttal; a rebuttal attacks a counterargument. Nodes get IDs (ARG1, ARG2, ...) to attach further nodes to.
+The result shows the whole debate around the claim and whether each node still stands: a node falls while an
+attack on it stands.`),
+	mcp.WithString("kind",
+		mcp.Required(),
+		mcp.Enum(argumentKinds...),
+		mcp.Description("Type of node"),
+	),
+	mcp.WithString("statement",
+		mcp.Required(),
+		mcp.Description("The claim, evidence, counterargument or rebuttal"),
+	),
+	mcp.WithString("targetId",
+		mcp.Description("Node this one supports or attacks, e.g. ARG1 (not used for claims)"),
+	),
+	mcp.WithNumber("thoughtNumber",
+		mcp.Description("Thought the node belongs to (defaults to the latest thought)"),
+	),
+)
+
+var assessKnowledgeTool = mcp.NewTool("assess_knowledge",
+	mcp.WithDescription(`Take stock of what is known, assumed and unknown about a topic, and how confident you are.
+Repeated calls for the same topic add to its lists and replace the confidence; items listed as known are dropped
+from the unknowns. Confidence that doesn't match the lists is flagged as possibly over- or underconfident.
+Topics with confidence below 0.5 are listed in every tool result until reassessed.`),
+	mcp.WithString("topic",
+		mcp.Required(),
+		mcp.Description("Area of knowledge being assessed"),
+	),
+	mcp.WithNumber("confidence",
+		mcp.Required(),
+		mcp.Min(0),
+		mcp.Max(1),
+		mcp.Description("Confidence in the reasoning about the topic, from 0 to 1"),
+	),
+	mcp.WithArray("known",
+		mcp.WithStringItems(),
+		mcp.Description("Facts established about the topic"),
+	),
+	mcp.WithArray("assumed",
+		mcp.WithStringItems(),
+		mcp.Description("Things taken as true without verification"),
+	),
+	mcp.WithArray("unknown",
+		mcp.WithStringItems(),
+		mcp.Description("Open gaps in knowledge"),
+	),
+	mcp.WithNumber("thoughtNumber",
+		mcp.Description("Thought the assessment belongs to (defaults to the latest thought)"),
+	),
+)
+
+// renderSessionTool builds render_session for the formats a server provides.
+func renderSessionTool(formats []string) mcp.Tool {
+	return mcp.NewTool("render_session",
+		mcp.WithDescription(`Render the whole session as a readable document, for pasting into docs and pull requests.
+Markdown lists the numbered main-line thoughts with revision callouts, a section per branch,
+the final answer, and the hypotheses, assumptions and questions.
+Mermaid draws the thought graph (sequence, revisions, branches, merges) as a flowchart;
+DOT draws it for Graphviz, with hypotheses linked to the thoughts that support or refute them.
+HTML is a self-contained page with a tab per branch, collapsible thoughts and revision diffs.
+JSON is the canonical session document that load_session reads back; JSONL lists one thought per line.
+CSV has a row of metadata per thought (number, branch, revision, length, type) for spreadsheets.
+Org has a heading per thought with a property drawer, and open questions as TODO items.
+Messages is an OpenAI/Anthropic-compatible array of assistant turns, one per thought, for replaying the trace.
+OPML outlines the main line with each branch nested under the thought it forks from.
+Tree is a compact text tree of the main line with branch points and branch tips.
+Timeline lists thoughts in the order they arrived, with arrival times and the gaps between them.`),
+		mcp.WithString("format",
+			mcp.Enum(formats...),
+			mcp.Description("Document format (defaults to markdown)"),
+		),
+		mcp.WithString("author",
+			mcp.Description("Only render the thoughts by this author"),
+		),
+	)
+}
+
+var loadSessionTool = mcp.NewTool("load_session",
+	mcp.WithDescription(`Replace the session with one exported by render_session in the json format, here or by another gothink instance.
+Thoughts are checked as import_thoughts checks them; if any is rejected, the current session is kept.`),
+	mcp.WithString("document",
+		mcp.Required(),
+		mcp.Description("The session document, as JSON"),
+	),
+)
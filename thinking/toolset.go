@@ -0,0 +1,125 @@
+package thinking
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// toolGroups names sets of companion tools that can be switched on and off
+// together.
+var toolGroups = map[string][]string{
+	"history": {"think_batch", "clear_history", "checkpoint", "restore_checkpoint", "export_session",
+		"repair_sequence", "split_thought", "import_thoughts", "render_session", "load_session"},
+	"summary":       {"summarize_thoughts", "critique_chain", "extract_plan"},
+	"branches":      {"merge_branches", "abandon_branch", "get_branch", "list_branches", "describe_branch"},
+	"search":        {"search_thoughts", "get_thought", "get_citations", "query_thought_graph"},
+	"tags":          {"tag_thought", "get_tagged_thoughts", "set_thought_status"},
+	"answers":       {"finalize_answer", "get_final_answer"},
+	"hypotheses":    {"record_hypothesis", "verify_hypothesis"},
+	"assumptions":   {"record_assumption", "update_assumption"},
+	"questions":     {"raise_question", "answer_question", "socratic_questioning"},
+	"mental_models": {"decision_matrix", "apply_mental_model", "debuggingapproach", "scientific_method", "record_argument"},
+	"metacognition": {"assess_knowledge"},
+}
+
+// ToolConfig is the file format read by ReadToolConfig: tool or group names
+// mapped to whether they are enabled.
+type ToolConfig struct {
+	Tools map[string]bool `json:"tools"`
+}
+
+// ReadToolConfig reads a JSON tool configuration from path.
+func ReadToolConfig(path string) (map[string]bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cfg ToolConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg.Tools, nil
+}
+
+// ConfigureTools enables and disables the companion tools. Keys are tool names
+// or the names of tool groups; a tool's own entry wins over its group's.
+// Tools not mentioned are enabled, so each call replaces the previous
+// configuration. sequentialthinking can't be disabled.
+//
+// If the server is already registered, tools are added to and removed from
+// it, and connected clients get a tools/list_changed notification.
+func (s *SequentialThinkingServer) ConfigureTools(tools map[string]bool) error {
+	known := make(map[string]bool)
+	for _, t := range s.tools() {
+		known[t.Tool.Name] = true
+	}
+
+	disabled := make(map[string]bool)
+	for name, enabled := range tools {
+		if _, ok := toolGroups[name]; !ok && !known[name] {
+			return fmt.Errorf("unknown tool or group %q; groups: %s", name, strings.Join(groupNames(), ", "))
+		}
+		for _, tool := range toolGroups[name] {
+			if _, own := tools[tool]; !own && !enabled {
+				disabled[tool] = true
+			}
+		}
+		if known[name] && !enabled {
+			disabled[name] = true
+		}
+	}
+	if disabled[sequentialThinkingTool.Name] {
+		return fmt.Errorf("%s can't be disabled", sequentialThinkingTool.Name)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var added []server.ServerTool
+	var removed []string
+	for _, t := range s.tools() {
+		name := t.Tool.Name
+		switch {
+		case disabled[name] && !s.disabledTools[name]:
+			removed = append(removed, name)
+		case !disabled[name] && s.disabledTools[name]:
+			added = append(added, t)
+		}
+	}
+	s.disabledTools = disabled
+
+	if s.srv != nil {
+		if len(removed) > 0 {
+			s.srv.DeleteTools(removed...)
+		}
+		if len(added) > 0 {
+			s.srv.AddTools(added...)
+		}
+	}
+	return nil
+}
+
+// enabledTools lists the tools that aren't disabled, in registration order.
+func (s *SequentialThinkingServer) enabledTools() []server.ServerTool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.DeleteFunc(s.tools(), func(t server.ServerTool) bool {
+		return s.disabledTools[t.Tool.Name]
+	})
+}
+
+func groupNames() []string {
+	names := make([]string, 0, len(toolGroups))
+	for name := range toolGroups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,133 @@
+package thinking
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// treeLabelLength is the most characters of a thought shown in the tree.
+const treeLabelLength = 40
+
+// treeGlyphs are the characters a tree is drawn with.
+type treeGlyphs struct {
+	fork, lastFork, branch, thought, tip, arrow, ellipsis string
+}
+
+var (
+	unicodeTree = treeGlyphs{"├─", "└─", "🌿 ", "● ", " ◀ tip", " → ", "…"}
+	asciiTree   = treeGlyphs{"|- ", "`- ", "", "* ", " <- tip", " -> ", "..."}
+)
+
+// renderTree draws the structure of the session as a text tree: the main
+// line top to bottom, and each branch under the thought it forks from with
+// its thought numbers, tip and fate.
+func (s *SequentialThinkingServer) renderTree() string {
+	return s.drawTree(unicodeTree)
+}
+
+// drawTree draws the tree with the glyphs g.
+func (s *SequentialThinkingServer) drawTree(g treeGlyphs) string {
+	forks := make(map[int][]string)
+	var unrooted []string
+	for _, id := range s.allBranches() {
+		first := -1
+		for i := range s.thoughtHistory {
+			if branchOf(&s.thoughtHistory[i]) == id {
+				first = i
+				break
+			}
+		}
+		if at := s.indexBefore("", s.branchOrigin(id), first); at >= 0 {
+			forks[at] = append(forks[at], id)
+		} else {
+			unrooted = append(unrooted, id)
+		}
+	}
+
+	var b strings.Builder
+	writeBranches := func(ids []string, more bool) {
+		for k, id := range ids {
+			connector := g.fork
+			if !more && k == len(ids)-1 {
+				connector = g.lastFork
+			}
+			fmt.Fprintf(&b, "%s%s%s\n", connector, g.branch, s.treeBranch(id, g))
+		}
+	}
+
+	writeBranches(unrooted, true)
+	last := -1
+	for i := range s.thoughtHistory {
+		if branchOf(&s.thoughtHistory[i]) == "" {
+			last = i
+		}
+	}
+	for i := range s.thoughtHistory {
+		t := &s.thoughtHistory[i]
+		if branchOf(t) != "" {
+			continue
+		}
+		fmt.Fprintf(&b, "%s%s", g.thought, treeLabel(t, g))
+		switch {
+		case t.MergedBranchId != nil:
+			fmt.Fprintf(&b, " (merges %s)", *t.MergedBranchId)
+		case t.RevisesThought != nil:
+			fmt.Fprintf(&b, " (revises %d)", *t.RevisesThought)
+		}
+		if i == last {
+			b.WriteString(g.tip)
+		}
+		b.WriteString("\n")
+		writeBranches(forks[i], i != last)
+	}
+	if b.Len() == 0 {
+		return "(no thoughts)\n"
+	}
+	return b.String()
+}
+
+// treeBranch describes a branch on one line: its thought numbers ending at
+// the tip, and whether it was merged or abandoned.
+func (s *SequentialThinkingServer) treeBranch(id string, g treeGlyphs) string {
+	thoughts := s.branches[id].Thoughts
+	numbers := make([]string, len(thoughts))
+	for i, t := range thoughts {
+		numbers[i] = fmt.Sprint(t.ThoughtNumber)
+	}
+	line := fmt.Sprintf("%s: %s (tip: %s)", id, strings.Join(numbers, g.arrow), treeLabel(&thoughts[len(thoughts)-1], g))
+	if into, ok := s.mergedInto(id); ok {
+		line += ", merged into " + describeScope(into)
+	}
+	if reason, ok := s.abandonReason(id); ok {
+		line += ", abandoned"
+		if reason != "" {
+			line += ": " + reason
+		}
+	}
+	return line
+}
+
+func treeLabel(t *ThoughtData, g treeGlyphs) string {
+	text := strings.Join(strings.Fields(t.Thought), " ")
+	if runes := []rune(text); len(runes) > treeLabelLength {
+		text = string(runes[:treeLabelLength-1]) + g.ellipsis
+	}
+	return fmt.Sprintf("%d %s", t.ThoughtNumber, text)
+}
+
+// logTree logs the tree after the branch structure changed. The compact and
+// timeline logs leave it out, as a tree takes more than a line.
+func (s *SequentialThinkingServer) logTree() {
+	g, title := unicodeTree, "🌳 Thought tree"
+	if s.theme.ASCII {
+		g, title = asciiTree, "Thought tree"
+	}
+	switch s.logFormat {
+	case LogFormatCompact, LogFormatTimeline:
+	case LogFormatJSON:
+		writeLogEntry(logEntry{Kind: "tree", Message: "Thought tree", Detail: s.drawTree(g)})
+	default:
+		fmt.Fprintf(os.Stderr, "\n%s\n%s", paintf(s.theme.Success, "%s", title), Printable(s.drawTree(g)))
+	}
+}
@@ -0,0 +1,103 @@
+package thinking
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// snakeCaseFields maps the snake_case parameter names used in the reference
+// TypeScript server's tool description to the fields they stand for.
+var snakeCaseFields = map[string]string{
+	"thought_number":      "thoughtNumber",
+	"total_thoughts":      "totalThoughts",
+	"next_thought_needed": "nextThoughtNeeded",
+	"is_revision":         "isRevision",
+	"revises_thought":     "revisesThought",
+	"branch_from_thought": "branchFromThought",
+	"branch_id":           "branchId",
+	"needs_more_thoughts": "needsMoreThoughts",
+	"revises_branch_id":   "revisesBranchId",
+}
+
+// normalizeThought renames snake_case fields to the names this server uses.
+// Fields given both ways keep the camelCase value.
+func normalizeThought(item map[string]any) map[string]any {
+	normalized := make(map[string]any, len(item))
+	for k, v := range item {
+		if camel, ok := snakeCaseFields[k]; ok {
+			if _, both := item[camel]; !both {
+				normalized[camel] = v
+			}
+			continue
+		}
+		normalized[k] = v
+	}
+	return normalized
+}
+
+// typeScriptThoughts reads a history dump of the reference TypeScript
+// sequentialthinking server: its thoughtHistory array, alone or in an object
+// with the branches record (which only repeats the history), or one thought
+// per line. Field names may be camelCase or snake_case.
+func typeScriptThoughts(dump []byte) ([]map[string]any, error) {
+	var items []any
+	var state struct {
+		ThoughtHistory []any `json:"thoughtHistory"`
+	}
+	switch trimmed := bytes.TrimSpace(dump); {
+	case bytes.HasPrefix(trimmed, []byte("[")):
+		if err := json.Unmarshal(trimmed, &items); err != nil {
+			return nil, err
+		}
+	case json.Unmarshal(trimmed, &state) == nil && state.ThoughtHistory != nil:
+		items = state.ThoughtHistory
+	default:
+		dec := json.NewDecoder(bytes.NewReader(trimmed))
+		for {
+			var item any
+			err := dec.Decode(&item)
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("no thoughts found")
+	}
+
+	thoughts := make([]map[string]any, len(items))
+	for i, item := range items {
+		m, ok := item.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("thoughts[%d]: must be an object", i)
+		}
+		thoughts[i] = normalizeThought(m)
+	}
+	return thoughts, nil
+}
+
+// typeScriptSession wraps a TypeScript server dump in a session document.
+func typeScriptSession(dump []byte) (*SessionDocument, error) {
+	thoughts, err := typeScriptThoughts(dump)
+	if err != nil {
+		return nil, err
+	}
+	doc := &SessionDocument{Format: SessionFormat, Version: SessionVersion}
+	for i, m := range thoughts {
+		jsonBytes, _ := json.Marshal(m)
+		var data ThoughtData
+		if err := json.Unmarshal(jsonBytes, &data); err != nil {
+			return nil, fmt.Errorf("thoughts[%d]: %s", i, strings.TrimPrefix(err.Error(), "json: "))
+		}
+		doc.Thoughts = append(doc.Thoughts, data)
+	}
+	return doc, nil
+}
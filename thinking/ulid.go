@@ -0,0 +1,94 @@
+package thinking
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// crockford is the Crockford base32 alphabet ULIDs are written in.
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ulids holds the last ULID made, for monotonic ones.
+var ulids struct {
+	sync.Mutex
+	ms      uint64
+	entropy [10]byte
+}
+
+// newULID returns a ULID for a thought recorded at t: a 48-bit millisecond
+// timestamp followed by 80 random bits, written as 26 characters that sort
+// in recording order. Within the same millisecond, or if the clock goes
+// back, the last ULID's random bits are incremented instead of drawn anew,
+// as in the ULID spec's monotonic mode.
+func newULID(t time.Time) string {
+	ulids.Lock()
+	ms := max(uint64(t.UnixMilli()), ulids.ms)
+	if ms == ulids.ms {
+		for i := len(ulids.entropy) - 1; i >= 0; i-- {
+			ulids.entropy[i]++
+			if ulids.entropy[i] != 0 {
+				break
+			}
+		}
+	} else {
+		rand.Read(ulids.entropy[:])
+	}
+	ulids.ms = ms
+	var b [16]byte
+	binary.BigEndian.PutUint64(b[:8], ms<<16)
+	copy(b[6:], ulids.entropy[:])
+	ulids.Unlock()
+
+	hi, lo := binary.BigEndian.Uint64(b[:8]), binary.BigEndian.Uint64(b[8:])
+	var id [26]byte
+	for i := len(id) - 1; i >= 0; i-- {
+		id[i] = crockford[lo&31]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(id[:])
+}
+
+// thoughtByID returns the recorded thought with the given ID, or nil if
+// there is none.
+func (s *SequentialThinkingServer) thoughtByID(id string) *ThoughtData {
+	for i := range s.thoughtHistory {
+		if s.thoughtHistory[i].Id == id {
+			return &s.thoughtHistory[i]
+		}
+	}
+	return nil
+}
+
+// resolveRevisionID points a revision given by revisesThoughtId at the
+// number and line of that thought, which can't be ambiguous.
+func (s *SequentialThinkingServer) resolveRevisionID(data *ThoughtData) error {
+	if data.RevisesThoughtId == nil {
+		return nil
+	}
+	id := *data.RevisesThoughtId
+	target := s.thoughtByID(id)
+	if target == nil {
+		return invalid("revisesThoughtId", "recorded thought ID", id, "thought %s does not exist", id)
+	}
+	line := branchOf(target)
+	if data.RevisesThought != nil && *data.RevisesThought != target.ThoughtNumber {
+		return invalid("revisesThought", fmt.Sprint(target.ThoughtNumber), *data.RevisesThought,
+			"is %d but thought %s is thought %d; set revisesThought or revisesThoughtId, not both", *data.RevisesThought, id, target.ThoughtNumber)
+	}
+	if data.RevisesBranchId != nil && *data.RevisesBranchId != line {
+		return invalid("revisesBranchId", strconv.Quote(line), *data.RevisesBranchId,
+			"is %q but thought %s is in %s; leave revisesBranchId out when revising by ID", *data.RevisesBranchId, id, describeScope(line))
+	}
+	n := target.ThoughtNumber
+	data.RevisesThought, data.RevisesBranchId = &n, &line
+	if data.IsRevision == nil {
+		isRevision := true
+		data.IsRevision = &isRevision
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package thinking
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewULIDOrder(t *testing.T) {
+	now := time.Now()
+	times := []time.Time{now, now, now, now.Add(-time.Second), now.Add(time.Millisecond)}
+	for range 1000 {
+		times = append(times, now.Add(time.Millisecond))
+	}
+	previous := ""
+	for i, at := range times {
+		id := newULID(at)
+		if len(id) != 26 {
+			t.Fatalf("ULID %q has %d characters, want 26", id, len(id))
+		}
+		if id <= previous {
+			t.Fatalf("ULID %d %s doesn't sort after %s", i, id, previous)
+		}
+		previous = id
+	}
+}
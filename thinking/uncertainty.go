@@ -0,0 +1,67 @@
+package thinking
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// openUncertainties lists the uncertainties of standing thoughts that no
+// later standing thought resolved, oldest first. Revising, retracting or
+// abandoning a thought drops its uncertainties along with it.
+func (s *SequentialThinkingServer) openUncertainties() []ThoughtRef {
+	live := s.liveThoughts()
+	var open []ThoughtRef
+	for i := range s.thoughtHistory {
+		t := &s.thoughtHistory[i]
+		if !live(t) {
+			continue
+		}
+		for _, text := range t.ResolvesUncertainties {
+			open = slices.DeleteFunc(open, func(u ThoughtRef) bool {
+				return strings.EqualFold(u.Text, text)
+			})
+		}
+		for _, text := range t.Uncertainties {
+			open = append(open, ThoughtRef{ThoughtNumber: t.ThoughtNumber, BranchId: branchOf(t), Text: text})
+		}
+	}
+	return open
+}
+
+// resolveUncertainties checks that each uncertainty a thought resolves is
+// outstanding, and spells it as it was raised.
+func (s *SequentialThinkingServer) resolveUncertainties(data *ThoughtData) error {
+	if len(data.ResolvesUncertainties) == 0 {
+		return nil
+	}
+	open := s.openUncertainties()
+	for i, text := range data.ResolvesUncertainties {
+		found := false
+		for _, u := range open {
+			if strings.EqualFold(u.Text, text) {
+				data.ResolvesUncertainties[i], found = u.Text, true
+				break
+			}
+		}
+		if !found {
+			return invalid("resolvesUncertainties", "one of the open uncertainties", text,
+				"%q is not an open uncertainty; open uncertainties are listed in openUncertainties", text)
+		}
+	}
+	return nil
+}
+
+// openUncertaintiesWarning flags the uncertainties left open when the chain
+// concludes, or returns "" if there are none.
+func (s *SequentialThinkingServer) openUncertaintiesWarning() string {
+	open := s.openUncertainties()
+	if len(open) == 0 {
+		return ""
+	}
+	quoted := make([]string, len(open))
+	for i, u := range open {
+		quoted[i] = fmt.Sprintf("%q (thought %d)", u.Text, u.ThoughtNumber)
+	}
+	return fmt.Sprintf("concluding with open uncertainties: %s", strings.Join(quoted, ", "))
+}
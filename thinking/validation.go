@@ -0,0 +1,210 @@
+package thinking
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"os"
+	"slices"
+	"strings"
+)
+
+// ValidationMode is how strictly thought arguments are checked.
+type ValidationMode string
+
+const (
+	// ValidationStandard rejects arguments of the wrong type and
+	// inconsistent references, and ignores unknown arguments.
+	ValidationStandard ValidationMode = "standard"
+	// ValidationStrict also rejects unknown arguments and numbers written
+	// as strings.
+	ValidationStrict ValidationMode = "strict"
+	// ValidationLenient coerces what it can, drops what it can't and
+	// records the thought, with a warning for each change.
+	ValidationLenient ValidationMode = "lenient"
+)
+
+var validationModes = []ValidationMode{ValidationStandard, ValidationStrict, ValidationLenient}
+
+// WithValidation sets how strictly thought arguments are checked,
+// overriding GOTHINK_VALIDATION.
+func WithValidation(mode ValidationMode) Option {
+	return func(s *SequentialThinkingServer) {
+		s.validation = mode
+	}
+}
+
+func validationModeFromEnv() ValidationMode {
+	name := os.Getenv("GOTHINK_VALIDATION")
+	if name == "" {
+		return ValidationStandard
+	}
+	for _, mode := range validationModes {
+		if string(mode) == name {
+			return mode
+		}
+	}
+	fmt.Fprintf(os.Stderr, "unknown validation mode %q; using %s\n", name, ValidationStandard)
+	return ValidationStandard
+}
+
+// screen prepares the arguments of a thought for validateThoughtData
+// according to the validation mode: strict mode rejects unknown arguments
+// and numbers written as strings, and lenient mode ignores them and repairs what it can, returning a
+// repaired copy and what it did.
+func (s *SequentialThinkingServer) screen(args map[string]any) (map[string]any, []string, error) {
+	properties := sequentialThinkingTool.InputSchema.Properties
+	var unknown []string
+	for name := range args {
+		if _, ok := properties[name]; !ok {
+			unknown = append(unknown, name)
+		}
+	}
+	slices.Sort(unknown)
+
+	switch s.validation {
+	case ValidationStrict:
+		if len(unknown) > 0 {
+			known := strings.Join(slices.Sorted(maps.Keys(properties)), ", ")
+			return nil, nil, &ValidationError{
+				Code:     "UNKNOWN_PARAMETERS",
+				Expected: known,
+				Received: unknown,
+				Message:  fmt.Sprintf("invalid arguments: unknown parameters %s; expected %s", strings.Join(unknown, ", "), known),
+			}
+		}
+		// Numbers are only read from strings outside strict mode, in
+		// arguments and in array items alike.
+		for _, name := range slices.Sorted(maps.Keys(args)) {
+			property, _ := properties[name].(map[string]any)
+			if numericString(property, args[name]) {
+				return nil, nil, invalid(name, property["type"].(string), args[name], "must be a number")
+			}
+			items, _ := property["items"].(map[string]any)
+			list, _ := args[name].([]any)
+			for i, item := range list {
+				if numericString(items, item) {
+					problem := invalid(name, items["type"].(string), item, "item %d must be a number", i)
+					problem.Path = fmt.Sprintf("%s/%d", problem.Path, i)
+					return nil, nil, problem
+				}
+			}
+		}
+		return args, nil, nil
+	case ValidationLenient:
+		var warnings []string
+		if len(unknown) > 0 {
+			warnings = append(warnings, fmt.Sprintf("ignored unknown parameters %s", strings.Join(unknown, ", ")))
+		}
+		args, repairs := loosen(args, properties)
+		return args, append(warnings, repairs...), nil
+	default:
+		return args, nil, nil
+	}
+}
+
+// numericString reports whether val is a string given for a number property.
+func numericString(property map[string]any, val any) bool {
+	_, ok := val.(string)
+	return ok && (property["type"] == "integer" || property["type"] == "number")
+}
+
+// loosen returns a copy of args fit for validation: booleans written as
+// strings are read, fractional numbers rounded, optional arguments of the
+// wrong type and array items that don't fit dropped, and revision and branch
+// parameters missing their counterpart completed or dropped. It describes
+// each change.
+func loosen(args map[string]any, properties map[string]any) (map[string]any, []string) {
+	args = maps.Clone(args)
+	required := sequentialThinkingTool.InputSchema.Required
+	var changes []string
+	for _, name := range slices.Sorted(maps.Keys(args)) {
+		property, ok := properties[name].(map[string]any)
+		if !ok {
+			continue
+		}
+		val := args[name]
+		switch property["type"] {
+		case "boolean":
+			if text, ok := val.(string); ok && (text == "true" || text == "false") {
+				args[name] = text == "true"
+				changes = append(changes, fmt.Sprintf("read %s %q as a boolean", name, text))
+				continue
+			}
+			if _, ok := val.(bool); ok {
+				continue
+			}
+		case "integer":
+			if num, ok := number(val); ok {
+				if num != math.Trunc(num) {
+					args[name] = math.Round(num)
+					changes = append(changes, fmt.Sprintf("rounded %s %v to %v", name, val, math.Round(num)))
+				}
+				continue
+			}
+		case "number":
+			if _, ok := number(val); ok {
+				continue
+			}
+		case "string":
+			if _, ok := val.(string); ok {
+				continue
+			}
+		case "object":
+			if _, ok := val.(map[string]any); ok {
+				continue
+			}
+		case "array":
+			if list, ok := val.([]any); ok {
+				items, _ := property["items"].(map[string]any)
+				kept := make([]any, 0, len(list))
+				for i, item := range list {
+					if problem := checkProperty(name, items, item); problem != nil {
+						changes = append(changes, fmt.Sprintf("ignored %s item %d: %s", name, i, strings.TrimPrefix(problem.Message, "invalid "+name+": ")))
+						continue
+					}
+					kept = append(kept, item)
+				}
+				args[name] = kept
+				continue
+			}
+		default:
+			continue
+		}
+		if !slices.Contains(required, name) {
+			delete(args, name)
+			changes = append(changes, fmt.Sprintf("ignored %s: %s", name, typeProblem(property)))
+		}
+	}
+
+	if id, ok := args["branchId"].(string); ok && id == "" {
+		delete(args, "branchId")
+		changes = append(changes, "ignored branchId: must not be empty")
+	}
+	isRevision, _ := args["isRevision"].(bool)
+	_, revises := args["revisesThought"]
+	_, byID := args["revisesThoughtId"]
+	switch {
+	case revises && !isRevision:
+		args["isRevision"] = true
+		changes = append(changes, "set isRevision, as revisesThought is set")
+	case isRevision && !revises && !byID:
+		delete(args, "isRevision")
+		changes = append(changes, "ignored isRevision: revisesThought is missing")
+	}
+	if _, ok := args["revisesBranchId"]; ok && !revises {
+		delete(args, "revisesBranchId")
+		changes = append(changes, "ignored revisesBranchId: revisesThought is missing")
+	}
+	_, branch := args["branchId"]
+	_, from := args["branchFromThought"]
+	switch {
+	case branch && !from:
+		delete(args, "branchId")
+		changes = append(changes, "ignored branchId: branchFromThought is missing")
+	case from && !branch:
+		delete(args, "branchFromThought")
+		changes = append(changes, "ignored branchFromThought: branchId is missing")
+	}
+	return args, changes
+}
@@ -0,0 +1,102 @@
+package thinking
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestLenientValidation(t *testing.T) {
+	tests := []struct {
+		name  string
+		extra map[string]any
+		check func(t *testing.T, th *ThoughtData)
+	}{
+		{
+			name:  "boolean written as a string",
+			extra: map[string]any{"needsMoreThoughts": "true"},
+			check: func(t *testing.T, th *ThoughtData) {
+				if th.NeedsMoreThoughts == nil || !*th.NeedsMoreThoughts {
+					t.Errorf("needsMoreThoughts = %v, want true", th.NeedsMoreThoughts)
+				}
+			},
+		},
+		{
+			name:  "number of the wrong type",
+			extra: map[string]any{"confidence": "high"},
+			check: func(t *testing.T, th *ThoughtData) {
+				if th.Confidence != nil {
+					t.Errorf("confidence = %v, want none", *th.Confidence)
+				}
+			},
+		},
+		{
+			name:  "array of the wrong type",
+			extra: map[string]any{"tags": "risk"},
+			check: func(t *testing.T, th *ThoughtData) {
+				if th.Tags != nil {
+					t.Errorf("tags = %v, want none", th.Tags)
+				}
+			},
+		},
+		{
+			name:  "array items of the wrong type",
+			extra: map[string]any{"dependsOn": []any{"a", 1.0}},
+			check: func(t *testing.T, th *ThoughtData) {
+				if !slices.Equal(th.DependsOn, []int{1}) {
+					t.Errorf("dependsOn = %v, want [1]", th.DependsOn)
+				}
+			},
+		},
+		{
+			name:  "object of the wrong type",
+			extra: map[string]any{"metadata": "x"},
+			check: func(t *testing.T, th *ThoughtData) {
+				if th.Metadata != nil {
+					t.Errorf("metadata = %v, want none", th.Metadata)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t, WithValidation(ValidationLenient))
+			mustCall(t, s.processThought, thought(1, 2, "a", nil))
+			fields := mustCall(t, s.processThought, thought(2, 2, "b", tt.extra))
+			if warnings, _ := fields["warnings"].([]any); len(warnings) == 0 {
+				t.Errorf("the repair was not reported: %v", fields)
+			}
+			tt.check(t, &s.thoughtHistory[1])
+		})
+	}
+}
+
+func TestStrictValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		extra    map[string]any
+		wantPath string
+	}{
+		{name: "unknown parameter", extra: map[string]any{"mood": "good"}},
+		{name: "number written as a string", extra: map[string]any{"confidence": "0.5"}, wantPath: "/confidence"},
+		{name: "array item written as a string", extra: map[string]any{"dependsOn": []any{1.0, "1"}}, wantPath: "/dependsOn/1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t, WithValidation(ValidationStrict))
+			mustCall(t, s.processThought, thought(1, 2, "a", nil))
+			fields, isError := callTool(t, s.processThought, thought(2, 2, "b", tt.extra))
+			if !isError {
+				t.Fatalf("strict validation accepted %v", tt.extra)
+			}
+			if tt.wantPath != "" && fields["path"] != tt.wantPath {
+				t.Errorf("path = %v, want %s", fields["path"], tt.wantPath)
+			}
+
+			// Standard validation reads the same arguments.
+			s.validation = ValidationStandard
+			if _, isError := callTool(t, s.processThought, thought(2, 2, "b", tt.extra)); isError {
+				t.Errorf("standard validation rejected %v", tt.extra)
+			}
+		})
+	}
+}
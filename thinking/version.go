@@ -0,0 +1,66 @@
+package thinking
+
+// revisionRoots returns, for each history index, the index of the original
+// thought that the thought there revises, directly or through earlier
+// revisions, or the index itself if it revises nothing. A revision revises
+// the latest thought recorded before it with the number and line it names.
+func (s *SequentialThinkingServer) revisionRoots() []int {
+	type target struct {
+		branchId string
+		number   int
+	}
+	latest := make(map[target]int)
+	roots := make([]int, len(s.thoughtHistory))
+	for i := range s.thoughtHistory {
+		t := &s.thoughtHistory[i]
+		roots[i] = i
+		if t.RevisesThought != nil && t.RevisesBranchId != nil {
+			if j, ok := latest[target{*t.RevisesBranchId, *t.RevisesThought}]; ok {
+				roots[i] = roots[j]
+			}
+		}
+		latest[target{branchOf(t), t.ThoughtNumber}] = i
+	}
+	return roots
+}
+
+// versionsOf returns the history indices of every version of the thought at
+// index i, the original first, in recording order: the last one is the
+// latest.
+func (s *SequentialThinkingServer) versionsOf(i int) []int {
+	roots := s.revisionRoots()
+	var versions []int
+	for j, root := range roots {
+		if root == roots[i] {
+			versions = append(versions, j)
+		}
+	}
+	return versions
+}
+
+// nextVersion returns the version a thought about to be recorded gets: one
+// past the latest version of the thought it revises, or 1.
+func (s *SequentialThinkingServer) nextVersion(data *ThoughtData) int {
+	if data.RevisesThought == nil || data.RevisesBranchId == nil {
+		return 1
+	}
+	target := s.indexBefore(*data.RevisesBranchId, *data.RevisesThought, len(s.thoughtHistory))
+	if target < 0 {
+		return 1
+	}
+	return len(s.versionsOf(target)) + 1
+}
+
+// superseded returns a predicate telling whether the thought at a history
+// index has a later version. The versions are worked out once, when it is
+// made.
+func (s *SequentialThinkingServer) superseded() func(int) bool {
+	roots := s.revisionRoots()
+	latest := make(map[int]int, len(roots))
+	for i, root := range roots {
+		latest[root] = i
+	}
+	return func(i int) bool {
+		return latest[roots[i]] != i
+	}
+}
@@ -0,0 +1,46 @@
+package thinking
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestVersions(t *testing.T) {
+	s := newTestServer(t)
+	mustCall(t, s.processThought, thought(1, 5, "alpha", nil))
+	mustCall(t, s.processThought, thought(2, 5, "b", nil))
+	mustCall(t, s.processThought, thought(3, 5, "alpha again", map[string]any{"isRevision": true, "revisesThought": 1.0}))
+	mustCall(t, s.processThought, thought(4, 5, "alpha once more", map[string]any{"isRevision": true, "revisesThought": 3.0}))
+	mustCall(t, s.processThought, thought(5, 5, "b again", map[string]any{"isRevision": true, "revisesThought": 2.0}))
+
+	gotVersions := make([]int, len(s.thoughtHistory))
+	for i, th := range s.thoughtHistory {
+		gotVersions[i] = th.Version
+	}
+	if want := []int{1, 1, 2, 3, 2}; !slices.Equal(gotVersions, want) {
+		t.Errorf("versions = %v, want %v", gotVersions, want)
+	}
+	if got := s.versionsOf(2); !slices.Equal(got, []int{0, 2, 3}) {
+		t.Errorf("versionsOf(2) = %v, want [0 2 3]", got)
+	}
+
+	superseded := s.superseded()
+	var latest []int
+	for i := range s.thoughtHistory {
+		if !superseded(i) {
+			latest = append(latest, i)
+		}
+	}
+	if !slices.Equal(latest, []int{3, 4}) {
+		t.Errorf("latest versions at %v, want [3 4]", latest)
+	}
+
+	fields := mustCall(t, s.searchThoughts, map[string]any{"query": "alpha"})
+	if fields["total"] != 1.0 {
+		t.Errorf("search found %v latest versions, want 1", fields["total"])
+	}
+	fields = mustCall(t, s.searchThoughts, map[string]any{"query": "alpha", "allVersions": true})
+	if fields["total"] != 3.0 {
+		t.Errorf("search found %v versions, want 3", fields["total"])
+	}
+}
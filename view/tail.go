@@ -0,0 +1,148 @@
+package view
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anuramat/gothink/thinking"
+)
+
+// tailRetry is how long Tail waits before reconnecting to a server that went
+// away.
+const tailRetry = 2 * time.Second
+
+// Tail follows the thoughts of a server running with a dashboard at address
+// (a host:port or a URL), printing them to out as they are recorded. It
+// starts with the last thoughts already recorded, or all of them if last is
+// negative, and reconnects until ctx is done.
+func Tail(ctx context.Context, address string, last int, out io.Writer) error {
+	if !strings.Contains(address, "://") {
+		address = "http://" + address
+	}
+	endpoint, err := url.JoinPath(address, "thoughts")
+	if err != nil {
+		return err
+	}
+	if last >= 0 {
+		endpoint += "?last=" + strconv.Itoa(last)
+	}
+
+	// Thoughts are colored on terminals only.
+	theme := thinking.Theme{}
+	if f, ok := out.(*os.File); ok {
+		theme = thinking.ThemeFromEnv(f)
+	}
+
+	lastEventID := ""
+	connected := false
+	for {
+		err := tailStream(ctx, endpoint, &lastEventID, out, theme, func() {
+			if connected {
+				fmt.Fprintln(out, dimStyle.Sprint("Reconnected."))
+			}
+			connected = true
+		})
+		if ctx.Err() != nil {
+			return nil
+		}
+		if !connected {
+			return err
+		}
+		fmt.Fprintln(out, dimStyle.Sprintf("Connection lost (%v), reconnecting…", err))
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(tailRetry):
+		}
+	}
+}
+
+// tailStream prints the events of one connection, keeping track of the last
+// event ID to resume from.
+func tailStream(ctx context.Context, endpoint string, lastEventID *string, out io.Writer, theme thinking.Theme, onConnect func()) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	if *lastEventID != "" {
+		req.Header.Set("Last-Event-ID", *lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s", endpoint, resp.Status)
+	}
+	onConnect()
+
+	var event, id string
+	var data []string
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		field, value, _ := strings.Cut(scanner.Text(), ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			event = value
+		case "id":
+			id = value
+		case "data":
+			data = append(data, value)
+		case "":
+			if len(data) > 0 {
+				printEvent(out, theme, event, strings.Join(data, "\n"))
+			}
+			if id != "" {
+				*lastEventID = id
+			}
+			event, id, data = "", "", nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return errors.New("server closed the stream")
+}
+
+func printEvent(out io.Writer, theme thinking.Theme, event, data string) {
+	switch event {
+	case "thought":
+		var t thinking.ThoughtData
+		if err := json.Unmarshal([]byte(data), &t); err != nil {
+			fmt.Fprintln(out, dimStyle.Sprintf("Unreadable thought: %v", err))
+			return
+		}
+		fmt.Fprintln(out, thinking.FormatThought(&t, outputWidth(out), theme))
+	case "reset":
+		var sessionID string
+		json.Unmarshal([]byte(data), &sessionID)
+		rule := "──"
+		if theme.ASCII {
+			rule = "--"
+		}
+		fmt.Fprintln(out, titleStyle.Sprintf("\n%s New session %s %s", rule, thinking.Printable(sessionID), rule))
+	}
+}
+
+// outputWidth returns the width of the terminal out writes to, or 80 when
+// it isn't one.
+func outputWidth(out io.Writer) int {
+	if f, ok := out.(*os.File); ok {
+		width, _ := terminalSize(int(f.Fd()))
+		return width
+	}
+	return 80
+}
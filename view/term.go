@@ -0,0 +1,29 @@
+package view
+
+import (
+	"os"
+
+	"golang.org/x/term"
+)
+
+// makeRaw puts the terminal into raw mode and returns a function restoring
+// its previous state.
+func makeRaw(fd int) (func(), error) {
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return nil, err
+	}
+	return func() { term.Restore(fd, state) }, nil
+}
+
+// terminalSize returns the width and height of the terminal, falling back
+// to 80x24. Windows input handles have no size, so standard output is asked
+// too.
+func terminalSize(fd int) (int, int) {
+	for _, fd := range []int{fd, int(os.Stdout.Fd())} {
+		if width, height, err := term.GetSize(fd); err == nil && width > 0 && height > 0 {
+			return width, height
+		}
+	}
+	return 80, 24
+}
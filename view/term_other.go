@@ -0,0 +1,10 @@
+//go:build !(linux || darwin || dragonfly || freebsd || netbsd || openbsd)
+
+package view
+
+import "os"
+
+// notifyResize does nothing: Windows consoles report resizes as input
+// records rather than signals, so the view keeps its size until a key is
+// pressed.
+func notifyResize(c chan<- os.Signal) {}
@@ -0,0 +1,471 @@
+// Package view is a terminal viewer for saved thinking sessions.
+package view
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/anuramat/gothink/thinking"
+	"github.com/fatih/color"
+	"github.com/mattn/go-runewidth"
+)
+
+// line is the main line or a branch, with the indexes of its thoughts in
+// the session document.
+type line struct {
+	id       string
+	from     int
+	status   string
+	thoughts []int
+}
+
+// viewer is the state of the terminal UI.
+type viewer struct {
+	doc      *thinking.SessionDocument
+	lines    []line
+	current  int
+	selected int
+	offset   int
+	scroll   int
+	width    int
+	height   int
+	// markdown is the glamour style thoughts are rendered in, and text
+	// caches the rendered thoughts by index for textWidth columns.
+	markdown  string
+	text      map[int][]string
+	textWidth int
+}
+
+type key int
+
+const (
+	keyNone key = iota
+	keyQuit
+	keyUp
+	keyDown
+	keyLeft
+	keyRight
+	keyHome
+	keyEnd
+	keyPageUp
+	keyPageDown
+)
+
+var (
+	titleStyle   = color.New(color.Bold)
+	tabStyle     = color.New(color.ReverseVideo)
+	selectStyle  = color.New(color.FgCyan, color.Bold)
+	dimStyle     = color.New(color.Faint)
+	revisedStyle = color.New(color.FgYellow)
+	mergeStyle   = color.New(color.FgMagenta)
+	deleteStyle  = color.New(color.FgRed, color.CrossedOut)
+	insertStyle  = color.New(color.FgGreen)
+)
+
+// Run shows the session on the terminal until the user quits. in must be a
+// terminal; it is switched to raw mode for the duration.
+func Run(doc *thinking.SessionDocument, in *os.File, out io.Writer) error {
+	restore, err := makeRaw(int(in.Fd()))
+	if err != nil {
+		return fmt.Errorf("view needs a terminal: %w", err)
+	}
+	defer restore()
+
+	v := newViewer(doc)
+	if f, ok := out.(*os.File); ok {
+		// On Windows this also turns on escape sequence processing for f.
+		v.markdown = thinking.ThemeFromEnv(f).Markdown
+	}
+	v.width, v.height = terminalSize(int(in.Fd()))
+
+	w := bufio.NewWriter(out)
+	fmt.Fprint(w, "\x1b[?1049h\x1b[?25l")
+	defer func() {
+		fmt.Fprint(w, "\x1b[?25h\x1b[?1049l")
+		w.Flush()
+	}()
+
+	keys := make(chan key)
+	go readKeys(in, keys)
+	winch := make(chan os.Signal, 1)
+	notifyResize(winch)
+	defer signal.Stop(winch)
+
+	for {
+		v.draw(w)
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		select {
+		case <-winch:
+			v.width, v.height = terminalSize(int(in.Fd()))
+		case k, ok := <-keys:
+			if !ok || k == keyQuit {
+				return nil
+			}
+			// Where resizes aren't signaled, they show on the next key.
+			v.width, v.height = terminalSize(int(in.Fd()))
+			v.handle(k)
+		}
+	}
+}
+
+func newViewer(doc *thinking.SessionDocument) *viewer {
+	v := &viewer{doc: doc, lines: []line{{}}}
+	index := make(map[string]int)
+	for _, b := range doc.Branches {
+		l := line{id: b.ID, from: b.BranchFromThought}
+		switch {
+		case b.AbandonReason != nil:
+			l.status = "abandoned"
+		case b.MergedInto != nil:
+			l.status = "merged"
+		}
+		index[b.ID] = len(v.lines)
+		v.lines = append(v.lines, l)
+	}
+	for i, t := range doc.Thoughts {
+		id := branchOf(&t)
+		n, ok := index[id]
+		if !ok && id != "" {
+			// A branch missing from the branch list, as in dumps from
+			// other servers.
+			n = len(v.lines)
+			index[id] = n
+			v.lines = append(v.lines, line{id: id, from: *t.BranchFromThought})
+		}
+		v.lines[n].thoughts = append(v.lines[n].thoughts, i)
+	}
+	return v
+}
+
+func branchOf(t *thinking.ThoughtData) string {
+	if t.BranchFromThought != nil && t.BranchId != nil {
+		return *t.BranchId
+	}
+	return ""
+}
+
+// readKeys decodes keypresses from the terminal until it is closed.
+func readKeys(in io.Reader, keys chan<- key) {
+	defer close(keys)
+	buf := make([]byte, 32)
+	for {
+		n, err := in.Read(buf)
+		if err != nil {
+			return
+		}
+		for b := buf[:n]; len(b) > 0; {
+			k, size := decodeKey(b)
+			b = b[size:]
+			if k != keyNone {
+				keys <- k
+			}
+		}
+	}
+}
+
+func decodeKey(b []byte) (key, int) {
+	sequences := []struct {
+		seq string
+		key key
+	}{
+		{"\x1b[A", keyUp}, {"\x1b[B", keyDown}, {"\x1b[C", keyRight}, {"\x1b[D", keyLeft},
+		{"\x1bOA", keyUp}, {"\x1bOB", keyDown}, {"\x1bOC", keyRight}, {"\x1bOD", keyLeft},
+		{"\x1b[H", keyHome}, {"\x1b[F", keyEnd}, {"\x1b[1~", keyHome}, {"\x1b[4~", keyEnd},
+		{"\x1b[5~", keyPageUp}, {"\x1b[6~", keyPageDown}, {"\x1b[Z", keyLeft},
+	}
+	for _, s := range sequences {
+		if strings.HasPrefix(string(b), s.seq) {
+			return s.key, len(s.seq)
+		}
+	}
+	switch b[0] {
+	case 'q', 0x03, 0x04:
+		return keyQuit, 1
+	case 'k':
+		return keyUp, 1
+	case 'j':
+		return keyDown, 1
+	case 'h':
+		return keyLeft, 1
+	case 'l', '\t':
+		return keyRight, 1
+	case 'g':
+		return keyHome, 1
+	case 'G':
+		return keyEnd, 1
+	case 'b':
+		return keyPageUp, 1
+	case ' ':
+		return keyPageDown, 1
+	case 0x1b:
+		// An unknown escape sequence: drop it whole.
+		return keyNone, len(b)
+	}
+	return keyNone, 1
+}
+
+func (v *viewer) handle(k key) {
+	count := len(v.lines[v.current].thoughts)
+	switch k {
+	case keyUp:
+		v.selected--
+	case keyDown:
+		v.selected++
+	case keyHome:
+		v.selected = 0
+	case keyEnd:
+		v.selected = count - 1
+	case keyLeft, keyRight:
+		if k == keyLeft {
+			v.current = (v.current + len(v.lines) - 1) % len(v.lines)
+		} else {
+			v.current = (v.current + 1) % len(v.lines)
+		}
+		v.selected, v.offset = 0, 0
+		count = len(v.lines[v.current].thoughts)
+	case keyPageUp:
+		v.scroll = max(v.scroll-v.detailHeight(), 0)
+		return
+	case keyPageDown:
+		v.scroll += v.detailHeight()
+		return
+	}
+	v.selected = min(max(v.selected, 0), max(count-1, 0))
+	v.scroll = 0
+}
+
+// listHeight is the number of rows of the thought list; the revision pane
+// gets the rest, below a separator, between the tab bar and the help line.
+func (v *viewer) listHeight() int {
+	return max((v.height-3)/2, 1)
+}
+
+func (v *viewer) detailHeight() int {
+	return max(v.height-3-v.listHeight(), 1)
+}
+
+func (v *viewer) draw(w io.Writer) {
+	fmt.Fprint(w, "\x1b[H\x1b[2J")
+	rows := make([]string, 0, v.height)
+	rows = append(rows, v.tabs())
+
+	l := v.lines[v.current]
+	height := v.listHeight()
+	if v.selected < v.offset {
+		v.offset = v.selected
+	} else if v.selected >= v.offset+height {
+		v.offset = v.selected - height + 1
+	}
+	for i := v.offset; i < v.offset+height; i++ {
+		if i >= len(l.thoughts) {
+			rows = append(rows, "")
+			continue
+		}
+		rows = append(rows, v.listRow(i, l.thoughts[i]))
+	}
+	rows = append(rows, dimStyle.Sprint(strings.Repeat("─", v.width)))
+
+	detail := v.detail()
+	v.scroll = min(v.scroll, max(len(detail)-v.detailHeight(), 0))
+	for i := v.scroll; i < v.scroll+v.detailHeight(); i++ {
+		if i < len(detail) {
+			rows = append(rows, detail[i])
+		} else {
+			rows = append(rows, "")
+		}
+	}
+	rows = append(rows, dimStyle.Sprint(truncate("↑/↓ thoughts  ←/→ branches  space/b scroll  q quit", v.width)))
+	fmt.Fprint(w, strings.Join(rows, "\r\n"))
+}
+
+func (v *viewer) tabs() string {
+	var b strings.Builder
+	b.WriteString(titleStyle.Sprint("gothink"))
+	width := len("gothink")
+	for i, l := range v.lines {
+		label := " main "
+		if l.id != "" {
+			label = fmt.Sprintf(" %s ", thinking.Printable(l.id))
+			if l.status != "" {
+				label = fmt.Sprintf(" %s (%s) ", thinking.Printable(l.id), l.status)
+			}
+		}
+		width += runewidth.StringWidth(label) + 1
+		if width > v.width {
+			break
+		}
+		b.WriteString(" ")
+		if i == v.current {
+			b.WriteString(tabStyle.Sprint(label))
+		} else {
+			b.WriteString(label)
+		}
+	}
+	return b.String()
+}
+
+func (v *viewer) listRow(i, index int) string {
+	t := v.doc.Thoughts[index]
+	kind := ""
+	switch {
+	case t.MergedBranchId != nil:
+		kind = mergeStyle.Sprintf("%-9s", "merge")
+	case t.RevisesThought != nil:
+		kind = revisedStyle.Sprintf("%-9s", fmt.Sprintf("rev %d", *t.RevisesThought))
+	default:
+		kind = strings.Repeat(" ", 9)
+	}
+	prefix := fmt.Sprintf("%3d/%-3d ", t.ThoughtNumber, t.TotalThoughts)
+	text := truncate(thinking.Printable(strings.Join(strings.Fields(t.Thought), " ")), v.width-len(prefix)-12)
+	row := fmt.Sprintf("  %s%s %s", prefix, kind, text)
+	if i == v.selected {
+		return selectStyle.Sprint("›") + row[1:]
+	}
+	return row
+}
+
+// detail shows the selected thought in full, followed by a word diff
+// against the thought it revises.
+func (v *viewer) detail() []string {
+	l := v.lines[v.current]
+	if len(l.thoughts) == 0 {
+		return []string{dimStyle.Sprint("No thoughts on this line.")}
+	}
+	index := l.thoughts[v.selected]
+	t := v.doc.Thoughts[index]
+
+	header := fmt.Sprintf("Thought %d/%d", t.ThoughtNumber, t.TotalThoughts)
+	if l.id != "" {
+		header += fmt.Sprintf(" on branch %s (from thought %d)", l.id, l.from)
+	}
+	rows := []string{titleStyle.Sprint(truncate(thinking.Printable(header), v.width))}
+	rows = append(rows, v.thoughtText(index)...)
+
+	if t.MergedBranchId != nil {
+		rows = append(rows, "", mergeStyle.Sprint(truncate(thinking.Printable(fmt.Sprintf("Merges branch %s", *t.MergedBranchId)), v.width)))
+	}
+	if original := v.revised(index); original != nil {
+		rows = append(rows, "", revisedStyle.Sprint(truncate(fmt.Sprintf("Changes from thought %d:", original.ThoughtNumber), v.width)))
+		var words []word
+		for _, op := range thinking.WordDiff(original.Thought, t.Thought) {
+			style := (*color.Color)(nil)
+			switch op.Op {
+			case "-":
+				style = deleteStyle
+			case "+":
+				style = insertStyle
+			}
+			for _, text := range strings.Fields(thinking.Printable(op.Text)) {
+				words = append(words, word{text, style})
+			}
+		}
+		rows = append(rows, wrap(words, v.width)...)
+	}
+	return rows
+}
+
+// revised returns the thought the thought at index revises: the latest one
+// recorded before it with that number on the revised line.
+func (v *viewer) revised(index int) *thinking.ThoughtData {
+	t := v.doc.Thoughts[index]
+	if t.RevisesThought == nil {
+		return nil
+	}
+	scope := branchOf(&t)
+	if t.RevisesBranchId != nil {
+		scope = *t.RevisesBranchId
+	}
+	for i := index - 1; i >= 0; i-- {
+		candidate := &v.doc.Thoughts[i]
+		if candidate.ThoughtNumber == *t.RevisesThought && branchOf(candidate) == scope {
+			return candidate
+		}
+	}
+	return nil
+}
+
+// thoughtText renders the text of the thought at index to the width of the
+// screen.
+func (v *viewer) thoughtText(index int) []string {
+	if v.text == nil || v.textWidth != v.width {
+		v.text = make(map[int][]string)
+		v.textWidth = v.width
+	}
+	if _, ok := v.text[index]; !ok {
+		v.text[index] = thinking.MarkdownLines(thinking.Printable(v.doc.Thoughts[index].Thought), max(v.width, 1), v.markdown)
+	}
+	return v.text[index]
+}
+
+// word is a word of text with the style it is printed in, if any.
+type word struct {
+	text  string
+	style *color.Color
+}
+
+// wrap lays words out in rows of at most width columns, breaking words that
+// don't fit on a row of their own.
+func wrap(words []word, width int) []string {
+	width = max(width, 1)
+	var rows []string
+	var row strings.Builder
+	used := 0
+	flush := func() {
+		rows = append(rows, row.String())
+		row.Reset()
+		used = 0
+	}
+	for _, w := range words {
+		text := w.text
+		for runewidth.StringWidth(text) > width {
+			if used > 0 {
+				flush()
+			}
+			head := runewidth.Truncate(text, width, "")
+			if head == "" {
+				_, size := utf8.DecodeRuneInString(text)
+				head = text[:size]
+			}
+			row.WriteString(paint(w.style, head))
+			flush()
+			text = text[len(head):]
+		}
+		textWidth := runewidth.StringWidth(text)
+		if used > 0 && used+1+textWidth > width {
+			flush()
+		}
+		if used > 0 {
+			row.WriteString(" ")
+			used++
+		}
+		row.WriteString(paint(w.style, text))
+		used += textWidth
+	}
+	if used > 0 {
+		flush()
+	}
+	return rows
+}
+
+func paint(style *color.Color, text string) string {
+	if style == nil {
+		return text
+	}
+	return style.Sprint(text)
+}
+
+// truncate shortens text to at most width columns, marking the cut with an
+// ellipsis.
+func truncate(text string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	return runewidth.Truncate(text, width, "…")
+}